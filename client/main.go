@@ -1,10 +1,12 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"log"
 	"time"
 
+	"badies/kvclient"
 	pb "badies/proto/badiespb"
 
 	"google.golang.org/grpc"
@@ -35,7 +37,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Put failed: %v", err)
 	}
-	log.Printf("Put Success: %v", putRes.GetSuccess())
+	log.Printf("Put Success: %v, Acks: %d", putRes.GetSuccess(), putRes.GetAcks())
 
 	// 2. GET
 	log.Println("\n2. Testing GET operation...")
@@ -63,7 +65,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("UpdateValue failed: %v", err)
 	}
-	log.Printf("UpdateValue Success: %v", updValRes.GetSuccess())
+	log.Printf("UpdateValue Success: %v, version: %d", updValRes.GetSuccess(), updValRes.GetVersion())
 
 	// Verify UPDATE VALUE worked
 	log.Println("\n5. Verifying UPDATE VALUE...")
@@ -109,7 +111,8 @@ func main() {
 	if err != nil {
 		log.Fatalf("UpdateValue failed: %v", err)
 	}
-	log.Printf("UpdateValue with wrong old value - Success: %v (should be false)", updValRes2.GetSuccess())
+	log.Printf("UpdateValue with wrong old value - Success: %v (should be false), current value: %s",
+		updValRes2.GetSuccess(), updValRes2.GetCurrentValue())
 
 	// 9. Test UPDATE KEY with non-existent key (should fail)
 	log.Println("\n9. Testing UPDATE KEY with non-existent key...")
@@ -138,5 +141,23 @@ func main() {
 	}
 	log.Printf("After Delete - Key 'hi' found: %v (should be false)", getRes6.GetFound())
 
+	// 12. Binary keys and values through the v2 API
+	log.Println("\n12. Testing binary PUT/GET through the v2 API...")
+	kv, err := kvclient.Dial("localhost:50051")
+	if err != nil {
+		log.Fatalf("Dial failed: %v", err)
+	}
+	defer kv.Close()
+	binKey := []byte{'b', 'i', 'n', 0x00, 0xff}
+	binValue := []byte{0x00, 0x01, 0xfe, 0xff, 0xc3, 0x28}
+	if _, err := kv.Put(ctx, binKey, binValue); err != nil {
+		log.Fatalf("Binary put failed: %v", err)
+	}
+	gotValue, found, err := kv.Get(ctx, binKey)
+	if err != nil {
+		log.Fatalf("Binary get failed: %v", err)
+	}
+	log.Printf("Binary value round-tripped: %v (should be true)", found && bytes.Equal(gotValue, binValue))
+
 	log.Println("\n=== All tests completed! ===")
 }
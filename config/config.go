@@ -0,0 +1,141 @@
+// Package config fills command-line flags from a YAML cluster file and from
+// environment variables. A flag given on the command line wins over the
+// environment, which wins over the file; anything left keeps its default.
+package config
+
+import (
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Cluster is a cluster file. The server section and every node entry map
+// flag names of the server and node binaries to their values.
+type Cluster struct {
+	Server map[string]string `yaml:"server"`
+	Nodes  []Node            `yaml:"nodes"`
+}
+
+// Node is one storage node of a cluster file. ID, Address and Weight tell
+// the server where the node is; the remaining settings are node flags such
+// as path or the LevelDB options.
+type Node struct {
+	ID       string            `yaml:"id"`
+	Address  string            `yaml:"address"` // host:port the server dials
+	Weight   int               `yaml:"weight"`  // share of the hash ring, 0 for the default
+	Settings map[string]string `yaml:",inline"`
+}
+
+// Load reads and checks the cluster file at path
+func Load(path string) (*Cluster, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %v", path, err)
+	}
+	c := &Cluster{}
+	if err := yaml.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %v", path, err)
+	}
+	seen := make(map[string]bool, len(c.Nodes))
+	for i, node := range c.Nodes {
+		if node.ID == "" || node.Address == "" {
+			return nil, fmt.Errorf("invalid config %s: node %d needs an id and an address", path, i+1)
+		}
+		if seen[node.ID] {
+			return nil, fmt.Errorf("invalid config %s: node %s is listed twice", path, node.ID)
+		}
+		if node.Weight < 0 {
+			return nil, fmt.Errorf("invalid config %s: node %s has a negative weight", path, node.ID)
+		}
+		seen[node.ID] = true
+	}
+	return c, nil
+}
+
+// ServerSettings returns the server's flag values, with the node list
+// turned into its --nodes and --weights flags
+func (c *Cluster) ServerSettings() (map[string]string, error) {
+	settings := make(map[string]string, len(c.Server)+2)
+	for name, value := range c.Server {
+		settings[name] = value
+	}
+	if len(c.Nodes) == 0 {
+		return settings, nil
+	}
+	if _, ok := settings["nodes"]; ok {
+		return nil, fmt.Errorf("config sets both server.nodes and a nodes list")
+	}
+	var nodes, weights []string
+	for _, node := range c.Nodes {
+		nodes = append(nodes, node.ID+"="+node.Address)
+		if node.Weight > 0 {
+			weights = append(weights, node.ID+"="+strconv.Itoa(node.Weight))
+		}
+	}
+	settings["nodes"] = strings.Join(nodes, ",")
+	if len(weights) > 0 {
+		settings["weights"] = strings.Join(weights, ",")
+	}
+	return settings, nil
+}
+
+// NodeSettings returns the flag values of node id. Unless the entry sets a
+// port, the node listens on the port of its address.
+func (c *Cluster) NodeSettings(id string) (map[string]string, error) {
+	for _, node := range c.Nodes {
+		if node.ID != id {
+			continue
+		}
+		settings := make(map[string]string, len(node.Settings)+1)
+		for name, value := range node.Settings {
+			settings[name] = value
+		}
+		if _, ok := settings["port"]; !ok {
+			_, port, err := net.SplitHostPort(node.Address)
+			if err != nil {
+				return nil, fmt.Errorf("node %s: invalid address %q: %v", id, node.Address, err)
+			}
+			settings["port"] = port
+		}
+		return settings, nil
+	}
+	return nil, fmt.Errorf("node %s is not in the config", id)
+}
+
+// Apply sets every flag of fs that was not set yet, from the environment
+// variable envPrefix + the flag name in upper case with dashes turned into
+// underscores, or else from settings. It fails on settings naming no flag.
+func Apply(fs *flag.FlagSet, envPrefix string, settings map[string]string) error {
+	for name := range settings {
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown setting %q", name)
+		}
+	}
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	var err error
+	fs.VisitAll(func(f *flag.Flag) {
+		if err != nil || set[f.Name] {
+			return
+		}
+		env := envPrefix + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		if value, ok := os.LookupEnv(env); ok {
+			if serr := fs.Set(f.Name, value); serr != nil {
+				err = fmt.Errorf("invalid value %q for %s: %v", value, env, serr)
+			}
+			return
+		}
+		if value, ok := settings[f.Name]; ok {
+			if serr := fs.Set(f.Name, value); serr != nil {
+				err = fmt.Errorf("invalid value %q for setting %s: %v", value, f.Name, serr)
+			}
+		}
+	})
+	return err
+}
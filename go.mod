@@ -5,13 +5,19 @@ go 1.23
 toolchain go1.24.3
 
 require (
+	github.com/cespare/xxhash/v2 v2.3.0
+	github.com/syndtr/goleveldb v1.0.0
+	github.com/twmb/murmur3 v1.1.8
+	go.etcd.io/raft/v3 v3.6.0
 	google.golang.org/grpc v1.72.2
 	google.golang.org/protobuf v1.36.5
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
+	github.com/gogo/protobuf v1.3.2 // indirect
+	github.com/golang/protobuf v1.5.4 // indirect
 	github.com/golang/snappy v0.0.0-20180518054509-2e65f85255db // indirect
-	github.com/syndtr/goleveldb v1.0.0 // indirect
 	golang.org/x/net v0.35.0 // indirect
 	golang.org/x/sys v0.30.0 // indirect
 	golang.org/x/text v0.22.0 // indirect
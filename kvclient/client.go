@@ -0,0 +1,146 @@
+// Package kvclient is a Go client for the binary-safe badies.v2 KeyVal API.
+// Keys and values are byte slices and round-trip unchanged, so they may hold
+// protobufs, images or compressed data.
+package kvclient
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	v2pb "badies/proto/badiesv2pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client talks to a KeyVal server
+type Client struct {
+	conn *grpc.ClientConn
+	rpc  v2pb.KeyValClient
+}
+
+// Dial connects to the KeyVal server at addr. Without options the
+// connection is unencrypted.
+func Dial(addr string, options ...grpc.DialOption) (*Client, error) {
+	if len(options) == 0 {
+		options = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.NewClient(addr, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %v", addr, err)
+	}
+	return &Client{conn: conn, rpc: v2pb.NewKeyValClient(conn)}, nil
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// RPC returns the underlying gRPC client for calls without a helper here,
+// such as batches, Watch and History
+func (c *Client) RPC() v2pb.KeyValClient {
+	return c.rpc
+}
+
+// Put stores value under key and returns the revision it was written at
+func (c *Client) Put(ctx context.Context, key, value []byte) (uint64, error) {
+	return c.PutTTL(ctx, key, value, 0)
+}
+
+// PutTTL is Put for a key that expires after ttl; 0 means never
+func (c *Client) PutTTL(ctx context.Context, key, value []byte, ttl time.Duration) (uint64, error) {
+	resp, err := c.rpc.Put(ctx, &v2pb.PutRequest{Key: key, Value: value, TtlMs: ttl.Milliseconds()})
+	if err != nil {
+		return 0, err
+	}
+	return resp.GetRevision(), nil
+}
+
+// Get returns the value stored under key. A missing key is reported as
+// found == false without an error.
+func (c *Client) Get(ctx context.Context, key []byte) (value []byte, found bool, err error) {
+	return c.GetAt(ctx, key, 0)
+}
+
+// GetAt returns the value key had at revision, 0 for the latest
+func (c *Client) GetAt(ctx context.Context, key []byte, revision uint64) (value []byte, found bool, err error) {
+	resp, err := c.rpc.Get(ctx, &v2pb.GetRequest{Key: key, Revision: revision})
+	if err != nil {
+		return nil, false, err
+	}
+	return resp.GetValue(), resp.GetFound(), nil
+}
+
+// Delete removes key and returns the revision of the delete
+func (c *Client) Delete(ctx context.Context, key []byte) (uint64, error) {
+	resp, err := c.rpc.Delete(ctx, &v2pb.DeleteRequest{Key: key})
+	if err != nil {
+		return 0, err
+	}
+	if !resp.GetSuccess() {
+		return 0, fmt.Errorf("delete of key %q did not reach its write quorum", key)
+	}
+	return resp.GetRevision(), nil
+}
+
+// CompareAndSwap replaces the value of key with newValue if it currently
+// holds oldValue and reports whether it did
+func (c *Client) CompareAndSwap(ctx context.Context, key, oldValue, newValue []byte) (bool, error) {
+	resp, err := c.rpc.UpdateValue(ctx, &v2pb.UpdateValueRequest{Key: key, OldValue: oldValue, NewValue: newValue})
+	if err != nil {
+		return false, err
+	}
+	return resp.GetSuccess(), nil
+}
+
+// Rename moves the value of oldKey to newKey and reports whether it did.
+// With failIfExists an existing newKey is left alone.
+func (c *Client) Rename(ctx context.Context, oldKey, newKey []byte, failIfExists bool) (bool, error) {
+	resp, err := c.rpc.UpdateKey(ctx, &v2pb.UpdateKeyRequest{OldKey: oldKey, NewKey: newKey, FailIfExists: failIfExists})
+	if err != nil {
+		return false, err
+	}
+	return resp.GetSuccess(), nil
+}
+
+// ScanOptions selects the keys of a Scan, as in v2pb.ScanRequest
+type ScanOptions struct {
+	Start   []byte // first key, empty for the beginning
+	End     []byte // key after the last one, empty for the end
+	Prefix  []byte
+	Limit   int32 // 0 for no limit
+	Reverse bool
+}
+
+// Scan calls fn for every pair selected by opts, in key order, until fn
+// returns an error
+func (c *Client) Scan(ctx context.Context, opts ScanOptions, fn func(key, value []byte) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.rpc.Scan(ctx, &v2pb.ScanRequest{
+		StartKey: opts.Start,
+		EndKey:   opts.End,
+		Prefix:   opts.Prefix,
+		Limit:    opts.Limit,
+		Reverse:  opts.Reverse,
+	})
+	if err != nil {
+		return err
+	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(resp.GetKey(), resp.GetValue()); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Package meta is the coordinator's durable metadata store. It holds state
+// that must survive a coordinator crash, such as logged intents, in a local
+// LevelDB. Every write is synced to disk before it returns.
+package meta
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+var syncWrite = &opt.WriteOptions{Sync: true}
+
+// Store is a synced key-value store for coordinator metadata
+type Store struct {
+	db *leveldb.DB
+}
+
+// Open opens or creates the metadata store at path
+func Open(path string) (*Store, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open metadata store at %s: %v", path, err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the store
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Put durably stores value under key
+func (s *Store) Put(key string, value []byte) error {
+	return s.db.Put([]byte(key), value, syncWrite)
+}
+
+// Get returns the value stored under key. A missing key is reported as
+// found == false without an error.
+func (s *Store) Get(key string) ([]byte, bool, error) {
+	value, err := s.db.Get([]byte(key), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+// Delete durably removes key
+func (s *Store) Delete(key string) error {
+	return s.db.Delete([]byte(key), syncWrite)
+}
+
+// Scan calls fn for every key starting with prefix, in key order, until fn
+// returns an error
+func (s *Store) Scan(prefix string, fn func(key string, value []byte) error) error {
+	iter := s.db.NewIterator(util.BytesPrefix([]byte(prefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		if err := fn(string(iter.Key()), append([]byte(nil), iter.Value()...)); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"badies/config"
+	pb "badies/proto/badiespb"
+	"badies/router"
+	"badies/storage"
+
+	"github.com/syndtr/goleveldb/leveldb/filter"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// defaultShutdownTimeout bounds how long a node waits for in-flight requests
+// before closing its databases
+const defaultShutdownTimeout = 10 * time.Second
+
+func main() {
+	configPath := flag.String("config", os.Getenv("BADIES_NODE_CONFIG"), "YAML cluster file to take this node's settings from; flags and BADIES_NODE_* environment variables override it")
+	nodeID := flag.String("id", "node1", "ID of this storage node")
+	port := flag.Int("port", 5001, "port to serve the StorageNode service on")
+	path := flag.String("path", "", "LevelDB directory (defaults to dbs/<id>)")
+	maxHints := flag.Int("max-hints", storage.DefaultMaxHints, "maximum number of hints held for unreachable replicas")
+	hintTTL := flag.Duration("hint-ttl", storage.DefaultHintTTL, "how long a hint is kept before it is dropped")
+	historyRetention := flag.Duration("history-retention", storage.DefaultHistoryRetention, "how long replaced versions stay readable, 0 to keep them forever")
+	gossipAddr := flag.String("gossip-addr", "", "address other members reach this node on (default localhost:<port>)")
+	gossipSeeds := flag.String("gossip-seeds", "", "comma separated addresses of members to join gossip through, such as the server")
+	probeInterval := flag.Duration("probe-interval", router.DefaultProbeInterval, "how often gossip probes a member")
+	suspicionTimeout := flag.Duration("suspicion-timeout", router.DefaultSuspicionTimeout, "how long a suspected member has to refute before it is declared dead")
+	healthCheckInterval := flag.Duration("health-check-interval", storage.DefaultHealthCheckInterval, "how often the node checks that its databases can be read and written")
+	shutdownTimeout := flag.Duration("shutdown-timeout", defaultShutdownTimeout, "how long in-flight requests may run after SIGINT or SIGTERM before they are cancelled")
+	writeBufferMB := flag.Int("leveldb-write-buffer-mb", 0, "LevelDB memtable size in MiB, 0 for the LevelDB default")
+	blockCacheMB := flag.Int("leveldb-block-cache-mb", 0, "LevelDB block cache size in MiB, 0 for the LevelDB default")
+	openFiles := flag.Int("leveldb-open-files", 0, "number of table files LevelDB keeps open, 0 for the LevelDB default")
+	compression := flag.String("leveldb-compression", "snappy", "LevelDB block compression: snappy or none")
+	bloomBits := flag.Int("leveldb-bloom-bits", 0, "bits per key of a LevelDB bloom filter, 0 for none")
+	flag.Parse()
+
+	// The environment may pick the node ID, so it is applied before the
+	// node's entry is looked up in the cluster file
+	if err := config.Apply(flag.CommandLine, "BADIES_NODE_", nil); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	if *configPath != "" {
+		cluster, err := config.Load(*configPath)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		settings, err := cluster.NodeSettings(*nodeID)
+		if err != nil {
+			log.Fatalf("Invalid config %s: %v", *configPath, err)
+		}
+		if err := config.Apply(flag.CommandLine, "BADIES_NODE_", settings); err != nil {
+			log.Fatalf("Invalid config %s: %v", *configPath, err)
+		}
+	}
+
+	options := &opt.Options{
+		WriteBuffer:            *writeBufferMB * opt.MiB,
+		BlockCacheCapacity:     *blockCacheMB * opt.MiB,
+		OpenFilesCacheCapacity: *openFiles,
+	}
+	switch *compression {
+	case "snappy":
+		options.Compression = opt.SnappyCompression
+	case "none":
+		options.Compression = opt.NoCompression
+	default:
+		log.Fatalf("--leveldb-compression must be snappy or none, got %q", *compression)
+	}
+	if *bloomBits > 0 {
+		options.Filter = filter.NewBloomFilter(*bloomBits)
+	}
+
+	dbPath := *path
+	if dbPath == "" {
+		dbPath = filepath.Join("dbs", *nodeID)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	store, err := storage.NewServer(*nodeID, dbPath, options)
+	if err != nil {
+		log.Fatalf("Failed to start node %s: %v", *nodeID, err)
+	}
+	store.Hints.MaxHints = *maxHints
+	store.Hints.TTL = *hintTTL
+	store.History.Retention = *historyRetention
+	go store.RunHistoryCompaction(ctx)
+
+	addr := fmt.Sprintf(":%d", *port)
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	grpcServer := grpc.NewServer(
+		grpc.MaxRecvMsgSize(storage.MaxMessageSize),
+		grpc.UnaryInterceptor(store.UnaryEpochInterceptor),
+		grpc.StreamInterceptor(store.StreamEpochInterceptor),
+	)
+	pb.RegisterStorageNodeServer(grpcServer, store)
+
+	// The standard health service reports whether the databases work, so
+	// the server stops sending requests to a node whose disk is failing
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	go store.ReportHealth(ctx, healthServer, *healthCheckInterval)
+
+	// Nodes take part in gossip so that failures are detected by every
+	// member, not only by the server
+	if *gossipAddr == "" {
+		*gossipAddr = fmt.Sprintf("localhost:%d", *port)
+	}
+	gossipTransport := router.NewGRPCTransport()
+	gossip := router.NewGossip(*nodeID, *gossipAddr, gossipTransport)
+	gossip.ProbeInterval = *probeInterval
+	gossip.SuspicionTimeout = *suspicionTimeout
+	pb.RegisterGossipServer(grpcServer, gossip)
+	go func() {
+		var seeds []string
+		for _, seed := range strings.Split(*gossipSeeds, ",") {
+			if seed = strings.TrimSpace(seed); seed != "" {
+				seeds = append(seeds, seed)
+			}
+		}
+		if len(seeds) > 0 && gossip.Join(ctx, seeds...) == 0 {
+			log.Printf("Could not reach any gossip seed, waiting to be probed")
+		}
+		gossip.Run(ctx)
+	}()
+
+	log.Printf("Storage node %s listening on %s", *nodeID, addr)
+	exitCode := 0
+	if err := serve(ctx, stop, grpcServer, lis, *shutdownTimeout); err != nil {
+		log.Printf("Shutdown: %v", err)
+		exitCode = 1
+	}
+	gossipTransport.Close()
+	// Flushes and releases the LevelDB locks, so the node can be restarted
+	// on the same path
+	if err := store.Close(); err != nil {
+		log.Printf("Failed to close node %s: %v", *nodeID, err)
+		exitCode = 1
+	}
+	os.Exit(exitCode)
+}
+
+// serve runs grpcServer until serving fails or ctx is cancelled by a
+// signal. It then stops taking new RPCs, waits up to timeout for running
+// ones to finish and cancels the rest. stop restores the default signal
+// handling, so a second signal kills the node at once.
+func serve(ctx context.Context, stop func(), grpcServer *grpc.Server, lis net.Listener, timeout time.Duration) error {
+	served := make(chan error, 1)
+	go func() { served <- grpcServer.Serve(lis) }()
+	select {
+	case err := <-served:
+		return fmt.Errorf("failed to serve: %v", err)
+	case <-ctx.Done():
+	}
+	stop()
+
+	log.Printf("Shutting down, waiting up to %v for in-flight requests", timeout)
+	drained := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(drained)
+	}()
+	select {
+	case <-drained:
+		return nil
+	case <-time.After(timeout):
+		grpcServer.Stop()
+		return fmt.Errorf("in-flight requests still running after %v were cancelled", timeout)
+	}
+}
@@ -21,9 +21,301 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type WatchEvent_Type int32
+
+const (
+	WatchEvent_PUT    WatchEvent_Type = 0
+	WatchEvent_DELETE WatchEvent_Type = 1
+)
+
+// Enum value maps for WatchEvent_Type.
+var (
+	WatchEvent_Type_name = map[int32]string{
+		0: "PUT",
+		1: "DELETE",
+	}
+	WatchEvent_Type_value = map[string]int32{
+		"PUT":    0,
+		"DELETE": 1,
+	}
+)
+
+func (x WatchEvent_Type) Enum() *WatchEvent_Type {
+	p := new(WatchEvent_Type)
+	*p = x
+	return p
+}
+
+func (x WatchEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WatchEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_badies_proto_enumTypes[0].Descriptor()
+}
+
+func (WatchEvent_Type) Type() protoreflect.EnumType {
+	return &file_badies_proto_enumTypes[0]
+}
+
+func (x WatchEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WatchEvent_Type.Descriptor instead.
+func (WatchEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{21, 0}
+}
+
+type TxnCompare_Target int32
+
+const (
+	TxnCompare_VALUE   TxnCompare_Target = 0
+	TxnCompare_VERSION TxnCompare_Target = 1
+	TxnCompare_EXISTS  TxnCompare_Target = 2
+	TxnCompare_MISSING TxnCompare_Target = 3
+)
+
+// Enum value maps for TxnCompare_Target.
+var (
+	TxnCompare_Target_name = map[int32]string{
+		0: "VALUE",
+		1: "VERSION",
+		2: "EXISTS",
+		3: "MISSING",
+	}
+	TxnCompare_Target_value = map[string]int32{
+		"VALUE":   0,
+		"VERSION": 1,
+		"EXISTS":  2,
+		"MISSING": 3,
+	}
+)
+
+func (x TxnCompare_Target) Enum() *TxnCompare_Target {
+	p := new(TxnCompare_Target)
+	*p = x
+	return p
+}
+
+func (x TxnCompare_Target) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TxnCompare_Target) Descriptor() protoreflect.EnumDescriptor {
+	return file_badies_proto_enumTypes[1].Descriptor()
+}
+
+func (TxnCompare_Target) Type() protoreflect.EnumType {
+	return &file_badies_proto_enumTypes[1]
+}
+
+func (x TxnCompare_Target) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TxnCompare_Target.Descriptor instead.
+func (TxnCompare_Target) EnumDescriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{25, 0}
+}
+
+type TxnOp_Type int32
+
+const (
+	TxnOp_PUT    TxnOp_Type = 0
+	TxnOp_DELETE TxnOp_Type = 1
+)
+
+// Enum value maps for TxnOp_Type.
+var (
+	TxnOp_Type_name = map[int32]string{
+		0: "PUT",
+		1: "DELETE",
+	}
+	TxnOp_Type_value = map[string]int32{
+		"PUT":    0,
+		"DELETE": 1,
+	}
+)
+
+func (x TxnOp_Type) Enum() *TxnOp_Type {
+	p := new(TxnOp_Type)
+	*p = x
+	return p
+}
+
+func (x TxnOp_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TxnOp_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_badies_proto_enumTypes[2].Descriptor()
+}
+
+func (TxnOp_Type) Type() protoreflect.EnumType {
+	return &file_badies_proto_enumTypes[2]
+}
+
+func (x TxnOp_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TxnOp_Type.Descriptor instead.
+func (TxnOp_Type) EnumDescriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{26, 0}
+}
+
+type NodeInfo_State int32
+
+const (
+	NodeInfo_ACTIVE   NodeInfo_State = 0
+	NodeInfo_DRAINING NodeInfo_State = 1 // off the ring, its keys are being moved away
+	NodeInfo_DOWN     NodeInfo_State = 2 // on the ring, but gossip declared it dead
+)
+
+// Enum value maps for NodeInfo_State.
+var (
+	NodeInfo_State_name = map[int32]string{
+		0: "ACTIVE",
+		1: "DRAINING",
+		2: "DOWN",
+	}
+	NodeInfo_State_value = map[string]int32{
+		"ACTIVE":   0,
+		"DRAINING": 1,
+		"DOWN":     2,
+	}
+)
+
+func (x NodeInfo_State) Enum() *NodeInfo_State {
+	p := new(NodeInfo_State)
+	*p = x
+	return p
+}
+
+func (x NodeInfo_State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NodeInfo_State) Descriptor() protoreflect.EnumDescriptor {
+	return file_badies_proto_enumTypes[3].Descriptor()
+}
+
+func (NodeInfo_State) Type() protoreflect.EnumType {
+	return &file_badies_proto_enumTypes[3]
+}
+
+func (x NodeInfo_State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NodeInfo_State.Descriptor instead.
+func (NodeInfo_State) EnumDescriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{74, 0}
+}
+
+type NodeHealth_Circuit int32
+
+const (
+	NodeHealth_CLOSED    NodeHealth_Circuit = 0 // calls go through
+	NodeHealth_OPEN      NodeHealth_Circuit = 1 // calls fail at once until a health probe succeeds
+	NodeHealth_HALF_OPEN NodeHealth_Circuit = 2 // the node is being probed
+)
+
+// Enum value maps for NodeHealth_Circuit.
+var (
+	NodeHealth_Circuit_name = map[int32]string{
+		0: "CLOSED",
+		1: "OPEN",
+		2: "HALF_OPEN",
+	}
+	NodeHealth_Circuit_value = map[string]int32{
+		"CLOSED":    0,
+		"OPEN":      1,
+		"HALF_OPEN": 2,
+	}
+)
+
+func (x NodeHealth_Circuit) Enum() *NodeHealth_Circuit {
+	p := new(NodeHealth_Circuit)
+	*p = x
+	return p
+}
+
+func (x NodeHealth_Circuit) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (NodeHealth_Circuit) Descriptor() protoreflect.EnumDescriptor {
+	return file_badies_proto_enumTypes[4].Descriptor()
+}
+
+func (NodeHealth_Circuit) Type() protoreflect.EnumType {
+	return &file_badies_proto_enumTypes[4]
+}
+
+func (x NodeHealth_Circuit) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use NodeHealth_Circuit.Descriptor instead.
+func (NodeHealth_Circuit) EnumDescriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{75, 0}
+}
+
+type GossipMember_State int32
+
+const (
+	GossipMember_ALIVE   GossipMember_State = 0
+	GossipMember_SUSPECT GossipMember_State = 1
+	GossipMember_DEAD    GossipMember_State = 2
+)
+
+// Enum value maps for GossipMember_State.
+var (
+	GossipMember_State_name = map[int32]string{
+		0: "ALIVE",
+		1: "SUSPECT",
+		2: "DEAD",
+	}
+	GossipMember_State_value = map[string]int32{
+		"ALIVE":   0,
+		"SUSPECT": 1,
+		"DEAD":    2,
+	}
+)
+
+func (x GossipMember_State) Enum() *GossipMember_State {
+	p := new(GossipMember_State)
+	*p = x
+	return p
+}
+
+func (x GossipMember_State) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GossipMember_State) Descriptor() protoreflect.EnumDescriptor {
+	return file_badies_proto_enumTypes[5].Descriptor()
+}
+
+func (GossipMember_State) Type() protoreflect.EnumType {
+	return &file_badies_proto_enumTypes[5]
+}
+
+func (x GossipMember_State) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GossipMember_State.Descriptor instead.
+func (GossipMember_State) EnumDescriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{77, 0}
+}
+
 type GetRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Revision      uint64                 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"` // read the value the key had at this revision, 0 for the latest
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -65,10 +357,18 @@ func (x *GetRequest) GetKey() string {
 	return ""
 }
 
+func (x *GetRequest) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
 type PutRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
 	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	TtlMs         int64                  `protobuf:"varint,3,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"` // the key expires after this many milliseconds, 0 for never
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -117,6 +417,13 @@ func (x *PutRequest) GetValue() string {
 	return ""
 }
 
+func (x *PutRequest) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
 type DeleteRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -165,6 +472,7 @@ type UpdateKeyRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OldKey        string                 `protobuf:"bytes,1,opt,name=old_key,json=oldKey,proto3" json:"old_key,omitempty"`
 	NewKey        string                 `protobuf:"bytes,2,opt,name=new_key,json=newKey,proto3" json:"new_key,omitempty"`
+	FailIfExists  bool                   `protobuf:"varint,3,opt,name=fail_if_exists,json=failIfExists,proto3" json:"fail_if_exists,omitempty"` // refuse to overwrite an existing new_key
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -213,13 +521,24 @@ func (x *UpdateKeyRequest) GetNewKey() string {
 	return ""
 }
 
+func (x *UpdateKeyRequest) GetFailIfExists() bool {
+	if x != nil {
+		return x.FailIfExists
+	}
+	return false
+}
+
+// UpdateValueRequest is an atomic compare-and-swap. The swap happens if the
+// key currently holds old_value or, when expected_version is set, if it is
+// at that version.
 type UpdateValueRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
-	OldValue      string                 `protobuf:"bytes,2,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
-	NewValue      string                 `protobuf:"bytes,3,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Key             string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	OldValue        string                 `protobuf:"bytes,2,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	NewValue        string                 `protobuf:"bytes,3,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *UpdateValueRequest) Reset() {
@@ -273,28 +592,41 @@ func (x *UpdateValueRequest) GetNewValue() string {
 	return ""
 }
 
-type GetResponse struct {
+func (x *UpdateValueRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+// ScanRequest selects the keys in [start_key, end_key) that begin with
+// prefix; empty bounds are open. At most limit pairs are returned, 0 means
+// no limit.
+type ScanRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
-	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	StartKey      string                 `protobuf:"bytes,1,opt,name=start_key,json=startKey,proto3" json:"start_key,omitempty"`
+	EndKey        string                 `protobuf:"bytes,2,opt,name=end_key,json=endKey,proto3" json:"end_key,omitempty"`
+	Prefix        string                 `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Reverse       bool                   `protobuf:"varint,5,opt,name=reverse,proto3" json:"reverse,omitempty"` // descending key order
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetResponse) Reset() {
-	*x = GetResponse{}
+func (x *ScanRequest) Reset() {
+	*x = ScanRequest{}
 	mi := &file_badies_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetResponse) String() string {
+func (x *ScanRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetResponse) ProtoMessage() {}
+func (*ScanRequest) ProtoMessage() {}
 
-func (x *GetResponse) ProtoReflect() protoreflect.Message {
+func (x *ScanRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_badies_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -306,46 +638,68 @@ func (x *GetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
-func (*GetResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ScanRequest.ProtoReflect.Descriptor instead.
+func (*ScanRequest) Descriptor() ([]byte, []int) {
 	return file_badies_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *GetResponse) GetValue() string {
+func (x *ScanRequest) GetStartKey() string {
 	if x != nil {
-		return x.Value
+		return x.StartKey
 	}
 	return ""
 }
 
-func (x *GetResponse) GetFound() bool {
+func (x *ScanRequest) GetEndKey() string {
 	if x != nil {
-		return x.Found
+		return x.EndKey
+	}
+	return ""
+}
+
+func (x *ScanRequest) GetPrefix() string {
+	if x != nil {
+		return x.Prefix
+	}
+	return ""
+}
+
+func (x *ScanRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ScanRequest) GetReverse() bool {
+	if x != nil {
+		return x.Reverse
 	}
 	return false
 }
 
-type PutResponse struct {
+type ScanResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PutResponse) Reset() {
-	*x = PutResponse{}
+func (x *ScanResponse) Reset() {
+	*x = ScanResponse{}
 	mi := &file_badies_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PutResponse) String() string {
+func (x *ScanResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PutResponse) ProtoMessage() {}
+func (*ScanResponse) ProtoMessage() {}
 
-func (x *PutResponse) ProtoReflect() protoreflect.Message {
+func (x *ScanResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_badies_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -357,39 +711,47 @@ func (x *PutResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PutResponse.ProtoReflect.Descriptor instead.
-func (*PutResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ScanResponse.ProtoReflect.Descriptor instead.
+func (*ScanResponse) Descriptor() ([]byte, []int) {
 	return file_badies_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *PutResponse) GetSuccess() bool {
+func (x *ScanResponse) GetKey() string {
 	if x != nil {
-		return x.Success
+		return x.Key
 	}
-	return false
+	return ""
 }
 
-type DeleteResponse struct {
+func (x *ScanResponse) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type KeyValue struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteResponse) Reset() {
-	*x = DeleteResponse{}
+func (x *KeyValue) Reset() {
+	*x = KeyValue{}
 	mi := &file_badies_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteResponse) String() string {
+func (x *KeyValue) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteResponse) ProtoMessage() {}
+func (*KeyValue) ProtoMessage() {}
 
-func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+func (x *KeyValue) ProtoReflect() protoreflect.Message {
 	mi := &file_badies_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -401,39 +763,46 @@ func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
-func (*DeleteResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use KeyValue.ProtoReflect.Descriptor instead.
+func (*KeyValue) Descriptor() ([]byte, []int) {
 	return file_badies_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *DeleteResponse) GetSuccess() bool {
+func (x *KeyValue) GetKey() string {
 	if x != nil {
-		return x.Success
+		return x.Key
 	}
-	return false
+	return ""
 }
 
-type UpdateKeyResponse struct {
+func (x *KeyValue) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+type BatchPutRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Entries       []*KeyValue            `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateKeyResponse) Reset() {
-	*x = UpdateKeyResponse{}
+func (x *BatchPutRequest) Reset() {
+	*x = BatchPutRequest{}
 	mi := &file_badies_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateKeyResponse) String() string {
+func (x *BatchPutRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateKeyResponse) ProtoMessage() {}
+func (*BatchPutRequest) ProtoMessage() {}
 
-func (x *UpdateKeyResponse) ProtoReflect() protoreflect.Message {
+func (x *BatchPutRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_badies_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -445,39 +814,43 @@ func (x *UpdateKeyResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateKeyResponse.ProtoReflect.Descriptor instead.
-func (*UpdateKeyResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use BatchPutRequest.ProtoReflect.Descriptor instead.
+func (*BatchPutRequest) Descriptor() ([]byte, []int) {
 	return file_badies_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *UpdateKeyResponse) GetSuccess() bool {
+func (x *BatchPutRequest) GetEntries() []*KeyValue {
 	if x != nil {
-		return x.Success
+		return x.Entries
 	}
-	return false
+	return nil
 }
 
-type UpdateValueResponse struct {
+// KeyResult is the outcome of one write in a batch
+type KeyResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Acks          int32                  `protobuf:"varint,3,opt,name=acks,proto3" json:"acks,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"` // why the write failed
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateValueResponse) Reset() {
-	*x = UpdateValueResponse{}
+func (x *KeyResult) Reset() {
+	*x = KeyResult{}
 	mi := &file_badies_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateValueResponse) String() string {
+func (x *KeyResult) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateValueResponse) ProtoMessage() {}
+func (*KeyResult) ProtoMessage() {}
 
-func (x *UpdateValueResponse) ProtoReflect() protoreflect.Message {
+func (x *KeyResult) ProtoReflect() protoreflect.Message {
 	mi := &file_badies_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -489,56 +862,5664 @@ func (x *UpdateValueResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateValueResponse.ProtoReflect.Descriptor instead.
-func (*UpdateValueResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use KeyResult.ProtoReflect.Descriptor instead.
+func (*KeyResult) Descriptor() ([]byte, []int) {
 	return file_badies_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *UpdateValueResponse) GetSuccess() bool {
+func (x *KeyResult) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *KeyResult) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-var File_badies_proto protoreflect.FileDescriptor
+func (x *KeyResult) GetAcks() int32 {
+	if x != nil {
+		return x.Acks
+	}
+	return 0
+}
 
-const file_badies_proto_rawDesc = "" +
-	"\n" +
-	"\fbadies.proto\x12\x06badies\"\x1e\n" +
-	"\n" +
-	"GetRequest\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\"4\n" +
+func (x *KeyResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchPutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*KeyResult           `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchPutResponse) Reset() {
+	*x = BatchPutResponse{}
+	mi := &file_badies_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchPutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchPutResponse) ProtoMessage() {}
+
+func (x *BatchPutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchPutResponse.ProtoReflect.Descriptor instead.
+func (*BatchPutResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchPutResponse) GetResults() []*KeyResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type BatchGetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetRequest) Reset() {
+	*x = BatchGetRequest{}
+	mi := &file_badies_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetRequest) ProtoMessage() {}
+
+func (x *BatchGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchGetRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+// GetResult is the outcome of one read in a batch
+type GetResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Found         bool                   `protobuf:"varint,3,opt,name=found,proto3" json:"found,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"` // why the read failed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetResult) Reset() {
+	*x = GetResult{}
+	mi := &file_badies_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetResult) ProtoMessage() {}
+
+func (x *GetResult) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetResult.ProtoReflect.Descriptor instead.
+func (*GetResult) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetResult) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *GetResult) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *GetResult) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type BatchGetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*GetResult           `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetResponse) Reset() {
+	*x = BatchGetResponse{}
+	mi := &file_badies_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetResponse) ProtoMessage() {}
+
+func (x *BatchGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BatchGetResponse) GetResults() []*GetResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type BatchDeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          []string               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteRequest) Reset() {
+	*x = BatchDeleteRequest{}
+	mi := &file_badies_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteRequest) ProtoMessage() {}
+
+func (x *BatchDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteRequest.ProtoReflect.Descriptor instead.
+func (*BatchDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BatchDeleteRequest) GetKeys() []string {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type BatchDeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*KeyResult           `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchDeleteResponse) Reset() {
+	*x = BatchDeleteResponse{}
+	mi := &file_badies_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchDeleteResponse) ProtoMessage() {}
+
+func (x *BatchDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchDeleteResponse.ProtoReflect.Descriptor instead.
+func (*BatchDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BatchDeleteResponse) GetResults() []*KeyResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type ExpireRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	TtlMs         int64                  `protobuf:"varint,2,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpireRequest) Reset() {
+	*x = ExpireRequest{}
+	mi := &file_badies_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpireRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpireRequest) ProtoMessage() {}
+
+func (x *ExpireRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpireRequest.ProtoReflect.Descriptor instead.
+func (*ExpireRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ExpireRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *ExpireRequest) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+type ExpireResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"` // false if the key does not exist
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExpireResponse) Reset() {
+	*x = ExpireResponse{}
+	mi := &file_badies_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExpireResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExpireResponse) ProtoMessage() {}
+
+func (x *ExpireResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExpireResponse.ProtoReflect.Descriptor instead.
+func (*ExpireResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ExpireResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type PersistRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PersistRequest) Reset() {
+	*x = PersistRequest{}
+	mi := &file_badies_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PersistRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PersistRequest) ProtoMessage() {}
+
+func (x *PersistRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PersistRequest.ProtoReflect.Descriptor instead.
+func (*PersistRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PersistRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+type PersistResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"` // false if the key does not exist
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PersistResponse) Reset() {
+	*x = PersistResponse{}
+	mi := &file_badies_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PersistResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PersistResponse) ProtoMessage() {}
+
+func (x *PersistResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PersistResponse.ProtoReflect.Descriptor instead.
+func (*PersistResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PersistResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type WatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Prefix        bool                   `protobuf:"varint,2,opt,name=prefix,proto3" json:"prefix,omitempty"`                                    // watch every key starting with key
+	StartRevision uint64                 `protobuf:"varint,3,opt,name=start_revision,json=startRevision,proto3" json:"start_revision,omitempty"` // replay events from this revision on, 0 for new events only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_badies_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *WatchRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *WatchRequest) GetPrefix() bool {
+	if x != nil {
+		return x.Prefix
+	}
+	return false
+}
+
+func (x *WatchRequest) GetStartRevision() uint64 {
+	if x != nil {
+		return x.StartRevision
+	}
+	return 0
+}
+
+type WatchEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          WatchEvent_Type        `protobuf:"varint,1,opt,name=type,proto3,enum=badies.WatchEvent_Type" json:"type,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Revision      uint64                 `protobuf:"varint,4,opt,name=revision,proto3" json:"revision,omitempty"` // increases with every event; resume with the last seen revision + 1
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEvent) Reset() {
+	*x = WatchEvent{}
+	mi := &file_badies_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEvent) ProtoMessage() {}
+
+func (x *WatchEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEvent.ProtoReflect.Descriptor instead.
+func (*WatchEvent) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *WatchEvent) GetType() WatchEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return WatchEvent_PUT
+}
+
+func (x *WatchEvent) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *WatchEvent) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type HistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // 0 for all kept versions
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HistoryRequest) Reset() {
+	*x = HistoryRequest{}
+	mi := &file_badies_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryRequest) ProtoMessage() {}
+
+func (x *HistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryRequest.ProtoReflect.Descriptor instead.
+func (*HistoryRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *HistoryRequest) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *HistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type KeyVersion struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Revision      uint64                 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	Deleted       bool                   `protobuf:"varint,3,opt,name=deleted,proto3" json:"deleted,omitempty"`                      // the key was deleted at this revision
+	ExpiresAt     int64                  `protobuf:"varint,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // unix nanoseconds, 0 if the version never expires
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KeyVersion) Reset() {
+	*x = KeyVersion{}
+	mi := &file_badies_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KeyVersion) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KeyVersion) ProtoMessage() {}
+
+func (x *KeyVersion) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KeyVersion.ProtoReflect.Descriptor instead.
+func (*KeyVersion) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *KeyVersion) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *KeyVersion) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *KeyVersion) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+func (x *KeyVersion) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type HistoryResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Versions          []*KeyVersion          `protobuf:"bytes,1,rep,name=versions,proto3" json:"versions,omitempty"`                                             // newest first
+	CompactedRevision uint64                 `protobuf:"varint,2,opt,name=compacted_revision,json=compactedRevision,proto3" json:"compacted_revision,omitempty"` // reads below this revision fail
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *HistoryResponse) Reset() {
+	*x = HistoryResponse{}
+	mi := &file_badies_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HistoryResponse) ProtoMessage() {}
+
+func (x *HistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HistoryResponse.ProtoReflect.Descriptor instead.
+func (*HistoryResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *HistoryResponse) GetVersions() []*KeyVersion {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+func (x *HistoryResponse) GetCompactedRevision() uint64 {
+	if x != nil {
+		return x.CompactedRevision
+	}
+	return 0
+}
+
+// TxnCompare is a condition on one key. VALUE and VERSION compare the
+// current value or version for equality; EXISTS and MISSING check whether
+// the key is present.
+type TxnCompare struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Target        TxnCompare_Target      `protobuf:"varint,2,opt,name=target,proto3,enum=badies.TxnCompare_Target" json:"target,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Version       uint64                 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TxnCompare) Reset() {
+	*x = TxnCompare{}
+	mi := &file_badies_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxnCompare) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxnCompare) ProtoMessage() {}
+
+func (x *TxnCompare) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxnCompare.ProtoReflect.Descriptor instead.
+func (*TxnCompare) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *TxnCompare) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *TxnCompare) GetTarget() TxnCompare_Target {
+	if x != nil {
+		return x.Target
+	}
+	return TxnCompare_VALUE
+}
+
+func (x *TxnCompare) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *TxnCompare) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type TxnOp struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          TxnOp_Type             `protobuf:"varint,1,opt,name=type,proto3,enum=badies.TxnOp_Type" json:"type,omitempty"`
+	Key           string                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value         string                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	TtlMs         int64                  `protobuf:"varint,4,opt,name=ttl_ms,json=ttlMs,proto3" json:"ttl_ms,omitempty"` // for puts, 0 for never
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TxnOp) Reset() {
+	*x = TxnOp{}
+	mi := &file_badies_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxnOp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxnOp) ProtoMessage() {}
+
+func (x *TxnOp) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxnOp.ProtoReflect.Descriptor instead.
+func (*TxnOp) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *TxnOp) GetType() TxnOp_Type {
+	if x != nil {
+		return x.Type
+	}
+	return TxnOp_PUT
+}
+
+func (x *TxnOp) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *TxnOp) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *TxnOp) GetTtlMs() int64 {
+	if x != nil {
+		return x.TtlMs
+	}
+	return 0
+}
+
+// TxnRequest applies ops, each on a different key, if every compare holds
+type TxnRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Compares      []*TxnCompare          `protobuf:"bytes,1,rep,name=compares,proto3" json:"compares,omitempty"`
+	Ops           []*TxnOp               `protobuf:"bytes,2,rep,name=ops,proto3" json:"ops,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TxnRequest) Reset() {
+	*x = TxnRequest{}
+	mi := &file_badies_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxnRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxnRequest) ProtoMessage() {}
+
+func (x *TxnRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxnRequest.ProtoReflect.Descriptor instead.
+func (*TxnRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *TxnRequest) GetCompares() []*TxnCompare {
+	if x != nil {
+		return x.Compares
+	}
+	return nil
+}
+
+func (x *TxnRequest) GetOps() []*TxnOp {
+	if x != nil {
+		return x.Ops
+	}
+	return nil
+}
+
+type TxnResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`                                            // false if a compare did not hold; nothing was written
+	Revision       uint64                 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`                                          // revision all ops were written at
+	FailedCompares []int32                `protobuf:"varint,3,rep,packed,name=failed_compares,json=failedCompares,proto3" json:"failed_compares,omitempty"` // indexes of the compares that did not hold
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *TxnResponse) Reset() {
+	*x = TxnResponse{}
+	mi := &file_badies_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxnResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxnResponse) ProtoMessage() {}
+
+func (x *TxnResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxnResponse.ProtoReflect.Descriptor instead.
+func (*TxnResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *TxnResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *TxnResponse) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+func (x *TxnResponse) GetFailedCompares() []int32 {
+	if x != nil {
+		return x.FailedCompares
+	}
+	return nil
+}
+
+type GetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         string                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	Revision      uint64                 `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"` // revision of the returned value
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetResponse) Reset() {
+	*x = GetResponse{}
+	mi := &file_badies_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetResponse) ProtoMessage() {}
+
+func (x *GetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetResponse.ProtoReflect.Descriptor instead.
+func (*GetResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetResponse) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *GetResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *GetResponse) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type PutResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Acks          int32                  `protobuf:"varint,2,opt,name=acks,proto3" json:"acks,omitempty"`         // number of replicas that acknowledged the write, 0 in strong mode
+	Revision      uint64                 `protobuf:"varint,3,opt,name=revision,proto3" json:"revision,omitempty"` // revision the write was stored at
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PutResponse) Reset() {
+	*x = PutResponse{}
+	mi := &file_badies_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PutResponse) ProtoMessage() {}
+
+func (x *PutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PutResponse.ProtoReflect.Descriptor instead.
+func (*PutResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *PutResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *PutResponse) GetAcks() int32 {
+	if x != nil {
+		return x.Acks
+	}
+	return 0
+}
+
+func (x *PutResponse) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type DeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Revision      uint64                 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteResponse) Reset() {
+	*x = DeleteResponse{}
+	mi := &file_badies_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteResponse) ProtoMessage() {}
+
+func (x *DeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteResponse.ProtoReflect.Descriptor instead.
+func (*DeleteResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DeleteResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteResponse) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type UpdateKeyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	NewKeyExists  bool                   `protobuf:"varint,2,opt,name=new_key_exists,json=newKeyExists,proto3" json:"new_key_exists,omitempty"` // set when fail_if_exists refused the rename
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateKeyResponse) Reset() {
+	*x = UpdateKeyResponse{}
+	mi := &file_badies_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateKeyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateKeyResponse) ProtoMessage() {}
+
+func (x *UpdateKeyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateKeyResponse.ProtoReflect.Descriptor instead.
+func (*UpdateKeyResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *UpdateKeyResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdateKeyResponse) GetNewKeyExists() bool {
+	if x != nil {
+		return x.NewKeyExists
+	}
+	return false
+}
+
+// UpdateValueResponse carries the value and version the key holds after the
+// call: the new ones on success, the ones that failed the comparison
+// otherwise
+type UpdateValueResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	CurrentValue  string                 `protobuf:"bytes,2,opt,name=current_value,json=currentValue,proto3" json:"current_value,omitempty"`
+	Version       uint64                 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Found         bool                   `protobuf:"varint,4,opt,name=found,proto3" json:"found,omitempty"` // false if the key does not exist
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateValueResponse) Reset() {
+	*x = UpdateValueResponse{}
+	mi := &file_badies_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateValueResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateValueResponse) ProtoMessage() {}
+
+func (x *UpdateValueResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateValueResponse.ProtoReflect.Descriptor instead.
+func (*UpdateValueResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *UpdateValueResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *UpdateValueResponse) GetCurrentValue() string {
+	if x != nil {
+		return x.CurrentValue
+	}
+	return ""
+}
+
+func (x *UpdateValueResponse) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *UpdateValueResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+// Every write to a storage node carries a version assigned by the
+// coordinator. A node only applies a write that is newer than what it
+// already stores (last write wins).
+type NodePutRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Key     []byte                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value   []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Version uint64                 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	// A conditional write is rejected if the node stores a version newer
+	// than expected_version (0 for a missing key)
+	Conditional     bool   `protobuf:"varint,4,opt,name=conditional,proto3" json:"conditional,omitempty"`
+	ExpectedVersion uint64 `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	ExpiresAt       int64  `protobuf:"varint,6,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // unix nanoseconds, 0 for never
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *NodePutRequest) Reset() {
+	*x = NodePutRequest{}
+	mi := &file_badies_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodePutRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodePutRequest) ProtoMessage() {}
+
+func (x *NodePutRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodePutRequest.ProtoReflect.Descriptor instead.
+func (*NodePutRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *NodePutRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodePutRequest) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *NodePutRequest) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *NodePutRequest) GetConditional() bool {
+	if x != nil {
+		return x.Conditional
+	}
+	return false
+}
+
+func (x *NodePutRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *NodePutRequest) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type NodePutResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Success        bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Applied        bool                   `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`                                     // false if the node already had a newer version
+	Conflict       bool                   `protobuf:"varint,3,opt,name=conflict,proto3" json:"conflict,omitempty"`                                   // a conditional write found a newer version
+	CurrentVersion uint64                 `protobuf:"varint,4,opt,name=current_version,json=currentVersion,proto3" json:"current_version,omitempty"` // version stored when conflict is set
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *NodePutResponse) Reset() {
+	*x = NodePutResponse{}
+	mi := &file_badies_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodePutResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodePutResponse) ProtoMessage() {}
+
+func (x *NodePutResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodePutResponse.ProtoReflect.Descriptor instead.
+func (*NodePutResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *NodePutResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *NodePutResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+func (x *NodePutResponse) GetConflict() bool {
+	if x != nil {
+		return x.Conflict
+	}
+	return false
+}
+
+func (x *NodePutResponse) GetCurrentVersion() uint64 {
+	if x != nil {
+		return x.CurrentVersion
+	}
+	return 0
+}
+
+type NodeGetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           []byte                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Revision      uint64                 `protobuf:"varint,2,opt,name=revision,proto3" json:"revision,omitempty"` // read the version current at this revision, 0 for the latest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeGetRequest) Reset() {
+	*x = NodeGetRequest{}
+	mi := &file_badies_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeGetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeGetRequest) ProtoMessage() {}
+
+func (x *NodeGetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeGetRequest.ProtoReflect.Descriptor instead.
+func (*NodeGetRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *NodeGetRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodeGetRequest) GetRevision() uint64 {
+	if x != nil {
+		return x.Revision
+	}
+	return 0
+}
+
+type NodeGetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         []byte                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	Found         bool                   `protobuf:"varint,2,opt,name=found,proto3" json:"found,omitempty"`
+	Version       uint64                 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Tombstone     bool                   `protobuf:"varint,4,opt,name=tombstone,proto3" json:"tombstone,omitempty"`                  // the key was deleted at version
+	ExpiresAt     int64                  `protobuf:"varint,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // found is false once this has passed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeGetResponse) Reset() {
+	*x = NodeGetResponse{}
+	mi := &file_badies_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeGetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeGetResponse) ProtoMessage() {}
+
+func (x *NodeGetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeGetResponse.ProtoReflect.Descriptor instead.
+func (*NodeGetResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *NodeGetResponse) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *NodeGetResponse) GetFound() bool {
+	if x != nil {
+		return x.Found
+	}
+	return false
+}
+
+func (x *NodeGetResponse) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *NodeGetResponse) GetTombstone() bool {
+	if x != nil {
+		return x.Tombstone
+	}
+	return false
+}
+
+func (x *NodeGetResponse) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type NodeDeleteRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           []byte                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Version       uint64                 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeDeleteRequest) Reset() {
+	*x = NodeDeleteRequest{}
+	mi := &file_badies_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeDeleteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeDeleteRequest) ProtoMessage() {}
+
+func (x *NodeDeleteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeDeleteRequest.ProtoReflect.Descriptor instead.
+func (*NodeDeleteRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *NodeDeleteRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodeDeleteRequest) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type NodeDeleteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Applied       bool                   `protobuf:"varint,2,opt,name=applied,proto3" json:"applied,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeDeleteResponse) Reset() {
+	*x = NodeDeleteResponse{}
+	mi := &file_badies_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeDeleteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeDeleteResponse) ProtoMessage() {}
+
+func (x *NodeDeleteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeDeleteResponse.ProtoReflect.Descriptor instead.
+func (*NodeDeleteResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *NodeDeleteResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *NodeDeleteResponse) GetApplied() bool {
+	if x != nil {
+		return x.Applied
+	}
+	return false
+}
+
+// NodeWriteBatchRequest applies versioned puts and tombstones in one
+// LevelDB write batch
+type NodeWriteBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*NodeRecord          `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeWriteBatchRequest) Reset() {
+	*x = NodeWriteBatchRequest{}
+	mi := &file_badies_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeWriteBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeWriteBatchRequest) ProtoMessage() {}
+
+func (x *NodeWriteBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeWriteBatchRequest.ProtoReflect.Descriptor instead.
+func (*NodeWriteBatchRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *NodeWriteBatchRequest) GetRecords() []*NodeRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type NodeWriteBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Applied       []bool                 `protobuf:"varint,1,rep,packed,name=applied,proto3" json:"applied,omitempty"` // per record, false if the node had a newer version
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeWriteBatchResponse) Reset() {
+	*x = NodeWriteBatchResponse{}
+	mi := &file_badies_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeWriteBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeWriteBatchResponse) ProtoMessage() {}
+
+func (x *NodeWriteBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeWriteBatchResponse.ProtoReflect.Descriptor instead.
+func (*NodeWriteBatchResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *NodeWriteBatchResponse) GetApplied() []bool {
+	if x != nil {
+		return x.Applied
+	}
+	return nil
+}
+
+type NodeGetBatchRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Keys          [][]byte               `protobuf:"bytes,1,rep,name=keys,proto3" json:"keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeGetBatchRequest) Reset() {
+	*x = NodeGetBatchRequest{}
+	mi := &file_badies_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeGetBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeGetBatchRequest) ProtoMessage() {}
+
+func (x *NodeGetBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeGetBatchRequest.ProtoReflect.Descriptor instead.
+func (*NodeGetBatchRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *NodeGetBatchRequest) GetKeys() [][]byte {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type NodeGetBatchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Records       []*NodeGetResponse     `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"` // in request order
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeGetBatchResponse) Reset() {
+	*x = NodeGetBatchResponse{}
+	mi := &file_badies_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeGetBatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeGetBatchResponse) ProtoMessage() {}
+
+func (x *NodeGetBatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeGetBatchResponse.ProtoReflect.Descriptor instead.
+func (*NodeGetBatchResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *NodeGetBatchResponse) GetRecords() []*NodeGetResponse {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type NodeScanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StartKey      []byte                 `protobuf:"bytes,1,opt,name=start_key,json=startKey,proto3" json:"start_key,omitempty"`
+	EndKey        []byte                 `protobuf:"bytes,2,opt,name=end_key,json=endKey,proto3" json:"end_key,omitempty"`
+	Prefix        []byte                 `protobuf:"bytes,3,opt,name=prefix,proto3" json:"prefix,omitempty"`
+	Reverse       bool                   `protobuf:"varint,4,opt,name=reverse,proto3" json:"reverse,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeScanRequest) Reset() {
+	*x = NodeScanRequest{}
+	mi := &file_badies_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeScanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeScanRequest) ProtoMessage() {}
+
+func (x *NodeScanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeScanRequest.ProtoReflect.Descriptor instead.
+func (*NodeScanRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *NodeScanRequest) GetStartKey() []byte {
+	if x != nil {
+		return x.StartKey
+	}
+	return nil
+}
+
+func (x *NodeScanRequest) GetEndKey() []byte {
+	if x != nil {
+		return x.EndKey
+	}
+	return nil
+}
+
+func (x *NodeScanRequest) GetPrefix() []byte {
+	if x != nil {
+		return x.Prefix
+	}
+	return nil
+}
+
+func (x *NodeScanRequest) GetReverse() bool {
+	if x != nil {
+		return x.Reverse
+	}
+	return false
+}
+
+// NodeHashRange is the arc (start, end] of the hash circle. A range with
+// start >= end wraps around zero.
+type NodeHashRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         uint32                 `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           uint32                 `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeHashRange) Reset() {
+	*x = NodeHashRange{}
+	mi := &file_badies_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeHashRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeHashRange) ProtoMessage() {}
+
+func (x *NodeHashRange) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeHashRange.ProtoReflect.Descriptor instead.
+func (*NodeHashRange) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *NodeHashRange) GetStart() uint32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *NodeHashRange) GetEnd() uint32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type NodeRangeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Ranges        []*NodeHashRange       `protobuf:"bytes,1,rep,name=ranges,proto3" json:"ranges,omitempty"`
+	Hash          string                 `protobuf:"bytes,2,opt,name=hash,proto3" json:"hash,omitempty"`        // hash function placing keys on the circle, crc32 if empty
+	History       bool                   `protobuf:"varint,3,opt,name=history,proto3" json:"history,omitempty"` // also send the kept versions of every key
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeRangeRequest) Reset() {
+	*x = NodeRangeRequest{}
+	mi := &file_badies_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRangeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRangeRequest) ProtoMessage() {}
+
+func (x *NodeRangeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRangeRequest.ProtoReflect.Descriptor instead.
+func (*NodeRangeRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *NodeRangeRequest) GetRanges() []*NodeHashRange {
+	if x != nil {
+		return x.Ranges
+	}
+	return nil
+}
+
+func (x *NodeRangeRequest) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *NodeRangeRequest) GetHistory() bool {
+	if x != nil {
+		return x.History
+	}
+	return false
+}
+
+type NodeRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           []byte                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	Version       uint64                 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Tombstone     bool                   `protobuf:"varint,4,opt,name=tombstone,proto3" json:"tombstone,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	History       []*NodeRecord          `protobuf:"bytes,6,rep,name=history,proto3" json:"history,omitempty"` // kept versions, newest first, if StreamRange was asked for them
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeRecord) Reset() {
+	*x = NodeRecord{}
+	mi := &file_badies_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRecord) ProtoMessage() {}
+
+func (x *NodeRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRecord.ProtoReflect.Descriptor instead.
+func (*NodeRecord) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *NodeRecord) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodeRecord) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *NodeRecord) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *NodeRecord) GetTombstone() bool {
+	if x != nil {
+		return x.Tombstone
+	}
+	return false
+}
+
+func (x *NodeRecord) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *NodeRecord) GetHistory() []*NodeRecord {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type NodeSweepRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ExpiredBefore int64                  `protobuf:"varint,1,opt,name=expired_before,json=expiredBefore,proto3" json:"expired_before,omitempty"` // unix nanoseconds
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeSweepRequest) Reset() {
+	*x = NodeSweepRequest{}
+	mi := &file_badies_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeSweepRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeSweepRequest) ProtoMessage() {}
+
+func (x *NodeSweepRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeSweepRequest.ProtoReflect.Descriptor instead.
+func (*NodeSweepRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *NodeSweepRequest) GetExpiredBefore() int64 {
+	if x != nil {
+		return x.ExpiredBefore
+	}
+	return 0
+}
+
+type NodeSweepResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Swept         int64                  `protobuf:"varint,1,opt,name=swept,proto3" json:"swept,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeSweepResponse) Reset() {
+	*x = NodeSweepResponse{}
+	mi := &file_badies_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeSweepResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeSweepResponse) ProtoMessage() {}
+
+func (x *NodeSweepResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeSweepResponse.ProtoReflect.Descriptor instead.
+func (*NodeSweepResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *NodeSweepResponse) GetSwept() int64 {
+	if x != nil {
+		return x.Swept
+	}
+	return 0
+}
+
+type NodeVersionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           []byte                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // 0 for all versions
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeVersionsRequest) Reset() {
+	*x = NodeVersionsRequest{}
+	mi := &file_badies_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeVersionsRequest) ProtoMessage() {}
+
+func (x *NodeVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeVersionsRequest.ProtoReflect.Descriptor instead.
+func (*NodeVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *NodeVersionsRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodeVersionsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type NodeVersionsResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Records           []*NodeRecord          `protobuf:"bytes,1,rep,name=records,proto3" json:"records,omitempty"`                                               // newest first
+	CompactedRevision uint64                 `protobuf:"varint,2,opt,name=compacted_revision,json=compactedRevision,proto3" json:"compacted_revision,omitempty"` // versions replaced before this revision are gone
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *NodeVersionsResponse) Reset() {
+	*x = NodeVersionsResponse{}
+	mi := &file_badies_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeVersionsResponse) ProtoMessage() {}
+
+func (x *NodeVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeVersionsResponse.ProtoReflect.Descriptor instead.
+func (*NodeVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *NodeVersionsResponse) GetRecords() []*NodeRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *NodeVersionsResponse) GetCompactedRevision() uint64 {
+	if x != nil {
+		return x.CompactedRevision
+	}
+	return 0
+}
+
+type NodeKeepVersionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           []byte                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Versions      []*NodeRecord          `protobuf:"bytes,2,rep,name=versions,proto3" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeKeepVersionsRequest) Reset() {
+	*x = NodeKeepVersionsRequest{}
+	mi := &file_badies_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeKeepVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeKeepVersionsRequest) ProtoMessage() {}
+
+func (x *NodeKeepVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeKeepVersionsRequest.ProtoReflect.Descriptor instead.
+func (*NodeKeepVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *NodeKeepVersionsRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodeKeepVersionsRequest) GetVersions() []*NodeRecord {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+type NodeKeepVersionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kept          int32                  `protobuf:"varint,1,opt,name=kept,proto3" json:"kept,omitempty"` // versions that were older than the stored record and not kept yet
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeKeepVersionsResponse) Reset() {
+	*x = NodeKeepVersionsResponse{}
+	mi := &file_badies_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeKeepVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeKeepVersionsResponse) ProtoMessage() {}
+
+func (x *NodeKeepVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeKeepVersionsResponse.ProtoReflect.Descriptor instead.
+func (*NodeKeepVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *NodeKeepVersionsResponse) GetKept() int32 {
+	if x != nil {
+		return x.Kept
+	}
+	return 0
+}
+
+type NodePurgeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           []byte                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Version       uint64                 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // only purge if the stored version is not newer
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodePurgeRequest) Reset() {
+	*x = NodePurgeRequest{}
+	mi := &file_badies_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodePurgeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodePurgeRequest) ProtoMessage() {}
+
+func (x *NodePurgeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodePurgeRequest.ProtoReflect.Descriptor instead.
+func (*NodePurgeRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *NodePurgeRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodePurgeRequest) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type NodePurgeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Purged        bool                   `protobuf:"varint,1,opt,name=purged,proto3" json:"purged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodePurgeResponse) Reset() {
+	*x = NodePurgeResponse{}
+	mi := &file_badies_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodePurgeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodePurgeResponse) ProtoMessage() {}
+
+func (x *NodePurgeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodePurgeResponse.ProtoReflect.Descriptor instead.
+func (*NodePurgeResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *NodePurgeResponse) GetPurged() bool {
+	if x != nil {
+		return x.Purged
+	}
+	return false
+}
+
+type NodeRevertRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Key             []byte                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Version         uint64                 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`                                        // version of the write to undo
+	PreviousVersion uint64                 `protobuf:"varint,3,opt,name=previous_version,json=previousVersion,proto3" json:"previous_version,omitempty"` // version it replaced, restored from the history
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *NodeRevertRequest) Reset() {
+	*x = NodeRevertRequest{}
+	mi := &file_badies_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRevertRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRevertRequest) ProtoMessage() {}
+
+func (x *NodeRevertRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRevertRequest.ProtoReflect.Descriptor instead.
+func (*NodeRevertRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *NodeRevertRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodeRevertRequest) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *NodeRevertRequest) GetPreviousVersion() uint64 {
+	if x != nil {
+		return x.PreviousVersion
+	}
+	return 0
+}
+
+type NodeRevertResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reverted      bool                   `protobuf:"varint,1,opt,name=reverted,proto3" json:"reverted,omitempty"` // false if the key was written again since
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeRevertResponse) Reset() {
+	*x = NodeRevertResponse{}
+	mi := &file_badies_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRevertResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRevertResponse) ProtoMessage() {}
+
+func (x *NodeRevertResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRevertResponse.ProtoReflect.Descriptor instead.
+func (*NodeRevertResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *NodeRevertResponse) GetReverted() bool {
+	if x != nil {
+		return x.Reverted
+	}
+	return false
+}
+
+// NodeHint is a write held on behalf of the replica target
+type NodeHint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Target        string                 `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	Record        *NodeRecord            `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // unix nanoseconds, set by the holder
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeHint) Reset() {
+	*x = NodeHint{}
+	mi := &file_badies_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeHint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeHint) ProtoMessage() {}
+
+func (x *NodeHint) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeHint.ProtoReflect.Descriptor instead.
+func (*NodeHint) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *NodeHint) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *NodeHint) GetRecord() *NodeRecord {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *NodeHint) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+type NodeStoreHintResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stored        bool                   `protobuf:"varint,1,opt,name=stored,proto3" json:"stored,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeStoreHintResponse) Reset() {
+	*x = NodeStoreHintResponse{}
+	mi := &file_badies_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeStoreHintResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeStoreHintResponse) ProtoMessage() {}
+
+func (x *NodeStoreHintResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeStoreHintResponse.ProtoReflect.Descriptor instead.
+func (*NodeStoreHintResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *NodeStoreHintResponse) GetStored() bool {
+	if x != nil {
+		return x.Stored
+	}
+	return false
+}
+
+type NodeStreamHintsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeStreamHintsRequest) Reset() {
+	*x = NodeStreamHintsRequest{}
+	mi := &file_badies_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeStreamHintsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeStreamHintsRequest) ProtoMessage() {}
+
+func (x *NodeStreamHintsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeStreamHintsRequest.ProtoReflect.Descriptor instead.
+func (*NodeStreamHintsRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{60}
+}
+
+type NodeDeleteHintRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Target        string                 `protobuf:"bytes,1,opt,name=target,proto3" json:"target,omitempty"`
+	Key           []byte                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Version       uint64                 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"` // only delete if the hint was not replaced since
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeDeleteHintRequest) Reset() {
+	*x = NodeDeleteHintRequest{}
+	mi := &file_badies_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeDeleteHintRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeDeleteHintRequest) ProtoMessage() {}
+
+func (x *NodeDeleteHintRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeDeleteHintRequest.ProtoReflect.Descriptor instead.
+func (*NodeDeleteHintRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *NodeDeleteHintRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *NodeDeleteHintRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodeDeleteHintRequest) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type NodeDeleteHintResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deleted       bool                   `protobuf:"varint,1,opt,name=deleted,proto3" json:"deleted,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeDeleteHintResponse) Reset() {
+	*x = NodeDeleteHintResponse{}
+	mi := &file_badies_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeDeleteHintResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeDeleteHintResponse) ProtoMessage() {}
+
+func (x *NodeDeleteHintResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeDeleteHintResponse.ProtoReflect.Descriptor instead.
+func (*NodeDeleteHintResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *NodeDeleteHintResponse) GetDeleted() bool {
+	if x != nil {
+		return x.Deleted
+	}
+	return false
+}
+
+type NodeMerkleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Range         *NodeHashRange         `protobuf:"bytes,1,opt,name=range,proto3" json:"range,omitempty"`
+	Depth         uint32                 `protobuf:"varint,2,opt,name=depth,proto3" json:"depth,omitempty"` // the tree has 2^depth leaves
+	Hash          string                 `protobuf:"bytes,3,opt,name=hash,proto3" json:"hash,omitempty"`    // hash function placing keys on the circle, crc32 if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeMerkleRequest) Reset() {
+	*x = NodeMerkleRequest{}
+	mi := &file_badies_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeMerkleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeMerkleRequest) ProtoMessage() {}
+
+func (x *NodeMerkleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeMerkleRequest.ProtoReflect.Descriptor instead.
+func (*NodeMerkleRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *NodeMerkleRequest) GetRange() *NodeHashRange {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+func (x *NodeMerkleRequest) GetDepth() uint32 {
+	if x != nil {
+		return x.Depth
+	}
+	return 0
+}
+
+func (x *NodeMerkleRequest) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+// NodeMerkleResponse holds the tree in heap order: hashes[0] is the root and
+// the children of i are 2i+1 and 2i+2
+type NodeMerkleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hashes        [][]byte               `protobuf:"bytes,1,rep,name=hashes,proto3" json:"hashes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeMerkleResponse) Reset() {
+	*x = NodeMerkleResponse{}
+	mi := &file_badies_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeMerkleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeMerkleResponse) ProtoMessage() {}
+
+func (x *NodeMerkleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeMerkleResponse.ProtoReflect.Descriptor instead.
+func (*NodeMerkleResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *NodeMerkleResponse) GetHashes() [][]byte {
+	if x != nil {
+		return x.Hashes
+	}
+	return nil
+}
+
+type RepairRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Target:
+	//
+	//	*RepairRequest_NodeId
+	//	*RepairRequest_Range
+	Target        isRepairRequest_Target `protobuf_oneof:"target"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RepairRequest) Reset() {
+	*x = RepairRequest{}
+	mi := &file_badies_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RepairRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RepairRequest) ProtoMessage() {}
+
+func (x *RepairRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RepairRequest.ProtoReflect.Descriptor instead.
+func (*RepairRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *RepairRequest) GetTarget() isRepairRequest_Target {
+	if x != nil {
+		return x.Target
+	}
+	return nil
+}
+
+func (x *RepairRequest) GetNodeId() string {
+	if x != nil {
+		if x, ok := x.Target.(*RepairRequest_NodeId); ok {
+			return x.NodeId
+		}
+	}
+	return ""
+}
+
+func (x *RepairRequest) GetRange() *NodeHashRange {
+	if x != nil {
+		if x, ok := x.Target.(*RepairRequest_Range); ok {
+			return x.Range
+		}
+	}
+	return nil
+}
+
+type isRepairRequest_Target interface {
+	isRepairRequest_Target()
+}
+
+type RepairRequest_NodeId struct {
+	NodeId string `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3,oneof"`
+}
+
+type RepairRequest_Range struct {
+	Range *NodeHashRange `protobuf:"bytes,2,opt,name=range,proto3,oneof"`
+}
+
+func (*RepairRequest_NodeId) isRepairRequest_Target() {}
+
+func (*RepairRequest_Range) isRepairRequest_Target() {}
+
+type RepairResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	RangesCompared int32                  `protobuf:"varint,1,opt,name=ranges_compared,json=rangesCompared,proto3" json:"ranges_compared,omitempty"`
+	RangesRepaired int32                  `protobuf:"varint,2,opt,name=ranges_repaired,json=rangesRepaired,proto3" json:"ranges_repaired,omitempty"`
+	KeysRepaired   int64                  `protobuf:"varint,3,opt,name=keys_repaired,json=keysRepaired,proto3" json:"keys_repaired,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *RepairResponse) Reset() {
+	*x = RepairResponse{}
+	mi := &file_badies_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RepairResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RepairResponse) ProtoMessage() {}
+
+func (x *RepairResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RepairResponse.ProtoReflect.Descriptor instead.
+func (*RepairResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *RepairResponse) GetRangesCompared() int32 {
+	if x != nil {
+		return x.RangesCompared
+	}
+	return 0
+}
+
+func (x *RepairResponse) GetRangesRepaired() int32 {
+	if x != nil {
+		return x.RangesRepaired
+	}
+	return 0
+}
+
+func (x *RepairResponse) GetKeysRepaired() int64 {
+	if x != nil {
+		return x.KeysRepaired
+	}
+	return 0
+}
+
+type AddNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"` // host:port of the node's StorageNode service
+	Weight        int32                  `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`  // share of the ring, 0 for 1
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddNodeRequest) Reset() {
+	*x = AddNodeRequest{}
+	mi := &file_badies_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddNodeRequest) ProtoMessage() {}
+
+func (x *AddNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddNodeRequest.ProtoReflect.Descriptor instead.
+func (*AddNodeRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *AddNodeRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *AddNodeRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *AddNodeRequest) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+type AddNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddNodeResponse) Reset() {
+	*x = AddNodeResponse{}
+	mi := &file_badies_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddNodeResponse) ProtoMessage() {}
+
+func (x *AddNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddNodeResponse.ProtoReflect.Descriptor instead.
+func (*AddNodeResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{68}
+}
+
+type RemoveNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Drain         bool                   `protobuf:"varint,2,opt,name=drain,proto3" json:"drain,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveNodeRequest) Reset() {
+	*x = RemoveNodeRequest{}
+	mi := &file_badies_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveNodeRequest) ProtoMessage() {}
+
+func (x *RemoveNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveNodeRequest.ProtoReflect.Descriptor instead.
+func (*RemoveNodeRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *RemoveNodeRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *RemoveNodeRequest) GetDrain() bool {
+	if x != nil {
+		return x.Drain
+	}
+	return false
+}
+
+// RemoveNodeResponse reports the keys a drain moved
+type RemoveNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	KeysCopied    int64                  `protobuf:"varint,1,opt,name=keys_copied,json=keysCopied,proto3" json:"keys_copied,omitempty"`
+	KeysPurged    int64                  `protobuf:"varint,2,opt,name=keys_purged,json=keysPurged,proto3" json:"keys_purged,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveNodeResponse) Reset() {
+	*x = RemoveNodeResponse{}
+	mi := &file_badies_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveNodeResponse) ProtoMessage() {}
+
+func (x *RemoveNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveNodeResponse.ProtoReflect.Descriptor instead.
+func (*RemoveNodeResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *RemoveNodeResponse) GetKeysCopied() int64 {
+	if x != nil {
+		return x.KeysCopied
+	}
+	return 0
+}
+
+func (x *RemoveNodeResponse) GetKeysPurged() int64 {
+	if x != nil {
+		return x.KeysPurged
+	}
+	return 0
+}
+
+type ReplaceNodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplaceNodeRequest) Reset() {
+	*x = ReplaceNodeRequest{}
+	mi := &file_badies_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplaceNodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaceNodeRequest) ProtoMessage() {}
+
+func (x *ReplaceNodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaceNodeRequest.ProtoReflect.Descriptor instead.
+func (*ReplaceNodeRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *ReplaceNodeRequest) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *ReplaceNodeRequest) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+type ReplaceNodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplaceNodeResponse) Reset() {
+	*x = ReplaceNodeResponse{}
+	mi := &file_badies_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplaceNodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplaceNodeResponse) ProtoMessage() {}
+
+func (x *ReplaceNodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplaceNodeResponse.ProtoReflect.Descriptor instead.
+func (*ReplaceNodeResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{72}
+}
+
+type ListNodesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNodesRequest) Reset() {
+	*x = ListNodesRequest{}
+	mi := &file_badies_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNodesRequest) ProtoMessage() {}
+
+func (x *ListNodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNodesRequest.ProtoReflect.Descriptor instead.
+func (*ListNodesRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{73}
+}
+
+type NodeInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Weight        int32                  `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	State         NodeInfo_State         `protobuf:"varint,4,opt,name=state,proto3,enum=badies.NodeInfo_State" json:"state,omitempty"`
+	Health        *NodeHealth            `protobuf:"bytes,5,opt,name=health,proto3" json:"health,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeInfo) Reset() {
+	*x = NodeInfo{}
+	mi := &file_badies_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeInfo) ProtoMessage() {}
+
+func (x *NodeInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeInfo.ProtoReflect.Descriptor instead.
+func (*NodeInfo) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *NodeInfo) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *NodeInfo) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *NodeInfo) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *NodeInfo) GetState() NodeInfo_State {
+	if x != nil {
+		return x.State
+	}
+	return NodeInfo_ACTIVE
+}
+
+func (x *NodeInfo) GetHealth() *NodeHealth {
+	if x != nil {
+		return x.Health
+	}
+	return nil
+}
+
+// NodeHealth is what the server observed of its calls to a node
+type NodeHealth struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Circuit             NodeHealth_Circuit     `protobuf:"varint,1,opt,name=circuit,proto3,enum=badies.NodeHealth_Circuit" json:"circuit,omitempty"`
+	ErrorRate           float64                `protobuf:"fixed64,2,opt,name=error_rate,json=errorRate,proto3" json:"error_rate,omitempty"` // moving average of failed calls, from 0 to 1
+	LatencyMs           float64                `protobuf:"fixed64,3,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"` // moving average of answered calls
+	ConsecutiveFailures int32                  `protobuf:"varint,4,opt,name=consecutive_failures,json=consecutiveFailures,proto3" json:"consecutive_failures,omitempty"`
+	LastError           string                 `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *NodeHealth) Reset() {
+	*x = NodeHealth{}
+	mi := &file_badies_proto_msgTypes[75]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeHealth) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeHealth) ProtoMessage() {}
+
+func (x *NodeHealth) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[75]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeHealth.ProtoReflect.Descriptor instead.
+func (*NodeHealth) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{75}
+}
+
+func (x *NodeHealth) GetCircuit() NodeHealth_Circuit {
+	if x != nil {
+		return x.Circuit
+	}
+	return NodeHealth_CLOSED
+}
+
+func (x *NodeHealth) GetErrorRate() float64 {
+	if x != nil {
+		return x.ErrorRate
+	}
+	return 0
+}
+
+func (x *NodeHealth) GetLatencyMs() float64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *NodeHealth) GetConsecutiveFailures() int32 {
+	if x != nil {
+		return x.ConsecutiveFailures
+	}
+	return 0
+}
+
+func (x *NodeHealth) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+type ListNodesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Nodes         []*NodeInfo            `protobuf:"bytes,1,rep,name=nodes,proto3" json:"nodes,omitempty"` // ordered by node ID
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListNodesResponse) Reset() {
+	*x = ListNodesResponse{}
+	mi := &file_badies_proto_msgTypes[76]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListNodesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListNodesResponse) ProtoMessage() {}
+
+func (x *ListNodesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[76]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListNodesResponse.ProtoReflect.Descriptor instead.
+func (*ListNodesResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{76}
+}
+
+func (x *ListNodesResponse) GetNodes() []*NodeInfo {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+type GossipMember struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	State         GossipMember_State     `protobuf:"varint,3,opt,name=state,proto3,enum=badies.GossipMember_State" json:"state,omitempty"`
+	Incarnation   uint64                 `protobuf:"varint,4,opt,name=incarnation,proto3" json:"incarnation,omitempty"` // raised by the member itself to refute a suspicion
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GossipMember) Reset() {
+	*x = GossipMember{}
+	mi := &file_badies_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GossipMember) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GossipMember) ProtoMessage() {}
+
+func (x *GossipMember) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[77]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GossipMember.ProtoReflect.Descriptor instead.
+func (*GossipMember) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{77}
+}
+
+func (x *GossipMember) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GossipMember) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *GossipMember) GetState() GossipMember_State {
+	if x != nil {
+		return x.State
+	}
+	return GossipMember_ALIVE
+}
+
+func (x *GossipMember) GetIncarnation() uint64 {
+	if x != nil {
+		return x.Incarnation
+	}
+	return 0
+}
+
+type GossipPing struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *GossipMember          `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Updates       []*GossipMember        `protobuf:"bytes,2,rep,name=updates,proto3" json:"updates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GossipPing) Reset() {
+	*x = GossipPing{}
+	mi := &file_badies_proto_msgTypes[78]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GossipPing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GossipPing) ProtoMessage() {}
+
+func (x *GossipPing) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[78]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GossipPing.ProtoReflect.Descriptor instead.
+func (*GossipPing) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{78}
+}
+
+func (x *GossipPing) GetFrom() *GossipMember {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GossipPing) GetUpdates() []*GossipMember {
+	if x != nil {
+		return x.Updates
+	}
+	return nil
+}
+
+type GossipAck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *GossipMember          `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	Updates       []*GossipMember        `protobuf:"bytes,2,rep,name=updates,proto3" json:"updates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GossipAck) Reset() {
+	*x = GossipAck{}
+	mi := &file_badies_proto_msgTypes[79]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GossipAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GossipAck) ProtoMessage() {}
+
+func (x *GossipAck) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[79]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GossipAck.ProtoReflect.Descriptor instead.
+func (*GossipAck) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{79}
+}
+
+func (x *GossipAck) GetFrom() *GossipMember {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GossipAck) GetUpdates() []*GossipMember {
+	if x != nil {
+		return x.Updates
+	}
+	return nil
+}
+
+type GossipPingReq struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          *GossipMember          `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	TargetAddress string                 `protobuf:"bytes,2,opt,name=target_address,json=targetAddress,proto3" json:"target_address,omitempty"`
+	Updates       []*GossipMember        `protobuf:"bytes,3,rep,name=updates,proto3" json:"updates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GossipPingReq) Reset() {
+	*x = GossipPingReq{}
+	mi := &file_badies_proto_msgTypes[80]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GossipPingReq) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GossipPingReq) ProtoMessage() {}
+
+func (x *GossipPingReq) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[80]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GossipPingReq.ProtoReflect.Descriptor instead.
+func (*GossipPingReq) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{80}
+}
+
+func (x *GossipPingReq) GetFrom() *GossipMember {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GossipPingReq) GetTargetAddress() string {
+	if x != nil {
+		return x.TargetAddress
+	}
+	return ""
+}
+
+func (x *GossipPingReq) GetUpdates() []*GossipMember {
+	if x != nil {
+		return x.Updates
+	}
+	return nil
+}
+
+type NodeRaftPeer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Addr          string                 `protobuf:"bytes,2,opt,name=addr,proto3" json:"addr,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeRaftPeer) Reset() {
+	*x = NodeRaftPeer{}
+	mi := &file_badies_proto_msgTypes[81]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRaftPeer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRaftPeer) ProtoMessage() {}
+
+func (x *NodeRaftPeer) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRaftPeer.ProtoReflect.Descriptor instead.
+func (*NodeRaftPeer) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *NodeRaftPeer) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *NodeRaftPeer) GetAddr() string {
+	if x != nil {
+		return x.Addr
+	}
+	return ""
+}
+
+// NodeRaftGroup describes the replica set of one ring partition
+type NodeRaftGroup struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       uint64                 `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Peers         []*NodeRaftPeer        `protobuf:"bytes,2,rep,name=peers,proto3" json:"peers,omitempty"`
+	Range         *NodeHashRange         `protobuf:"bytes,3,opt,name=range,proto3" json:"range,omitempty"` // hash range of the partition, whose records snapshots carry
+	Hash          string                 `protobuf:"bytes,4,opt,name=hash,proto3" json:"hash,omitempty"`   // hash function placing keys on the circle, crc32 if empty
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeRaftGroup) Reset() {
+	*x = NodeRaftGroup{}
+	mi := &file_badies_proto_msgTypes[82]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRaftGroup) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRaftGroup) ProtoMessage() {}
+
+func (x *NodeRaftGroup) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[82]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRaftGroup.ProtoReflect.Descriptor instead.
+func (*NodeRaftGroup) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{82}
+}
+
+func (x *NodeRaftGroup) GetGroupId() uint64 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *NodeRaftGroup) GetPeers() []*NodeRaftPeer {
+	if x != nil {
+		return x.Peers
+	}
+	return nil
+}
+
+func (x *NodeRaftGroup) GetRange() *NodeHashRange {
+	if x != nil {
+		return x.Range
+	}
+	return nil
+}
+
+func (x *NodeRaftGroup) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+type NodeRaftStartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Started       bool                   `protobuf:"varint,1,opt,name=started,proto3" json:"started,omitempty"` // false if the group was already running
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeRaftStartResponse) Reset() {
+	*x = NodeRaftStartResponse{}
+	mi := &file_badies_proto_msgTypes[83]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRaftStartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRaftStartResponse) ProtoMessage() {}
+
+func (x *NodeRaftStartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[83]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRaftStartResponse.ProtoReflect.Descriptor instead.
+func (*NodeRaftStartResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{83}
+}
+
+func (x *NodeRaftStartResponse) GetStarted() bool {
+	if x != nil {
+		return x.Started
+	}
+	return false
+}
+
+// NodeRaftEnvelope carries a serialized raftpb.Message between nodes
+type NodeRaftEnvelope struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       uint64                 `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Message       []byte                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeRaftEnvelope) Reset() {
+	*x = NodeRaftEnvelope{}
+	mi := &file_badies_proto_msgTypes[84]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRaftEnvelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRaftEnvelope) ProtoMessage() {}
+
+func (x *NodeRaftEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[84]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRaftEnvelope.ProtoReflect.Descriptor instead.
+func (*NodeRaftEnvelope) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{84}
+}
+
+func (x *NodeRaftEnvelope) GetGroupId() uint64 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *NodeRaftEnvelope) GetMessage() []byte {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+type NodeRaftMessageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeRaftMessageResponse) Reset() {
+	*x = NodeRaftMessageResponse{}
+	mi := &file_badies_proto_msgTypes[85]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRaftMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRaftMessageResponse) ProtoMessage() {}
+
+func (x *NodeRaftMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[85]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRaftMessageResponse.ProtoReflect.Descriptor instead.
+func (*NodeRaftMessageResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{85}
+}
+
+// NodeRaftCommand is the payload of a raft log entry
+type NodeRaftCommand struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	RequestId       uint64                 `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Key             []byte                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value           []byte                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Version         uint64                 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	Tombstone       bool                   `protobuf:"varint,5,opt,name=tombstone,proto3" json:"tombstone,omitempty"`
+	Conditional     bool                   `protobuf:"varint,6,opt,name=conditional,proto3" json:"conditional,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,7,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	ExpiresAt       int64                  `protobuf:"varint,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Exact           bool                   `protobuf:"varint,9,opt,name=exact,proto3" json:"exact,omitempty"` // apply at version instead of after the group's last version
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *NodeRaftCommand) Reset() {
+	*x = NodeRaftCommand{}
+	mi := &file_badies_proto_msgTypes[86]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRaftCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRaftCommand) ProtoMessage() {}
+
+func (x *NodeRaftCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[86]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRaftCommand.ProtoReflect.Descriptor instead.
+func (*NodeRaftCommand) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{86}
+}
+
+func (x *NodeRaftCommand) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *NodeRaftCommand) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodeRaftCommand) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *NodeRaftCommand) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *NodeRaftCommand) GetTombstone() bool {
+	if x != nil {
+		return x.Tombstone
+	}
+	return false
+}
+
+func (x *NodeRaftCommand) GetConditional() bool {
+	if x != nil {
+		return x.Conditional
+	}
+	return false
+}
+
+func (x *NodeRaftCommand) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *NodeRaftCommand) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *NodeRaftCommand) GetExact() bool {
+	if x != nil {
+		return x.Exact
+	}
+	return false
+}
+
+// NodeRaftWriteRequest proposes a write to a raft group. Conditional writes
+// are checked against expected_version when the entry is applied, like
+// conditional NodePutRequests.
+type NodeRaftWriteRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	GroupId         uint64                 `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Key             []byte                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	Value           []byte                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Tombstone       bool                   `protobuf:"varint,4,opt,name=tombstone,proto3" json:"tombstone,omitempty"`
+	Conditional     bool                   `protobuf:"varint,5,opt,name=conditional,proto3" json:"conditional,omitempty"`
+	ExpectedVersion uint64                 `protobuf:"varint,6,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	ExpiresAt       int64                  `protobuf:"varint,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	RequestId       uint64                 `protobuf:"varint,8,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"` // chosen by the caller; a write retried with the same ID is applied once
+	Version         uint64                 `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`                      // if set, the write is applied at this version and skipped if the key holds one at least as new
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *NodeRaftWriteRequest) Reset() {
+	*x = NodeRaftWriteRequest{}
+	mi := &file_badies_proto_msgTypes[87]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRaftWriteRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRaftWriteRequest) ProtoMessage() {}
+
+func (x *NodeRaftWriteRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[87]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRaftWriteRequest.ProtoReflect.Descriptor instead.
+func (*NodeRaftWriteRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{87}
+}
+
+func (x *NodeRaftWriteRequest) GetGroupId() uint64 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *NodeRaftWriteRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodeRaftWriteRequest) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *NodeRaftWriteRequest) GetTombstone() bool {
+	if x != nil {
+		return x.Tombstone
+	}
+	return false
+}
+
+func (x *NodeRaftWriteRequest) GetConditional() bool {
+	if x != nil {
+		return x.Conditional
+	}
+	return false
+}
+
+func (x *NodeRaftWriteRequest) GetExpectedVersion() uint64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+func (x *NodeRaftWriteRequest) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+func (x *NodeRaftWriteRequest) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *NodeRaftWriteRequest) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type NodeRaftWriteResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       uint64                 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`   // version the write was applied at
+	Conflict      bool                   `protobuf:"varint,2,opt,name=conflict,proto3" json:"conflict,omitempty"` // a conditional write found a newer version
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeRaftWriteResponse) Reset() {
+	*x = NodeRaftWriteResponse{}
+	mi := &file_badies_proto_msgTypes[88]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRaftWriteResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRaftWriteResponse) ProtoMessage() {}
+
+func (x *NodeRaftWriteResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[88]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRaftWriteResponse.ProtoReflect.Descriptor instead.
+func (*NodeRaftWriteResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{88}
+}
+
+func (x *NodeRaftWriteResponse) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *NodeRaftWriteResponse) GetConflict() bool {
+	if x != nil {
+		return x.Conflict
+	}
+	return false
+}
+
+// NodeRaftSnapshot is the data of a raft group snapshot: the apply state
+// every member derives from the log and, when it is sent to a member that
+// fell behind the compacted log, the records of the group's range
+type NodeRaftSnapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LastVersion   uint64                 `protobuf:"varint,1,opt,name=last_version,json=lastVersion,proto3" json:"last_version,omitempty"` // highest version handed out by the log
+	Applied       []*NodeRaftApplied     `protobuf:"bytes,2,rep,name=applied,proto3" json:"applied,omitempty"`                             // recent writes, oldest first
+	Records       []*NodeRecord          `protobuf:"bytes,3,rep,name=records,proto3" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeRaftSnapshot) Reset() {
+	*x = NodeRaftSnapshot{}
+	mi := &file_badies_proto_msgTypes[89]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRaftSnapshot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRaftSnapshot) ProtoMessage() {}
+
+func (x *NodeRaftSnapshot) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[89]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRaftSnapshot.ProtoReflect.Descriptor instead.
+func (*NodeRaftSnapshot) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{89}
+}
+
+func (x *NodeRaftSnapshot) GetLastVersion() uint64 {
+	if x != nil {
+		return x.LastVersion
+	}
+	return 0
+}
+
+func (x *NodeRaftSnapshot) GetApplied() []*NodeRaftApplied {
+	if x != nil {
+		return x.Applied
+	}
+	return nil
+}
+
+func (x *NodeRaftSnapshot) GetRecords() []*NodeRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+// NodeRaftApplied is the outcome of a recently applied write, kept so that a
+// retry with the same request ID gets it instead of being applied again
+type NodeRaftApplied struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RequestId     uint64                 `protobuf:"varint,1,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Index         uint64                 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"` // log index the write was applied at
+	Version       uint64                 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Conflict      bool                   `protobuf:"varint,4,opt,name=conflict,proto3" json:"conflict,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeRaftApplied) Reset() {
+	*x = NodeRaftApplied{}
+	mi := &file_badies_proto_msgTypes[90]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRaftApplied) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRaftApplied) ProtoMessage() {}
+
+func (x *NodeRaftApplied) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[90]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRaftApplied.ProtoReflect.Descriptor instead.
+func (*NodeRaftApplied) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{90}
+}
+
+func (x *NodeRaftApplied) GetRequestId() uint64 {
+	if x != nil {
+		return x.RequestId
+	}
+	return 0
+}
+
+func (x *NodeRaftApplied) GetIndex() uint64 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *NodeRaftApplied) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *NodeRaftApplied) GetConflict() bool {
+	if x != nil {
+		return x.Conflict
+	}
+	return false
+}
+
+type NodeRaftReadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	GroupId       uint64                 `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	Key           []byte                 `protobuf:"bytes,2,opt,name=key,proto3" json:"key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeRaftReadRequest) Reset() {
+	*x = NodeRaftReadRequest{}
+	mi := &file_badies_proto_msgTypes[91]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeRaftReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeRaftReadRequest) ProtoMessage() {}
+
+func (x *NodeRaftReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[91]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeRaftReadRequest.ProtoReflect.Descriptor instead.
+func (*NodeRaftReadRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{91}
+}
+
+func (x *NodeRaftReadRequest) GetGroupId() uint64 {
+	if x != nil {
+		return x.GroupId
+	}
+	return 0
+}
+
+func (x *NodeRaftReadRequest) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+// NodeTxnCheck asks a node to refuse a prepare if key was written after
+// version, the version the coordinator read
+type NodeTxnCheck struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           []byte                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Version       uint64                 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeTxnCheck) Reset() {
+	*x = NodeTxnCheck{}
+	mi := &file_badies_proto_msgTypes[92]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeTxnCheck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeTxnCheck) ProtoMessage() {}
+
+func (x *NodeTxnCheck) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[92]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeTxnCheck.ProtoReflect.Descriptor instead.
+func (*NodeTxnCheck) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{92}
+}
+
+func (x *NodeTxnCheck) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *NodeTxnCheck) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type NodeTxnPrepareRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TxnId         uint64                 `protobuf:"varint,1,opt,name=txn_id,json=txnId,proto3" json:"txn_id,omitempty"`
+	Records       []*NodeRecord          `protobuf:"bytes,2,rep,name=records,proto3" json:"records,omitempty"` // writes this node applies on commit
+	Checks        []*NodeTxnCheck        `protobuf:"bytes,3,rep,name=checks,proto3" json:"checks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeTxnPrepareRequest) Reset() {
+	*x = NodeTxnPrepareRequest{}
+	mi := &file_badies_proto_msgTypes[93]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeTxnPrepareRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeTxnPrepareRequest) ProtoMessage() {}
+
+func (x *NodeTxnPrepareRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[93]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeTxnPrepareRequest.ProtoReflect.Descriptor instead.
+func (*NodeTxnPrepareRequest) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{93}
+}
+
+func (x *NodeTxnPrepareRequest) GetTxnId() uint64 {
+	if x != nil {
+		return x.TxnId
+	}
+	return 0
+}
+
+func (x *NodeTxnPrepareRequest) GetRecords() []*NodeRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *NodeTxnPrepareRequest) GetChecks() []*NodeTxnCheck {
+	if x != nil {
+		return x.Checks
+	}
+	return nil
+}
+
+type NodeTxnPrepareResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Prepared      bool                   `protobuf:"varint,1,opt,name=prepared,proto3" json:"prepared,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"` // why the node refused
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeTxnPrepareResponse) Reset() {
+	*x = NodeTxnPrepareResponse{}
+	mi := &file_badies_proto_msgTypes[94]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeTxnPrepareResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeTxnPrepareResponse) ProtoMessage() {}
+
+func (x *NodeTxnPrepareResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[94]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeTxnPrepareResponse.ProtoReflect.Descriptor instead.
+func (*NodeTxnPrepareResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{94}
+}
+
+func (x *NodeTxnPrepareResponse) GetPrepared() bool {
+	if x != nil {
+		return x.Prepared
+	}
+	return false
+}
+
+func (x *NodeTxnPrepareResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type NodeTxnDecision struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TxnId         uint64                 `protobuf:"varint,1,opt,name=txn_id,json=txnId,proto3" json:"txn_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeTxnDecision) Reset() {
+	*x = NodeTxnDecision{}
+	mi := &file_badies_proto_msgTypes[95]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeTxnDecision) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeTxnDecision) ProtoMessage() {}
+
+func (x *NodeTxnDecision) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[95]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeTxnDecision.ProtoReflect.Descriptor instead.
+func (*NodeTxnDecision) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{95}
+}
+
+func (x *NodeTxnDecision) GetTxnId() uint64 {
+	if x != nil {
+		return x.TxnId
+	}
+	return 0
+}
+
+type NodeTxnDecisionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NodeTxnDecisionResponse) Reset() {
+	*x = NodeTxnDecisionResponse{}
+	mi := &file_badies_proto_msgTypes[96]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NodeTxnDecisionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NodeTxnDecisionResponse) ProtoMessage() {}
+
+func (x *NodeTxnDecisionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[96]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NodeTxnDecisionResponse.ProtoReflect.Descriptor instead.
+func (*NodeTxnDecisionResponse) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{96}
+}
+
+// RingTopology is the hash ring the coordinator keeps in its metadata
+// store, so that membership changed at runtime survives a restart
+type RingTopology struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Epoch         uint64                 `protobuf:"varint,1,opt,name=epoch,proto3" json:"epoch,omitempty"`            // bumped by every membership change
+	Vnodes        int32                  `protobuf:"varint,2,opt,name=vnodes,proto3" json:"vnodes,omitempty"`          // virtual nodes per unit of weight
+	Nodes         []*RingNode            `protobuf:"bytes,3,rep,name=nodes,proto3" json:"nodes,omitempty"`             // in the order they joined
+	Partitioner   string                 `protobuf:"bytes,4,opt,name=partitioner,proto3" json:"partitioner,omitempty"` // consistent if empty
+	Hash          string                 `protobuf:"bytes,5,opt,name=hash,proto3" json:"hash,omitempty"`               // crc32 if empty
+	Partitions    int32                  `protobuf:"varint,6,opt,name=partitions,proto3" json:"partitions,omitempty"`  // fixed partitions of the jump and rendezvous partitioners
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RingTopology) Reset() {
+	*x = RingTopology{}
+	mi := &file_badies_proto_msgTypes[97]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RingTopology) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RingTopology) ProtoMessage() {}
+
+func (x *RingTopology) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[97]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RingTopology.ProtoReflect.Descriptor instead.
+func (*RingTopology) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{97}
+}
+
+func (x *RingTopology) GetEpoch() uint64 {
+	if x != nil {
+		return x.Epoch
+	}
+	return 0
+}
+
+func (x *RingTopology) GetVnodes() int32 {
+	if x != nil {
+		return x.Vnodes
+	}
+	return 0
+}
+
+func (x *RingTopology) GetNodes() []*RingNode {
+	if x != nil {
+		return x.Nodes
+	}
+	return nil
+}
+
+func (x *RingTopology) GetPartitioner() string {
+	if x != nil {
+		return x.Partitioner
+	}
+	return ""
+}
+
+func (x *RingTopology) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *RingTopology) GetPartitions() int32 {
+	if x != nil {
+		return x.Partitions
+	}
+	return 0
+}
+
+type RingNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	NodeId        string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	Weight        int32                  `protobuf:"varint,3,opt,name=weight,proto3" json:"weight,omitempty"`
+	Tokens        []uint32               `protobuf:"varint,4,rep,packed,name=tokens,proto3" json:"tokens,omitempty"` // positions of the node's virtual nodes
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RingNode) Reset() {
+	*x = RingNode{}
+	mi := &file_badies_proto_msgTypes[98]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RingNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RingNode) ProtoMessage() {}
+
+func (x *RingNode) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[98]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RingNode.ProtoReflect.Descriptor instead.
+func (*RingNode) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{98}
+}
+
+func (x *RingNode) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *RingNode) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *RingNode) GetWeight() int32 {
+	if x != nil {
+		return x.Weight
+	}
+	return 0
+}
+
+func (x *RingNode) GetTokens() []uint32 {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+// TxnLog is the coordinator's durable record of a transaction. It is
+// written before any node prepares and updated to committed once all of
+// them did; recovery aborts transactions that never committed and rolls
+// committed ones forward.
+type TxnLog struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TxnId         uint64                 `protobuf:"varint,1,opt,name=txn_id,json=txnId,proto3" json:"txn_id,omitempty"`
+	Committed     bool                   `protobuf:"varint,2,opt,name=committed,proto3" json:"committed,omitempty"`
+	Records       []*NodeRecord          `protobuf:"bytes,3,rep,name=records,proto3" json:"records,omitempty"`
+	Participants  []string               `protobuf:"bytes,4,rep,name=participants,proto3" json:"participants,omitempty"` // nodes asked to prepare; once committed, the ones that prepared
+	Unconfirmed   []string               `protobuf:"bytes,5,rep,name=unconfirmed,proto3" json:"unconfirmed,omitempty"`   // nodes that did not answer the prepare but may have staged it
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TxnLog) Reset() {
+	*x = TxnLog{}
+	mi := &file_badies_proto_msgTypes[99]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TxnLog) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TxnLog) ProtoMessage() {}
+
+func (x *TxnLog) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[99]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TxnLog.ProtoReflect.Descriptor instead.
+func (*TxnLog) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{99}
+}
+
+func (x *TxnLog) GetTxnId() uint64 {
+	if x != nil {
+		return x.TxnId
+	}
+	return 0
+}
+
+func (x *TxnLog) GetCommitted() bool {
+	if x != nil {
+		return x.Committed
+	}
+	return false
+}
+
+func (x *TxnLog) GetRecords() []*NodeRecord {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *TxnLog) GetParticipants() []string {
+	if x != nil {
+		return x.Participants
+	}
+	return nil
+}
+
+func (x *TxnLog) GetUnconfirmed() []string {
+	if x != nil {
+		return x.Unconfirmed
+	}
+	return nil
+}
+
+// RenameIntent is logged by the coordinator before a rename touches any
+// replica and rolled forward after a crash. Both writes use version.
+type RenameIntent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OldKey        []byte                 `protobuf:"bytes,1,opt,name=old_key,json=oldKey,proto3" json:"old_key,omitempty"`
+	NewKey        []byte                 `protobuf:"bytes,2,opt,name=new_key,json=newKey,proto3" json:"new_key,omitempty"`
+	Value         []byte                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	Version       uint64                 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	ExpiresAt     int64                  `protobuf:"varint,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // carried over from old_key
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenameIntent) Reset() {
+	*x = RenameIntent{}
+	mi := &file_badies_proto_msgTypes[100]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenameIntent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenameIntent) ProtoMessage() {}
+
+func (x *RenameIntent) ProtoReflect() protoreflect.Message {
+	mi := &file_badies_proto_msgTypes[100]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenameIntent.ProtoReflect.Descriptor instead.
+func (*RenameIntent) Descriptor() ([]byte, []int) {
+	return file_badies_proto_rawDescGZIP(), []int{100}
+}
+
+func (x *RenameIntent) GetOldKey() []byte {
+	if x != nil {
+		return x.OldKey
+	}
+	return nil
+}
+
+func (x *RenameIntent) GetNewKey() []byte {
+	if x != nil {
+		return x.NewKey
+	}
+	return nil
+}
+
+func (x *RenameIntent) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *RenameIntent) GetVersion() uint64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RenameIntent) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+var File_badies_proto protoreflect.FileDescriptor
+
+const file_badies_proto_rawDesc = "" +
+	"\n" +
+	"\fbadies.proto\x12\x06badies\":\n" +
+	"\n" +
+	"GetRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x04R\brevision\"K\n" +
 	"\n" +
 	"PutRequest\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value\"!\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x15\n" +
+	"\x06ttl_ms\x18\x03 \x01(\x03R\x05ttlMs\"!\n" +
 	"\rDeleteRequest\x12\x10\n" +
-	"\x03key\x18\x01 \x01(\tR\x03key\"D\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"j\n" +
 	"\x10UpdateKeyRequest\x12\x17\n" +
 	"\aold_key\x18\x01 \x01(\tR\x06oldKey\x12\x17\n" +
-	"\anew_key\x18\x02 \x01(\tR\x06newKey\"`\n" +
+	"\anew_key\x18\x02 \x01(\tR\x06newKey\x12$\n" +
+	"\x0efail_if_exists\x18\x03 \x01(\bR\ffailIfExists\"\x8b\x01\n" +
 	"\x12UpdateValueRequest\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x1b\n" +
 	"\told_value\x18\x02 \x01(\tR\boldValue\x12\x1b\n" +
-	"\tnew_value\x18\x03 \x01(\tR\bnewValue\"9\n" +
+	"\tnew_value\x18\x03 \x01(\tR\bnewValue\x12)\n" +
+	"\x10expected_version\x18\x04 \x01(\x04R\x0fexpectedVersion\"\x8b\x01\n" +
+	"\vScanRequest\x12\x1b\n" +
+	"\tstart_key\x18\x01 \x01(\tR\bstartKey\x12\x17\n" +
+	"\aend_key\x18\x02 \x01(\tR\x06endKey\x12\x16\n" +
+	"\x06prefix\x18\x03 \x01(\tR\x06prefix\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x18\n" +
+	"\areverse\x18\x05 \x01(\bR\areverse\"6\n" +
+	"\fScanResponse\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"2\n" +
+	"\bKeyValue\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\"=\n" +
+	"\x0fBatchPutRequest\x12*\n" +
+	"\aentries\x18\x01 \x03(\v2\x10.badies.KeyValueR\aentries\"a\n" +
+	"\tKeyResult\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x12\n" +
+	"\x04acks\x18\x03 \x01(\x05R\x04acks\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"?\n" +
+	"\x10BatchPutResponse\x12+\n" +
+	"\aresults\x18\x01 \x03(\v2\x11.badies.KeyResultR\aresults\"%\n" +
+	"\x0fBatchGetRequest\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\"_\n" +
+	"\tGetResult\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value\x12\x14\n" +
+	"\x05found\x18\x03 \x01(\bR\x05found\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"?\n" +
+	"\x10BatchGetResponse\x12+\n" +
+	"\aresults\x18\x01 \x03(\v2\x11.badies.GetResultR\aresults\"(\n" +
+	"\x12BatchDeleteRequest\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\tR\x04keys\"B\n" +
+	"\x13BatchDeleteResponse\x12+\n" +
+	"\aresults\x18\x01 \x03(\v2\x11.badies.KeyResultR\aresults\"8\n" +
+	"\rExpireRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x15\n" +
+	"\x06ttl_ms\x18\x02 \x01(\x03R\x05ttlMs\"*\n" +
+	"\x0eExpireResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"\"\n" +
+	"\x0ePersistRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\"+\n" +
+	"\x0fPersistResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"_\n" +
+	"\fWatchRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\bR\x06prefix\x12%\n" +
+	"\x0estart_revision\x18\x03 \x01(\x04R\rstartRevision\"\x9a\x01\n" +
+	"\n" +
+	"WatchEvent\x12+\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x17.badies.WatchEvent.TypeR\x04type\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x1a\n" +
+	"\brevision\x18\x04 \x01(\x04R\brevision\"\x1b\n" +
+	"\x04Type\x12\a\n" +
+	"\x03PUT\x10\x00\x12\n" +
+	"\n" +
+	"\x06DELETE\x10\x01\"8\n" +
+	"\x0eHistoryRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"w\n" +
+	"\n" +
+	"KeyVersion\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\tR\x05value\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x04R\brevision\x12\x18\n" +
+	"\adeleted\x18\x03 \x01(\bR\adeleted\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\x03R\texpiresAt\"p\n" +
+	"\x0fHistoryResponse\x12.\n" +
+	"\bversions\x18\x01 \x03(\v2\x12.badies.KeyVersionR\bversions\x12-\n" +
+	"\x12compacted_revision\x18\x02 \x01(\x04R\x11compactedRevision\"\xbc\x01\n" +
+	"\n" +
+	"TxnCompare\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x121\n" +
+	"\x06target\x18\x02 \x01(\x0e2\x19.badies.TxnCompare.TargetR\x06target\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x04R\aversion\"9\n" +
+	"\x06Target\x12\t\n" +
+	"\x05VALUE\x10\x00\x12\v\n" +
+	"\aVERSION\x10\x01\x12\n" +
+	"\n" +
+	"\x06EXISTS\x10\x02\x12\v\n" +
+	"\aMISSING\x10\x03\"\x8b\x01\n" +
+	"\x05TxnOp\x12&\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x12.badies.TxnOp.TypeR\x04type\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\tR\x05value\x12\x15\n" +
+	"\x06ttl_ms\x18\x04 \x01(\x03R\x05ttlMs\"\x1b\n" +
+	"\x04Type\x12\a\n" +
+	"\x03PUT\x10\x00\x12\n" +
+	"\n" +
+	"\x06DELETE\x10\x01\"]\n" +
+	"\n" +
+	"TxnRequest\x12.\n" +
+	"\bcompares\x18\x01 \x03(\v2\x12.badies.TxnCompareR\bcompares\x12\x1f\n" +
+	"\x03ops\x18\x02 \x03(\v2\r.badies.TxnOpR\x03ops\"l\n" +
+	"\vTxnResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x04R\brevision\x12'\n" +
+	"\x0ffailed_compares\x18\x03 \x03(\x05R\x0efailedCompares\"U\n" +
 	"\vGetResponse\x12\x14\n" +
 	"\x05value\x18\x01 \x01(\tR\x05value\x12\x14\n" +
-	"\x05found\x18\x02 \x01(\bR\x05found\"'\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x04R\brevision\"W\n" +
 	"\vPutResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"*\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x12\n" +
+	"\x04acks\x18\x02 \x01(\x05R\x04acks\x12\x1a\n" +
+	"\brevision\x18\x03 \x01(\x04R\brevision\"F\n" +
 	"\x0eDeleteResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"-\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x04R\brevision\"S\n" +
 	"\x11UpdateKeyResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"/\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12$\n" +
+	"\x0enew_key_exists\x18\x02 \x01(\bR\fnewKeyExists\"\x84\x01\n" +
 	"\x13UpdateValueResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess2\xab\x02\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12#\n" +
+	"\rcurrent_value\x18\x02 \x01(\tR\fcurrentValue\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x04R\aversion\x12\x14\n" +
+	"\x05found\x18\x04 \x01(\bR\x05found\"\xbe\x01\n" +
+	"\x0eNodePutRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\fR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x04R\aversion\x12 \n" +
+	"\vconditional\x18\x04 \x01(\bR\vconditional\x12)\n" +
+	"\x10expected_version\x18\x05 \x01(\x04R\x0fexpectedVersion\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x06 \x01(\x03R\texpiresAt\"\x8a\x01\n" +
+	"\x0fNodePutResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\aapplied\x18\x02 \x01(\bR\aapplied\x12\x1a\n" +
+	"\bconflict\x18\x03 \x01(\bR\bconflict\x12'\n" +
+	"\x0fcurrent_version\x18\x04 \x01(\x04R\x0ecurrentVersion\">\n" +
+	"\x0eNodeGetRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\fR\x03key\x12\x1a\n" +
+	"\brevision\x18\x02 \x01(\x04R\brevision\"\x94\x01\n" +
+	"\x0fNodeGetResponse\x12\x14\n" +
+	"\x05value\x18\x01 \x01(\fR\x05value\x12\x14\n" +
+	"\x05found\x18\x02 \x01(\bR\x05found\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x04R\aversion\x12\x1c\n" +
+	"\ttombstone\x18\x04 \x01(\bR\ttombstone\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\x03R\texpiresAt\"?\n" +
+	"\x11NodeDeleteRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\fR\x03key\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\"H\n" +
+	"\x12NodeDeleteResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
+	"\aapplied\x18\x02 \x01(\bR\aapplied\"E\n" +
+	"\x15NodeWriteBatchRequest\x12,\n" +
+	"\arecords\x18\x01 \x03(\v2\x12.badies.NodeRecordR\arecords\"2\n" +
+	"\x16NodeWriteBatchResponse\x12\x18\n" +
+	"\aapplied\x18\x01 \x03(\bR\aapplied\")\n" +
+	"\x13NodeGetBatchRequest\x12\x12\n" +
+	"\x04keys\x18\x01 \x03(\fR\x04keys\"I\n" +
+	"\x14NodeGetBatchResponse\x121\n" +
+	"\arecords\x18\x01 \x03(\v2\x17.badies.NodeGetResponseR\arecords\"y\n" +
+	"\x0fNodeScanRequest\x12\x1b\n" +
+	"\tstart_key\x18\x01 \x01(\fR\bstartKey\x12\x17\n" +
+	"\aend_key\x18\x02 \x01(\fR\x06endKey\x12\x16\n" +
+	"\x06prefix\x18\x03 \x01(\fR\x06prefix\x12\x18\n" +
+	"\areverse\x18\x04 \x01(\bR\areverse\"7\n" +
+	"\rNodeHashRange\x12\x14\n" +
+	"\x05start\x18\x01 \x01(\rR\x05start\x12\x10\n" +
+	"\x03end\x18\x02 \x01(\rR\x03end\"o\n" +
+	"\x10NodeRangeRequest\x12-\n" +
+	"\x06ranges\x18\x01 \x03(\v2\x15.badies.NodeHashRangeR\x06ranges\x12\x12\n" +
+	"\x04hash\x18\x02 \x01(\tR\x04hash\x12\x18\n" +
+	"\ahistory\x18\x03 \x01(\bR\ahistory\"\xb9\x01\n" +
+	"\n" +
+	"NodeRecord\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\fR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x04R\aversion\x12\x1c\n" +
+	"\ttombstone\x18\x04 \x01(\bR\ttombstone\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\x03R\texpiresAt\x12,\n" +
+	"\ahistory\x18\x06 \x03(\v2\x12.badies.NodeRecordR\ahistory\"9\n" +
+	"\x10NodeSweepRequest\x12%\n" +
+	"\x0eexpired_before\x18\x01 \x01(\x03R\rexpiredBefore\")\n" +
+	"\x11NodeSweepResponse\x12\x14\n" +
+	"\x05swept\x18\x01 \x01(\x03R\x05swept\"=\n" +
+	"\x13NodeVersionsRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\fR\x03key\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"s\n" +
+	"\x14NodeVersionsResponse\x12,\n" +
+	"\arecords\x18\x01 \x03(\v2\x12.badies.NodeRecordR\arecords\x12-\n" +
+	"\x12compacted_revision\x18\x02 \x01(\x04R\x11compactedRevision\"[\n" +
+	"\x17NodeKeepVersionsRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\fR\x03key\x12.\n" +
+	"\bversions\x18\x02 \x03(\v2\x12.badies.NodeRecordR\bversions\".\n" +
+	"\x18NodeKeepVersionsResponse\x12\x12\n" +
+	"\x04kept\x18\x01 \x01(\x05R\x04kept\">\n" +
+	"\x10NodePurgeRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\fR\x03key\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\"+\n" +
+	"\x11NodePurgeResponse\x12\x16\n" +
+	"\x06purged\x18\x01 \x01(\bR\x06purged\"j\n" +
+	"\x11NodeRevertRequest\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\fR\x03key\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\x12)\n" +
+	"\x10previous_version\x18\x03 \x01(\x04R\x0fpreviousVersion\"0\n" +
+	"\x12NodeRevertResponse\x12\x1a\n" +
+	"\breverted\x18\x01 \x01(\bR\breverted\"m\n" +
+	"\bNodeHint\x12\x16\n" +
+	"\x06target\x18\x01 \x01(\tR\x06target\x12*\n" +
+	"\x06record\x18\x02 \x01(\v2\x12.badies.NodeRecordR\x06record\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\x03R\texpiresAt\"/\n" +
+	"\x15NodeStoreHintResponse\x12\x16\n" +
+	"\x06stored\x18\x01 \x01(\bR\x06stored\"\x18\n" +
+	"\x16NodeStreamHintsRequest\"[\n" +
+	"\x15NodeDeleteHintRequest\x12\x16\n" +
+	"\x06target\x18\x01 \x01(\tR\x06target\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\fR\x03key\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x04R\aversion\"2\n" +
+	"\x16NodeDeleteHintResponse\x12\x18\n" +
+	"\adeleted\x18\x01 \x01(\bR\adeleted\"j\n" +
+	"\x11NodeMerkleRequest\x12+\n" +
+	"\x05range\x18\x01 \x01(\v2\x15.badies.NodeHashRangeR\x05range\x12\x14\n" +
+	"\x05depth\x18\x02 \x01(\rR\x05depth\x12\x12\n" +
+	"\x04hash\x18\x03 \x01(\tR\x04hash\",\n" +
+	"\x12NodeMerkleResponse\x12\x16\n" +
+	"\x06hashes\x18\x01 \x03(\fR\x06hashes\"c\n" +
+	"\rRepairRequest\x12\x19\n" +
+	"\anode_id\x18\x01 \x01(\tH\x00R\x06nodeId\x12-\n" +
+	"\x05range\x18\x02 \x01(\v2\x15.badies.NodeHashRangeH\x00R\x05rangeB\b\n" +
+	"\x06target\"\x87\x01\n" +
+	"\x0eRepairResponse\x12'\n" +
+	"\x0franges_compared\x18\x01 \x01(\x05R\x0erangesCompared\x12'\n" +
+	"\x0franges_repaired\x18\x02 \x01(\x05R\x0erangesRepaired\x12#\n" +
+	"\rkeys_repaired\x18\x03 \x01(\x03R\fkeysRepaired\"[\n" +
+	"\x0eAddNodeRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x05R\x06weight\"\x11\n" +
+	"\x0fAddNodeResponse\"B\n" +
+	"\x11RemoveNodeRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x14\n" +
+	"\x05drain\x18\x02 \x01(\bR\x05drain\"V\n" +
+	"\x12RemoveNodeResponse\x12\x1f\n" +
+	"\vkeys_copied\x18\x01 \x01(\x03R\n" +
+	"keysCopied\x12\x1f\n" +
+	"\vkeys_purged\x18\x02 \x01(\x03R\n" +
+	"keysPurged\"G\n" +
+	"\x12ReplaceNodeRequest\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\"\x15\n" +
+	"\x13ReplaceNodeResponse\"\x12\n" +
+	"\x10ListNodesRequest\"\xdc\x01\n" +
+	"\bNodeInfo\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x05R\x06weight\x12,\n" +
+	"\x05state\x18\x04 \x01(\x0e2\x16.badies.NodeInfo.StateR\x05state\x12*\n" +
+	"\x06health\x18\x05 \x01(\v2\x12.badies.NodeHealthR\x06health\"+\n" +
+	"\x05State\x12\n" +
+	"\n" +
+	"\x06ACTIVE\x10\x00\x12\f\n" +
+	"\bDRAINING\x10\x01\x12\b\n" +
+	"\x04DOWN\x10\x02\"\x82\x02\n" +
+	"\n" +
+	"NodeHealth\x124\n" +
+	"\acircuit\x18\x01 \x01(\x0e2\x1a.badies.NodeHealth.CircuitR\acircuit\x12\x1d\n" +
+	"\n" +
+	"error_rate\x18\x02 \x01(\x01R\terrorRate\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x03 \x01(\x01R\tlatencyMs\x121\n" +
+	"\x14consecutive_failures\x18\x04 \x01(\x05R\x13consecutiveFailures\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x05 \x01(\tR\tlastError\".\n" +
+	"\aCircuit\x12\n" +
+	"\n" +
+	"\x06CLOSED\x10\x00\x12\b\n" +
+	"\x04OPEN\x10\x01\x12\r\n" +
+	"\tHALF_OPEN\x10\x02\";\n" +
+	"\x11ListNodesResponse\x12&\n" +
+	"\x05nodes\x18\x01 \x03(\v2\x10.badies.NodeInfoR\x05nodes\"\xb7\x01\n" +
+	"\fGossipMember\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x120\n" +
+	"\x05state\x18\x03 \x01(\x0e2\x1a.badies.GossipMember.StateR\x05state\x12 \n" +
+	"\vincarnation\x18\x04 \x01(\x04R\vincarnation\")\n" +
+	"\x05State\x12\t\n" +
+	"\x05ALIVE\x10\x00\x12\v\n" +
+	"\aSUSPECT\x10\x01\x12\b\n" +
+	"\x04DEAD\x10\x02\"f\n" +
+	"\n" +
+	"GossipPing\x12(\n" +
+	"\x04from\x18\x01 \x01(\v2\x14.badies.GossipMemberR\x04from\x12.\n" +
+	"\aupdates\x18\x02 \x03(\v2\x14.badies.GossipMemberR\aupdates\"e\n" +
+	"\tGossipAck\x12(\n" +
+	"\x04from\x18\x01 \x01(\v2\x14.badies.GossipMemberR\x04from\x12.\n" +
+	"\aupdates\x18\x02 \x03(\v2\x14.badies.GossipMemberR\aupdates\"\x90\x01\n" +
+	"\rGossipPingReq\x12(\n" +
+	"\x04from\x18\x01 \x01(\v2\x14.badies.GossipMemberR\x04from\x12%\n" +
+	"\x0etarget_address\x18\x02 \x01(\tR\rtargetAddress\x12.\n" +
+	"\aupdates\x18\x03 \x03(\v2\x14.badies.GossipMemberR\aupdates\";\n" +
+	"\fNodeRaftPeer\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x12\n" +
+	"\x04addr\x18\x02 \x01(\tR\x04addr\"\x97\x01\n" +
+	"\rNodeRaftGroup\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\x04R\agroupId\x12*\n" +
+	"\x05peers\x18\x02 \x03(\v2\x14.badies.NodeRaftPeerR\x05peers\x12+\n" +
+	"\x05range\x18\x03 \x01(\v2\x15.badies.NodeHashRangeR\x05range\x12\x12\n" +
+	"\x04hash\x18\x04 \x01(\tR\x04hash\"1\n" +
+	"\x15NodeRaftStartResponse\x12\x18\n" +
+	"\astarted\x18\x01 \x01(\bR\astarted\"G\n" +
+	"\x10NodeRaftEnvelope\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\x04R\agroupId\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\fR\amessage\"\x19\n" +
+	"\x17NodeRaftMessageResponse\"\x92\x02\n" +
+	"\x0fNodeRaftCommand\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\x04R\trequestId\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\fR\x03key\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\fR\x05value\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x04R\aversion\x12\x1c\n" +
+	"\ttombstone\x18\x05 \x01(\bR\ttombstone\x12 \n" +
+	"\vconditional\x18\x06 \x01(\bR\vconditional\x12)\n" +
+	"\x10expected_version\x18\a \x01(\x04R\x0fexpectedVersion\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\x03R\texpiresAt\x12\x14\n" +
+	"\x05exact\x18\t \x01(\bR\x05exact\"\x9c\x02\n" +
+	"\x14NodeRaftWriteRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\x04R\agroupId\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\fR\x03key\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\fR\x05value\x12\x1c\n" +
+	"\ttombstone\x18\x04 \x01(\bR\ttombstone\x12 \n" +
+	"\vconditional\x18\x05 \x01(\bR\vconditional\x12)\n" +
+	"\x10expected_version\x18\x06 \x01(\x04R\x0fexpectedVersion\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\x03R\texpiresAt\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\b \x01(\x04R\trequestId\x12\x18\n" +
+	"\aversion\x18\t \x01(\x04R\aversion\"M\n" +
+	"\x15NodeRaftWriteResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x04R\aversion\x12\x1a\n" +
+	"\bconflict\x18\x02 \x01(\bR\bconflict\"\x96\x01\n" +
+	"\x10NodeRaftSnapshot\x12!\n" +
+	"\flast_version\x18\x01 \x01(\x04R\vlastVersion\x121\n" +
+	"\aapplied\x18\x02 \x03(\v2\x17.badies.NodeRaftAppliedR\aapplied\x12,\n" +
+	"\arecords\x18\x03 \x03(\v2\x12.badies.NodeRecordR\arecords\"|\n" +
+	"\x0fNodeRaftApplied\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x01 \x01(\x04R\trequestId\x12\x14\n" +
+	"\x05index\x18\x02 \x01(\x04R\x05index\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x04R\aversion\x12\x1a\n" +
+	"\bconflict\x18\x04 \x01(\bR\bconflict\"B\n" +
+	"\x13NodeRaftReadRequest\x12\x19\n" +
+	"\bgroup_id\x18\x01 \x01(\x04R\agroupId\x12\x10\n" +
+	"\x03key\x18\x02 \x01(\fR\x03key\":\n" +
+	"\fNodeTxnCheck\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\fR\x03key\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\"\x8a\x01\n" +
+	"\x15NodeTxnPrepareRequest\x12\x15\n" +
+	"\x06txn_id\x18\x01 \x01(\x04R\x05txnId\x12,\n" +
+	"\arecords\x18\x02 \x03(\v2\x12.badies.NodeRecordR\arecords\x12,\n" +
+	"\x06checks\x18\x03 \x03(\v2\x14.badies.NodeTxnCheckR\x06checks\"L\n" +
+	"\x16NodeTxnPrepareResponse\x12\x1a\n" +
+	"\bprepared\x18\x01 \x01(\bR\bprepared\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"(\n" +
+	"\x0fNodeTxnDecision\x12\x15\n" +
+	"\x06txn_id\x18\x01 \x01(\x04R\x05txnId\"\x19\n" +
+	"\x17NodeTxnDecisionResponse\"\xba\x01\n" +
+	"\fRingTopology\x12\x14\n" +
+	"\x05epoch\x18\x01 \x01(\x04R\x05epoch\x12\x16\n" +
+	"\x06vnodes\x18\x02 \x01(\x05R\x06vnodes\x12&\n" +
+	"\x05nodes\x18\x03 \x03(\v2\x10.badies.RingNodeR\x05nodes\x12 \n" +
+	"\vpartitioner\x18\x04 \x01(\tR\vpartitioner\x12\x12\n" +
+	"\x04hash\x18\x05 \x01(\tR\x04hash\x12\x1e\n" +
+	"\n" +
+	"partitions\x18\x06 \x01(\x05R\n" +
+	"partitions\"m\n" +
+	"\bRingNode\x12\x17\n" +
+	"\anode_id\x18\x01 \x01(\tR\x06nodeId\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x16\n" +
+	"\x06weight\x18\x03 \x01(\x05R\x06weight\x12\x16\n" +
+	"\x06tokens\x18\x04 \x03(\rR\x06tokens\"\xb1\x01\n" +
+	"\x06TxnLog\x12\x15\n" +
+	"\x06txn_id\x18\x01 \x01(\x04R\x05txnId\x12\x1c\n" +
+	"\tcommitted\x18\x02 \x01(\bR\tcommitted\x12,\n" +
+	"\arecords\x18\x03 \x03(\v2\x12.badies.NodeRecordR\arecords\x12\"\n" +
+	"\fparticipants\x18\x04 \x03(\tR\fparticipants\x12 \n" +
+	"\vunconfirmed\x18\x05 \x03(\tR\vunconfirmed\"\x8f\x01\n" +
+	"\fRenameIntent\x12\x17\n" +
+	"\aold_key\x18\x01 \x01(\fR\x06oldKey\x12\x17\n" +
+	"\anew_key\x18\x02 \x01(\fR\x06newKey\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\fR\x05value\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x04R\aversion\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\x03R\texpiresAt2\xbc\x06\n" +
 	"\x06KeyVal\x12.\n" +
 	"\x03Put\x12\x12.badies.PutRequest\x1a\x13.badies.PutResponse\x12.\n" +
 	"\x03Get\x12\x12.badies.GetRequest\x1a\x13.badies.GetResponse\x127\n" +
 	"\x06Delete\x12\x15.badies.DeleteRequest\x1a\x16.badies.DeleteResponse\x12@\n" +
 	"\tUpdateKey\x12\x18.badies.UpdateKeyRequest\x1a\x19.badies.UpdateKeyResponse\x12F\n" +
-	"\vUpdateValue\x12\x1a.badies.UpdateValueRequest\x1a\x1b.badies.UpdateValueResponseB*Z(github.com/1byinf8/KeyVal/proto/badiespbb\x06proto3"
+	"\vUpdateValue\x12\x1a.badies.UpdateValueRequest\x1a\x1b.badies.UpdateValueResponse\x123\n" +
+	"\x04Scan\x12\x13.badies.ScanRequest\x1a\x14.badies.ScanResponse0\x01\x127\n" +
+	"\x06Expire\x12\x15.badies.ExpireRequest\x1a\x16.badies.ExpireResponse\x12:\n" +
+	"\aPersist\x12\x16.badies.PersistRequest\x1a\x17.badies.PersistResponse\x12=\n" +
+	"\bBatchPut\x12\x17.badies.BatchPutRequest\x1a\x18.badies.BatchPutResponse\x12=\n" +
+	"\bBatchGet\x12\x17.badies.BatchGetRequest\x1a\x18.badies.BatchGetResponse\x12F\n" +
+	"\vBatchDelete\x12\x1a.badies.BatchDeleteRequest\x1a\x1b.badies.BatchDeleteResponse\x123\n" +
+	"\x05Watch\x12\x14.badies.WatchRequest\x1a\x12.badies.WatchEvent0\x01\x12:\n" +
+	"\aHistory\x12\x16.badies.HistoryRequest\x1a\x17.badies.HistoryResponse\x12.\n" +
+	"\x03Txn\x12\x12.badies.TxnRequest\x1a\x13.badies.TxnResponse2\xa5\f\n" +
+	"\vStorageNode\x126\n" +
+	"\x03Put\x12\x16.badies.NodePutRequest\x1a\x17.badies.NodePutResponse\x126\n" +
+	"\x03Get\x12\x16.badies.NodeGetRequest\x1a\x17.badies.NodeGetResponse\x12?\n" +
+	"\x06Delete\x12\x19.badies.NodeDeleteRequest\x1a\x1a.badies.NodeDeleteResponse\x12K\n" +
+	"\n" +
+	"WriteBatch\x12\x1d.badies.NodeWriteBatchRequest\x1a\x1e.badies.NodeWriteBatchResponse\x12E\n" +
+	"\bGetBatch\x12\x1b.badies.NodeGetBatchRequest\x1a\x1c.badies.NodeGetBatchResponse\x12=\n" +
+	"\vStreamRange\x12\x18.badies.NodeRangeRequest\x1a\x12.badies.NodeRecord0\x01\x125\n" +
+	"\x04Scan\x12\x17.badies.NodeScanRequest\x1a\x12.badies.NodeRecord0\x01\x12<\n" +
+	"\x05Purge\x12\x18.badies.NodePurgeRequest\x1a\x19.badies.NodePurgeResponse\x12?\n" +
+	"\x06Revert\x12\x19.badies.NodeRevertRequest\x1a\x1a.badies.NodeRevertResponse\x12<\n" +
+	"\x05Sweep\x12\x18.badies.NodeSweepRequest\x1a\x19.badies.NodeSweepResponse\x12E\n" +
+	"\bVersions\x12\x1b.badies.NodeVersionsRequest\x1a\x1c.badies.NodeVersionsResponse\x12Q\n" +
+	"\fKeepVersions\x12\x1f.badies.NodeKeepVersionsRequest\x1a .badies.NodeKeepVersionsResponse\x12K\n" +
+	"\n" +
+	"TxnPrepare\x12\x1d.badies.NodeTxnPrepareRequest\x1a\x1e.badies.NodeTxnPrepareResponse\x12E\n" +
+	"\tTxnCommit\x12\x17.badies.NodeTxnDecision\x1a\x1f.badies.NodeTxnDecisionResponse\x12D\n" +
+	"\bTxnAbort\x12\x17.badies.NodeTxnDecision\x1a\x1f.badies.NodeTxnDecisionResponse\x12<\n" +
+	"\tStoreHint\x12\x10.badies.NodeHint\x1a\x1d.badies.NodeStoreHintResponse\x12A\n" +
+	"\vStreamHints\x12\x1e.badies.NodeStreamHintsRequest\x1a\x10.badies.NodeHint0\x01\x12K\n" +
+	"\n" +
+	"DeleteHint\x12\x1d.badies.NodeDeleteHintRequest\x1a\x1e.badies.NodeDeleteHintResponse\x12C\n" +
+	"\n" +
+	"MerkleTree\x12\x19.badies.NodeMerkleRequest\x1a\x1a.badies.NodeMerkleResponse\x12A\n" +
+	"\tRaftStart\x12\x15.badies.NodeRaftGroup\x1a\x1d.badies.NodeRaftStartResponse\x12H\n" +
+	"\vRaftMessage\x12\x18.badies.NodeRaftEnvelope\x1a\x1f.badies.NodeRaftMessageResponse\x12H\n" +
+	"\tRaftWrite\x12\x1c.badies.NodeRaftWriteRequest\x1a\x1d.badies.NodeRaftWriteResponse\x12@\n" +
+	"\bRaftRead\x12\x1b.badies.NodeRaftReadRequest\x1a\x17.badies.NodeGetResponse2\xcb\x02\n" +
+	"\x05Admin\x127\n" +
+	"\x06Repair\x12\x15.badies.RepairRequest\x1a\x16.badies.RepairResponse\x12:\n" +
+	"\aAddNode\x12\x16.badies.AddNodeRequest\x1a\x17.badies.AddNodeResponse\x12C\n" +
+	"\n" +
+	"RemoveNode\x12\x19.badies.RemoveNodeRequest\x1a\x1a.badies.RemoveNodeResponse\x12F\n" +
+	"\vReplaceNode\x12\x1a.badies.ReplaceNodeRequest\x1a\x1b.badies.ReplaceNodeResponse\x12@\n" +
+	"\tListNodes\x12\x18.badies.ListNodesRequest\x1a\x19.badies.ListNodesResponse2l\n" +
+	"\x06Gossip\x12-\n" +
+	"\x04Ping\x12\x12.badies.GossipPing\x1a\x11.badies.GossipAck\x123\n" +
+	"\aPingReq\x12\x15.badies.GossipPingReq\x1a\x11.badies.GossipAckB*Z(github.com/1byinf8/KeyVal/proto/badiespbb\x06proto3"
 
 var (
 	file_badies_proto_rawDescOnce sync.Once
@@ -552,35 +6533,249 @@ func file_badies_proto_rawDescGZIP() []byte {
 	return file_badies_proto_rawDescData
 }
 
-var file_badies_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_badies_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_badies_proto_msgTypes = make([]protoimpl.MessageInfo, 101)
 var file_badies_proto_goTypes = []any{
-	(*GetRequest)(nil),          // 0: badies.GetRequest
-	(*PutRequest)(nil),          // 1: badies.PutRequest
-	(*DeleteRequest)(nil),       // 2: badies.DeleteRequest
-	(*UpdateKeyRequest)(nil),    // 3: badies.UpdateKeyRequest
-	(*UpdateValueRequest)(nil),  // 4: badies.UpdateValueRequest
-	(*GetResponse)(nil),         // 5: badies.GetResponse
-	(*PutResponse)(nil),         // 6: badies.PutResponse
-	(*DeleteResponse)(nil),      // 7: badies.DeleteResponse
-	(*UpdateKeyResponse)(nil),   // 8: badies.UpdateKeyResponse
-	(*UpdateValueResponse)(nil), // 9: badies.UpdateValueResponse
+	(WatchEvent_Type)(0),             // 0: badies.WatchEvent.Type
+	(TxnCompare_Target)(0),           // 1: badies.TxnCompare.Target
+	(TxnOp_Type)(0),                  // 2: badies.TxnOp.Type
+	(NodeInfo_State)(0),              // 3: badies.NodeInfo.State
+	(NodeHealth_Circuit)(0),          // 4: badies.NodeHealth.Circuit
+	(GossipMember_State)(0),          // 5: badies.GossipMember.State
+	(*GetRequest)(nil),               // 6: badies.GetRequest
+	(*PutRequest)(nil),               // 7: badies.PutRequest
+	(*DeleteRequest)(nil),            // 8: badies.DeleteRequest
+	(*UpdateKeyRequest)(nil),         // 9: badies.UpdateKeyRequest
+	(*UpdateValueRequest)(nil),       // 10: badies.UpdateValueRequest
+	(*ScanRequest)(nil),              // 11: badies.ScanRequest
+	(*ScanResponse)(nil),             // 12: badies.ScanResponse
+	(*KeyValue)(nil),                 // 13: badies.KeyValue
+	(*BatchPutRequest)(nil),          // 14: badies.BatchPutRequest
+	(*KeyResult)(nil),                // 15: badies.KeyResult
+	(*BatchPutResponse)(nil),         // 16: badies.BatchPutResponse
+	(*BatchGetRequest)(nil),          // 17: badies.BatchGetRequest
+	(*GetResult)(nil),                // 18: badies.GetResult
+	(*BatchGetResponse)(nil),         // 19: badies.BatchGetResponse
+	(*BatchDeleteRequest)(nil),       // 20: badies.BatchDeleteRequest
+	(*BatchDeleteResponse)(nil),      // 21: badies.BatchDeleteResponse
+	(*ExpireRequest)(nil),            // 22: badies.ExpireRequest
+	(*ExpireResponse)(nil),           // 23: badies.ExpireResponse
+	(*PersistRequest)(nil),           // 24: badies.PersistRequest
+	(*PersistResponse)(nil),          // 25: badies.PersistResponse
+	(*WatchRequest)(nil),             // 26: badies.WatchRequest
+	(*WatchEvent)(nil),               // 27: badies.WatchEvent
+	(*HistoryRequest)(nil),           // 28: badies.HistoryRequest
+	(*KeyVersion)(nil),               // 29: badies.KeyVersion
+	(*HistoryResponse)(nil),          // 30: badies.HistoryResponse
+	(*TxnCompare)(nil),               // 31: badies.TxnCompare
+	(*TxnOp)(nil),                    // 32: badies.TxnOp
+	(*TxnRequest)(nil),               // 33: badies.TxnRequest
+	(*TxnResponse)(nil),              // 34: badies.TxnResponse
+	(*GetResponse)(nil),              // 35: badies.GetResponse
+	(*PutResponse)(nil),              // 36: badies.PutResponse
+	(*DeleteResponse)(nil),           // 37: badies.DeleteResponse
+	(*UpdateKeyResponse)(nil),        // 38: badies.UpdateKeyResponse
+	(*UpdateValueResponse)(nil),      // 39: badies.UpdateValueResponse
+	(*NodePutRequest)(nil),           // 40: badies.NodePutRequest
+	(*NodePutResponse)(nil),          // 41: badies.NodePutResponse
+	(*NodeGetRequest)(nil),           // 42: badies.NodeGetRequest
+	(*NodeGetResponse)(nil),          // 43: badies.NodeGetResponse
+	(*NodeDeleteRequest)(nil),        // 44: badies.NodeDeleteRequest
+	(*NodeDeleteResponse)(nil),       // 45: badies.NodeDeleteResponse
+	(*NodeWriteBatchRequest)(nil),    // 46: badies.NodeWriteBatchRequest
+	(*NodeWriteBatchResponse)(nil),   // 47: badies.NodeWriteBatchResponse
+	(*NodeGetBatchRequest)(nil),      // 48: badies.NodeGetBatchRequest
+	(*NodeGetBatchResponse)(nil),     // 49: badies.NodeGetBatchResponse
+	(*NodeScanRequest)(nil),          // 50: badies.NodeScanRequest
+	(*NodeHashRange)(nil),            // 51: badies.NodeHashRange
+	(*NodeRangeRequest)(nil),         // 52: badies.NodeRangeRequest
+	(*NodeRecord)(nil),               // 53: badies.NodeRecord
+	(*NodeSweepRequest)(nil),         // 54: badies.NodeSweepRequest
+	(*NodeSweepResponse)(nil),        // 55: badies.NodeSweepResponse
+	(*NodeVersionsRequest)(nil),      // 56: badies.NodeVersionsRequest
+	(*NodeVersionsResponse)(nil),     // 57: badies.NodeVersionsResponse
+	(*NodeKeepVersionsRequest)(nil),  // 58: badies.NodeKeepVersionsRequest
+	(*NodeKeepVersionsResponse)(nil), // 59: badies.NodeKeepVersionsResponse
+	(*NodePurgeRequest)(nil),         // 60: badies.NodePurgeRequest
+	(*NodePurgeResponse)(nil),        // 61: badies.NodePurgeResponse
+	(*NodeRevertRequest)(nil),        // 62: badies.NodeRevertRequest
+	(*NodeRevertResponse)(nil),       // 63: badies.NodeRevertResponse
+	(*NodeHint)(nil),                 // 64: badies.NodeHint
+	(*NodeStoreHintResponse)(nil),    // 65: badies.NodeStoreHintResponse
+	(*NodeStreamHintsRequest)(nil),   // 66: badies.NodeStreamHintsRequest
+	(*NodeDeleteHintRequest)(nil),    // 67: badies.NodeDeleteHintRequest
+	(*NodeDeleteHintResponse)(nil),   // 68: badies.NodeDeleteHintResponse
+	(*NodeMerkleRequest)(nil),        // 69: badies.NodeMerkleRequest
+	(*NodeMerkleResponse)(nil),       // 70: badies.NodeMerkleResponse
+	(*RepairRequest)(nil),            // 71: badies.RepairRequest
+	(*RepairResponse)(nil),           // 72: badies.RepairResponse
+	(*AddNodeRequest)(nil),           // 73: badies.AddNodeRequest
+	(*AddNodeResponse)(nil),          // 74: badies.AddNodeResponse
+	(*RemoveNodeRequest)(nil),        // 75: badies.RemoveNodeRequest
+	(*RemoveNodeResponse)(nil),       // 76: badies.RemoveNodeResponse
+	(*ReplaceNodeRequest)(nil),       // 77: badies.ReplaceNodeRequest
+	(*ReplaceNodeResponse)(nil),      // 78: badies.ReplaceNodeResponse
+	(*ListNodesRequest)(nil),         // 79: badies.ListNodesRequest
+	(*NodeInfo)(nil),                 // 80: badies.NodeInfo
+	(*NodeHealth)(nil),               // 81: badies.NodeHealth
+	(*ListNodesResponse)(nil),        // 82: badies.ListNodesResponse
+	(*GossipMember)(nil),             // 83: badies.GossipMember
+	(*GossipPing)(nil),               // 84: badies.GossipPing
+	(*GossipAck)(nil),                // 85: badies.GossipAck
+	(*GossipPingReq)(nil),            // 86: badies.GossipPingReq
+	(*NodeRaftPeer)(nil),             // 87: badies.NodeRaftPeer
+	(*NodeRaftGroup)(nil),            // 88: badies.NodeRaftGroup
+	(*NodeRaftStartResponse)(nil),    // 89: badies.NodeRaftStartResponse
+	(*NodeRaftEnvelope)(nil),         // 90: badies.NodeRaftEnvelope
+	(*NodeRaftMessageResponse)(nil),  // 91: badies.NodeRaftMessageResponse
+	(*NodeRaftCommand)(nil),          // 92: badies.NodeRaftCommand
+	(*NodeRaftWriteRequest)(nil),     // 93: badies.NodeRaftWriteRequest
+	(*NodeRaftWriteResponse)(nil),    // 94: badies.NodeRaftWriteResponse
+	(*NodeRaftSnapshot)(nil),         // 95: badies.NodeRaftSnapshot
+	(*NodeRaftApplied)(nil),          // 96: badies.NodeRaftApplied
+	(*NodeRaftReadRequest)(nil),      // 97: badies.NodeRaftReadRequest
+	(*NodeTxnCheck)(nil),             // 98: badies.NodeTxnCheck
+	(*NodeTxnPrepareRequest)(nil),    // 99: badies.NodeTxnPrepareRequest
+	(*NodeTxnPrepareResponse)(nil),   // 100: badies.NodeTxnPrepareResponse
+	(*NodeTxnDecision)(nil),          // 101: badies.NodeTxnDecision
+	(*NodeTxnDecisionResponse)(nil),  // 102: badies.NodeTxnDecisionResponse
+	(*RingTopology)(nil),             // 103: badies.RingTopology
+	(*RingNode)(nil),                 // 104: badies.RingNode
+	(*TxnLog)(nil),                   // 105: badies.TxnLog
+	(*RenameIntent)(nil),             // 106: badies.RenameIntent
 }
 var file_badies_proto_depIdxs = []int32{
-	1, // 0: badies.KeyVal.Put:input_type -> badies.PutRequest
-	0, // 1: badies.KeyVal.Get:input_type -> badies.GetRequest
-	2, // 2: badies.KeyVal.Delete:input_type -> badies.DeleteRequest
-	3, // 3: badies.KeyVal.UpdateKey:input_type -> badies.UpdateKeyRequest
-	4, // 4: badies.KeyVal.UpdateValue:input_type -> badies.UpdateValueRequest
-	6, // 5: badies.KeyVal.Put:output_type -> badies.PutResponse
-	5, // 6: badies.KeyVal.Get:output_type -> badies.GetResponse
-	7, // 7: badies.KeyVal.Delete:output_type -> badies.DeleteResponse
-	8, // 8: badies.KeyVal.UpdateKey:output_type -> badies.UpdateKeyResponse
-	9, // 9: badies.KeyVal.UpdateValue:output_type -> badies.UpdateValueResponse
-	5, // [5:10] is the sub-list for method output_type
-	0, // [0:5] is the sub-list for method input_type
-	0, // [0:0] is the sub-list for extension type_name
-	0, // [0:0] is the sub-list for extension extendee
-	0, // [0:0] is the sub-list for field type_name
+	13,  // 0: badies.BatchPutRequest.entries:type_name -> badies.KeyValue
+	15,  // 1: badies.BatchPutResponse.results:type_name -> badies.KeyResult
+	18,  // 2: badies.BatchGetResponse.results:type_name -> badies.GetResult
+	15,  // 3: badies.BatchDeleteResponse.results:type_name -> badies.KeyResult
+	0,   // 4: badies.WatchEvent.type:type_name -> badies.WatchEvent.Type
+	29,  // 5: badies.HistoryResponse.versions:type_name -> badies.KeyVersion
+	1,   // 6: badies.TxnCompare.target:type_name -> badies.TxnCompare.Target
+	2,   // 7: badies.TxnOp.type:type_name -> badies.TxnOp.Type
+	31,  // 8: badies.TxnRequest.compares:type_name -> badies.TxnCompare
+	32,  // 9: badies.TxnRequest.ops:type_name -> badies.TxnOp
+	53,  // 10: badies.NodeWriteBatchRequest.records:type_name -> badies.NodeRecord
+	43,  // 11: badies.NodeGetBatchResponse.records:type_name -> badies.NodeGetResponse
+	51,  // 12: badies.NodeRangeRequest.ranges:type_name -> badies.NodeHashRange
+	53,  // 13: badies.NodeRecord.history:type_name -> badies.NodeRecord
+	53,  // 14: badies.NodeVersionsResponse.records:type_name -> badies.NodeRecord
+	53,  // 15: badies.NodeKeepVersionsRequest.versions:type_name -> badies.NodeRecord
+	53,  // 16: badies.NodeHint.record:type_name -> badies.NodeRecord
+	51,  // 17: badies.NodeMerkleRequest.range:type_name -> badies.NodeHashRange
+	51,  // 18: badies.RepairRequest.range:type_name -> badies.NodeHashRange
+	3,   // 19: badies.NodeInfo.state:type_name -> badies.NodeInfo.State
+	81,  // 20: badies.NodeInfo.health:type_name -> badies.NodeHealth
+	4,   // 21: badies.NodeHealth.circuit:type_name -> badies.NodeHealth.Circuit
+	80,  // 22: badies.ListNodesResponse.nodes:type_name -> badies.NodeInfo
+	5,   // 23: badies.GossipMember.state:type_name -> badies.GossipMember.State
+	83,  // 24: badies.GossipPing.from:type_name -> badies.GossipMember
+	83,  // 25: badies.GossipPing.updates:type_name -> badies.GossipMember
+	83,  // 26: badies.GossipAck.from:type_name -> badies.GossipMember
+	83,  // 27: badies.GossipAck.updates:type_name -> badies.GossipMember
+	83,  // 28: badies.GossipPingReq.from:type_name -> badies.GossipMember
+	83,  // 29: badies.GossipPingReq.updates:type_name -> badies.GossipMember
+	87,  // 30: badies.NodeRaftGroup.peers:type_name -> badies.NodeRaftPeer
+	51,  // 31: badies.NodeRaftGroup.range:type_name -> badies.NodeHashRange
+	96,  // 32: badies.NodeRaftSnapshot.applied:type_name -> badies.NodeRaftApplied
+	53,  // 33: badies.NodeRaftSnapshot.records:type_name -> badies.NodeRecord
+	53,  // 34: badies.NodeTxnPrepareRequest.records:type_name -> badies.NodeRecord
+	98,  // 35: badies.NodeTxnPrepareRequest.checks:type_name -> badies.NodeTxnCheck
+	104, // 36: badies.RingTopology.nodes:type_name -> badies.RingNode
+	53,  // 37: badies.TxnLog.records:type_name -> badies.NodeRecord
+	7,   // 38: badies.KeyVal.Put:input_type -> badies.PutRequest
+	6,   // 39: badies.KeyVal.Get:input_type -> badies.GetRequest
+	8,   // 40: badies.KeyVal.Delete:input_type -> badies.DeleteRequest
+	9,   // 41: badies.KeyVal.UpdateKey:input_type -> badies.UpdateKeyRequest
+	10,  // 42: badies.KeyVal.UpdateValue:input_type -> badies.UpdateValueRequest
+	11,  // 43: badies.KeyVal.Scan:input_type -> badies.ScanRequest
+	22,  // 44: badies.KeyVal.Expire:input_type -> badies.ExpireRequest
+	24,  // 45: badies.KeyVal.Persist:input_type -> badies.PersistRequest
+	14,  // 46: badies.KeyVal.BatchPut:input_type -> badies.BatchPutRequest
+	17,  // 47: badies.KeyVal.BatchGet:input_type -> badies.BatchGetRequest
+	20,  // 48: badies.KeyVal.BatchDelete:input_type -> badies.BatchDeleteRequest
+	26,  // 49: badies.KeyVal.Watch:input_type -> badies.WatchRequest
+	28,  // 50: badies.KeyVal.History:input_type -> badies.HistoryRequest
+	33,  // 51: badies.KeyVal.Txn:input_type -> badies.TxnRequest
+	40,  // 52: badies.StorageNode.Put:input_type -> badies.NodePutRequest
+	42,  // 53: badies.StorageNode.Get:input_type -> badies.NodeGetRequest
+	44,  // 54: badies.StorageNode.Delete:input_type -> badies.NodeDeleteRequest
+	46,  // 55: badies.StorageNode.WriteBatch:input_type -> badies.NodeWriteBatchRequest
+	48,  // 56: badies.StorageNode.GetBatch:input_type -> badies.NodeGetBatchRequest
+	52,  // 57: badies.StorageNode.StreamRange:input_type -> badies.NodeRangeRequest
+	50,  // 58: badies.StorageNode.Scan:input_type -> badies.NodeScanRequest
+	60,  // 59: badies.StorageNode.Purge:input_type -> badies.NodePurgeRequest
+	62,  // 60: badies.StorageNode.Revert:input_type -> badies.NodeRevertRequest
+	54,  // 61: badies.StorageNode.Sweep:input_type -> badies.NodeSweepRequest
+	56,  // 62: badies.StorageNode.Versions:input_type -> badies.NodeVersionsRequest
+	58,  // 63: badies.StorageNode.KeepVersions:input_type -> badies.NodeKeepVersionsRequest
+	99,  // 64: badies.StorageNode.TxnPrepare:input_type -> badies.NodeTxnPrepareRequest
+	101, // 65: badies.StorageNode.TxnCommit:input_type -> badies.NodeTxnDecision
+	101, // 66: badies.StorageNode.TxnAbort:input_type -> badies.NodeTxnDecision
+	64,  // 67: badies.StorageNode.StoreHint:input_type -> badies.NodeHint
+	66,  // 68: badies.StorageNode.StreamHints:input_type -> badies.NodeStreamHintsRequest
+	67,  // 69: badies.StorageNode.DeleteHint:input_type -> badies.NodeDeleteHintRequest
+	69,  // 70: badies.StorageNode.MerkleTree:input_type -> badies.NodeMerkleRequest
+	88,  // 71: badies.StorageNode.RaftStart:input_type -> badies.NodeRaftGroup
+	90,  // 72: badies.StorageNode.RaftMessage:input_type -> badies.NodeRaftEnvelope
+	93,  // 73: badies.StorageNode.RaftWrite:input_type -> badies.NodeRaftWriteRequest
+	97,  // 74: badies.StorageNode.RaftRead:input_type -> badies.NodeRaftReadRequest
+	71,  // 75: badies.Admin.Repair:input_type -> badies.RepairRequest
+	73,  // 76: badies.Admin.AddNode:input_type -> badies.AddNodeRequest
+	75,  // 77: badies.Admin.RemoveNode:input_type -> badies.RemoveNodeRequest
+	77,  // 78: badies.Admin.ReplaceNode:input_type -> badies.ReplaceNodeRequest
+	79,  // 79: badies.Admin.ListNodes:input_type -> badies.ListNodesRequest
+	84,  // 80: badies.Gossip.Ping:input_type -> badies.GossipPing
+	86,  // 81: badies.Gossip.PingReq:input_type -> badies.GossipPingReq
+	36,  // 82: badies.KeyVal.Put:output_type -> badies.PutResponse
+	35,  // 83: badies.KeyVal.Get:output_type -> badies.GetResponse
+	37,  // 84: badies.KeyVal.Delete:output_type -> badies.DeleteResponse
+	38,  // 85: badies.KeyVal.UpdateKey:output_type -> badies.UpdateKeyResponse
+	39,  // 86: badies.KeyVal.UpdateValue:output_type -> badies.UpdateValueResponse
+	12,  // 87: badies.KeyVal.Scan:output_type -> badies.ScanResponse
+	23,  // 88: badies.KeyVal.Expire:output_type -> badies.ExpireResponse
+	25,  // 89: badies.KeyVal.Persist:output_type -> badies.PersistResponse
+	16,  // 90: badies.KeyVal.BatchPut:output_type -> badies.BatchPutResponse
+	19,  // 91: badies.KeyVal.BatchGet:output_type -> badies.BatchGetResponse
+	21,  // 92: badies.KeyVal.BatchDelete:output_type -> badies.BatchDeleteResponse
+	27,  // 93: badies.KeyVal.Watch:output_type -> badies.WatchEvent
+	30,  // 94: badies.KeyVal.History:output_type -> badies.HistoryResponse
+	34,  // 95: badies.KeyVal.Txn:output_type -> badies.TxnResponse
+	41,  // 96: badies.StorageNode.Put:output_type -> badies.NodePutResponse
+	43,  // 97: badies.StorageNode.Get:output_type -> badies.NodeGetResponse
+	45,  // 98: badies.StorageNode.Delete:output_type -> badies.NodeDeleteResponse
+	47,  // 99: badies.StorageNode.WriteBatch:output_type -> badies.NodeWriteBatchResponse
+	49,  // 100: badies.StorageNode.GetBatch:output_type -> badies.NodeGetBatchResponse
+	53,  // 101: badies.StorageNode.StreamRange:output_type -> badies.NodeRecord
+	53,  // 102: badies.StorageNode.Scan:output_type -> badies.NodeRecord
+	61,  // 103: badies.StorageNode.Purge:output_type -> badies.NodePurgeResponse
+	63,  // 104: badies.StorageNode.Revert:output_type -> badies.NodeRevertResponse
+	55,  // 105: badies.StorageNode.Sweep:output_type -> badies.NodeSweepResponse
+	57,  // 106: badies.StorageNode.Versions:output_type -> badies.NodeVersionsResponse
+	59,  // 107: badies.StorageNode.KeepVersions:output_type -> badies.NodeKeepVersionsResponse
+	100, // 108: badies.StorageNode.TxnPrepare:output_type -> badies.NodeTxnPrepareResponse
+	102, // 109: badies.StorageNode.TxnCommit:output_type -> badies.NodeTxnDecisionResponse
+	102, // 110: badies.StorageNode.TxnAbort:output_type -> badies.NodeTxnDecisionResponse
+	65,  // 111: badies.StorageNode.StoreHint:output_type -> badies.NodeStoreHintResponse
+	64,  // 112: badies.StorageNode.StreamHints:output_type -> badies.NodeHint
+	68,  // 113: badies.StorageNode.DeleteHint:output_type -> badies.NodeDeleteHintResponse
+	70,  // 114: badies.StorageNode.MerkleTree:output_type -> badies.NodeMerkleResponse
+	89,  // 115: badies.StorageNode.RaftStart:output_type -> badies.NodeRaftStartResponse
+	91,  // 116: badies.StorageNode.RaftMessage:output_type -> badies.NodeRaftMessageResponse
+	94,  // 117: badies.StorageNode.RaftWrite:output_type -> badies.NodeRaftWriteResponse
+	43,  // 118: badies.StorageNode.RaftRead:output_type -> badies.NodeGetResponse
+	72,  // 119: badies.Admin.Repair:output_type -> badies.RepairResponse
+	74,  // 120: badies.Admin.AddNode:output_type -> badies.AddNodeResponse
+	76,  // 121: badies.Admin.RemoveNode:output_type -> badies.RemoveNodeResponse
+	78,  // 122: badies.Admin.ReplaceNode:output_type -> badies.ReplaceNodeResponse
+	82,  // 123: badies.Admin.ListNodes:output_type -> badies.ListNodesResponse
+	85,  // 124: badies.Gossip.Ping:output_type -> badies.GossipAck
+	85,  // 125: badies.Gossip.PingReq:output_type -> badies.GossipAck
+	82,  // [82:126] is the sub-list for method output_type
+	38,  // [38:82] is the sub-list for method input_type
+	38,  // [38:38] is the sub-list for extension type_name
+	38,  // [38:38] is the sub-list for extension extendee
+	0,   // [0:38] is the sub-list for field type_name
 }
 
 func init() { file_badies_proto_init() }
@@ -588,18 +6783,23 @@ func file_badies_proto_init() {
 	if File_badies_proto != nil {
 		return
 	}
+	file_badies_proto_msgTypes[65].OneofWrappers = []any{
+		(*RepairRequest_NodeId)(nil),
+		(*RepairRequest_Range)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_badies_proto_rawDesc), len(file_badies_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   10,
+			NumEnums:      6,
+			NumMessages:   101,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   4,
 		},
 		GoTypes:           file_badies_proto_goTypes,
 		DependencyIndexes: file_badies_proto_depIdxs,
+		EnumInfos:         file_badies_proto_enumTypes,
 		MessageInfos:      file_badies_proto_msgTypes,
 	}.Build()
 	File_badies_proto = out.File
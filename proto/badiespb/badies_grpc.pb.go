@@ -24,6 +24,15 @@ const (
 	KeyVal_Delete_FullMethodName      = "/badies.KeyVal/Delete"
 	KeyVal_UpdateKey_FullMethodName   = "/badies.KeyVal/UpdateKey"
 	KeyVal_UpdateValue_FullMethodName = "/badies.KeyVal/UpdateValue"
+	KeyVal_Scan_FullMethodName        = "/badies.KeyVal/Scan"
+	KeyVal_Expire_FullMethodName      = "/badies.KeyVal/Expire"
+	KeyVal_Persist_FullMethodName     = "/badies.KeyVal/Persist"
+	KeyVal_BatchPut_FullMethodName    = "/badies.KeyVal/BatchPut"
+	KeyVal_BatchGet_FullMethodName    = "/badies.KeyVal/BatchGet"
+	KeyVal_BatchDelete_FullMethodName = "/badies.KeyVal/BatchDelete"
+	KeyVal_Watch_FullMethodName       = "/badies.KeyVal/Watch"
+	KeyVal_History_FullMethodName     = "/badies.KeyVal/History"
+	KeyVal_Txn_FullMethodName         = "/badies.KeyVal/Txn"
 )
 
 // KeyValClient is the client API for KeyVal service.
@@ -35,6 +44,20 @@ type KeyValClient interface {
 	Delete(ctx context.Context, in *DeleteRequest, opts ...grpc.CallOption) (*DeleteResponse, error)
 	UpdateKey(ctx context.Context, in *UpdateKeyRequest, opts ...grpc.CallOption) (*UpdateKeyResponse, error)
 	UpdateValue(ctx context.Context, in *UpdateValueRequest, opts ...grpc.CallOption) (*UpdateValueResponse, error)
+	Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ScanResponse], error)
+	// Expire sets a TTL on an existing key, Persist removes it
+	Expire(ctx context.Context, in *ExpireRequest, opts ...grpc.CallOption) (*ExpireResponse, error)
+	Persist(ctx context.Context, in *PersistRequest, opts ...grpc.CallOption) (*PersistResponse, error)
+	// Batch calls report one result per key, in request order
+	BatchPut(ctx context.Context, in *BatchPutRequest, opts ...grpc.CallOption) (*BatchPutResponse, error)
+	BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error)
+	BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error)
+	// Watch streams the changes to a key, or to every key under a prefix
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error)
+	// History lists the kept versions of a key, newest first
+	History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error)
+	// Txn applies its operations atomically if all of its compares hold
+	Txn(ctx context.Context, in *TxnRequest, opts ...grpc.CallOption) (*TxnResponse, error)
 }
 
 type keyValClient struct {
@@ -95,6 +118,114 @@ func (c *keyValClient) UpdateValue(ctx context.Context, in *UpdateValueRequest,
 	return out, nil
 }
 
+func (c *keyValClient) Scan(ctx context.Context, in *ScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ScanResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &KeyVal_ServiceDesc.Streams[0], KeyVal_Scan_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ScanRequest, ScanResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type KeyVal_ScanClient = grpc.ServerStreamingClient[ScanResponse]
+
+func (c *keyValClient) Expire(ctx context.Context, in *ExpireRequest, opts ...grpc.CallOption) (*ExpireResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExpireResponse)
+	err := c.cc.Invoke(ctx, KeyVal_Expire_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyValClient) Persist(ctx context.Context, in *PersistRequest, opts ...grpc.CallOption) (*PersistResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PersistResponse)
+	err := c.cc.Invoke(ctx, KeyVal_Persist_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyValClient) BatchPut(ctx context.Context, in *BatchPutRequest, opts ...grpc.CallOption) (*BatchPutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchPutResponse)
+	err := c.cc.Invoke(ctx, KeyVal_BatchPut_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyValClient) BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchGetResponse)
+	err := c.cc.Invoke(ctx, KeyVal_BatchGet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyValClient) BatchDelete(ctx context.Context, in *BatchDeleteRequest, opts ...grpc.CallOption) (*BatchDeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BatchDeleteResponse)
+	err := c.cc.Invoke(ctx, KeyVal_BatchDelete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyValClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[WatchEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &KeyVal_ServiceDesc.Streams[1], KeyVal_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, WatchEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type KeyVal_WatchClient = grpc.ServerStreamingClient[WatchEvent]
+
+func (c *keyValClient) History(ctx context.Context, in *HistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoryResponse)
+	err := c.cc.Invoke(ctx, KeyVal_History_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *keyValClient) Txn(ctx context.Context, in *TxnRequest, opts ...grpc.CallOption) (*TxnResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TxnResponse)
+	err := c.cc.Invoke(ctx, KeyVal_Txn_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // KeyValServer is the server API for KeyVal service.
 // All implementations must embed UnimplementedKeyValServer
 // for forward compatibility.
@@ -104,6 +235,20 @@ type KeyValServer interface {
 	Delete(context.Context, *DeleteRequest) (*DeleteResponse, error)
 	UpdateKey(context.Context, *UpdateKeyRequest) (*UpdateKeyResponse, error)
 	UpdateValue(context.Context, *UpdateValueRequest) (*UpdateValueResponse, error)
+	Scan(*ScanRequest, grpc.ServerStreamingServer[ScanResponse]) error
+	// Expire sets a TTL on an existing key, Persist removes it
+	Expire(context.Context, *ExpireRequest) (*ExpireResponse, error)
+	Persist(context.Context, *PersistRequest) (*PersistResponse, error)
+	// Batch calls report one result per key, in request order
+	BatchPut(context.Context, *BatchPutRequest) (*BatchPutResponse, error)
+	BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error)
+	BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error)
+	// Watch streams the changes to a key, or to every key under a prefix
+	Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error
+	// History lists the kept versions of a key, newest first
+	History(context.Context, *HistoryRequest) (*HistoryResponse, error)
+	// Txn applies its operations atomically if all of its compares hold
+	Txn(context.Context, *TxnRequest) (*TxnResponse, error)
 	mustEmbedUnimplementedKeyValServer()
 }
 
@@ -129,6 +274,33 @@ func (UnimplementedKeyValServer) UpdateKey(context.Context, *UpdateKeyRequest) (
 func (UnimplementedKeyValServer) UpdateValue(context.Context, *UpdateValueRequest) (*UpdateValueResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateValue not implemented")
 }
+func (UnimplementedKeyValServer) Scan(*ScanRequest, grpc.ServerStreamingServer[ScanResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method Scan not implemented")
+}
+func (UnimplementedKeyValServer) Expire(context.Context, *ExpireRequest) (*ExpireResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Expire not implemented")
+}
+func (UnimplementedKeyValServer) Persist(context.Context, *PersistRequest) (*PersistResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Persist not implemented")
+}
+func (UnimplementedKeyValServer) BatchPut(context.Context, *BatchPutRequest) (*BatchPutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchPut not implemented")
+}
+func (UnimplementedKeyValServer) BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGet not implemented")
+}
+func (UnimplementedKeyValServer) BatchDelete(context.Context, *BatchDeleteRequest) (*BatchDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchDelete not implemented")
+}
+func (UnimplementedKeyValServer) Watch(*WatchRequest, grpc.ServerStreamingServer[WatchEvent]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedKeyValServer) History(context.Context, *HistoryRequest) (*HistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method History not implemented")
+}
+func (UnimplementedKeyValServer) Txn(context.Context, *TxnRequest) (*TxnResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Txn not implemented")
+}
 func (UnimplementedKeyValServer) mustEmbedUnimplementedKeyValServer() {}
 func (UnimplementedKeyValServer) testEmbeddedByValue()                {}
 
@@ -240,6 +412,154 @@ func _KeyVal_UpdateValue_Handler(srv interface{}, ctx context.Context, dec func(
 	return interceptor(ctx, in, info, handler)
 }
 
+func _KeyVal_Scan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ScanRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(KeyValServer).Scan(m, &grpc.GenericServerStream[ScanRequest, ScanResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type KeyVal_ScanServer = grpc.ServerStreamingServer[ScanResponse]
+
+func _KeyVal_Expire_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExpireRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyValServer).Expire(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyVal_Expire_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyValServer).Expire(ctx, req.(*ExpireRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyVal_Persist_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PersistRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyValServer).Persist(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyVal_Persist_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyValServer).Persist(ctx, req.(*PersistRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyVal_BatchPut_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchPutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyValServer).BatchPut(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyVal_BatchPut_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyValServer).BatchPut(ctx, req.(*BatchPutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyVal_BatchGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyValServer).BatchGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyVal_BatchGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyValServer).BatchGet(ctx, req.(*BatchGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyVal_BatchDelete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyValServer).BatchDelete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyVal_BatchDelete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyValServer).BatchDelete(ctx, req.(*BatchDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyVal_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(KeyValServer).Watch(m, &grpc.GenericServerStream[WatchRequest, WatchEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type KeyVal_WatchServer = grpc.ServerStreamingServer[WatchEvent]
+
+func _KeyVal_History_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyValServer).History(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyVal_History_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyValServer).History(ctx, req.(*HistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _KeyVal_Txn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TxnRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(KeyValServer).Txn(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: KeyVal_Txn_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(KeyValServer).Txn(ctx, req.(*TxnRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // KeyVal_ServiceDesc is the grpc.ServiceDesc for KeyVal service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -267,6 +587,1451 @@ var KeyVal_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateValue",
 			Handler:    _KeyVal_UpdateValue_Handler,
 		},
+		{
+			MethodName: "Expire",
+			Handler:    _KeyVal_Expire_Handler,
+		},
+		{
+			MethodName: "Persist",
+			Handler:    _KeyVal_Persist_Handler,
+		},
+		{
+			MethodName: "BatchPut",
+			Handler:    _KeyVal_BatchPut_Handler,
+		},
+		{
+			MethodName: "BatchGet",
+			Handler:    _KeyVal_BatchGet_Handler,
+		},
+		{
+			MethodName: "BatchDelete",
+			Handler:    _KeyVal_BatchDelete_Handler,
+		},
+		{
+			MethodName: "History",
+			Handler:    _KeyVal_History_Handler,
+		},
+		{
+			MethodName: "Txn",
+			Handler:    _KeyVal_Txn_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Scan",
+			Handler:       _KeyVal_Scan_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Watch",
+			Handler:       _KeyVal_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "badies.proto",
+}
+
+const (
+	StorageNode_Put_FullMethodName          = "/badies.StorageNode/Put"
+	StorageNode_Get_FullMethodName          = "/badies.StorageNode/Get"
+	StorageNode_Delete_FullMethodName       = "/badies.StorageNode/Delete"
+	StorageNode_WriteBatch_FullMethodName   = "/badies.StorageNode/WriteBatch"
+	StorageNode_GetBatch_FullMethodName     = "/badies.StorageNode/GetBatch"
+	StorageNode_StreamRange_FullMethodName  = "/badies.StorageNode/StreamRange"
+	StorageNode_Scan_FullMethodName         = "/badies.StorageNode/Scan"
+	StorageNode_Purge_FullMethodName        = "/badies.StorageNode/Purge"
+	StorageNode_Revert_FullMethodName       = "/badies.StorageNode/Revert"
+	StorageNode_Sweep_FullMethodName        = "/badies.StorageNode/Sweep"
+	StorageNode_Versions_FullMethodName     = "/badies.StorageNode/Versions"
+	StorageNode_KeepVersions_FullMethodName = "/badies.StorageNode/KeepVersions"
+	StorageNode_TxnPrepare_FullMethodName   = "/badies.StorageNode/TxnPrepare"
+	StorageNode_TxnCommit_FullMethodName    = "/badies.StorageNode/TxnCommit"
+	StorageNode_TxnAbort_FullMethodName     = "/badies.StorageNode/TxnAbort"
+	StorageNode_StoreHint_FullMethodName    = "/badies.StorageNode/StoreHint"
+	StorageNode_StreamHints_FullMethodName  = "/badies.StorageNode/StreamHints"
+	StorageNode_DeleteHint_FullMethodName   = "/badies.StorageNode/DeleteHint"
+	StorageNode_MerkleTree_FullMethodName   = "/badies.StorageNode/MerkleTree"
+	StorageNode_RaftStart_FullMethodName    = "/badies.StorageNode/RaftStart"
+	StorageNode_RaftMessage_FullMethodName  = "/badies.StorageNode/RaftMessage"
+	StorageNode_RaftWrite_FullMethodName    = "/badies.StorageNode/RaftWrite"
+	StorageNode_RaftRead_FullMethodName     = "/badies.StorageNode/RaftRead"
+)
+
+// StorageNodeClient is the client API for StorageNode service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// StorageNode is served by every storage node process and operates on that
+// node's local LevelDB only. The KeyVal coordinator fans requests out to it.
+type StorageNodeClient interface {
+	Put(ctx context.Context, in *NodePutRequest, opts ...grpc.CallOption) (*NodePutResponse, error)
+	Get(ctx context.Context, in *NodeGetRequest, opts ...grpc.CallOption) (*NodeGetResponse, error)
+	Delete(ctx context.Context, in *NodeDeleteRequest, opts ...grpc.CallOption) (*NodeDeleteResponse, error)
+	WriteBatch(ctx context.Context, in *NodeWriteBatchRequest, opts ...grpc.CallOption) (*NodeWriteBatchResponse, error)
+	GetBatch(ctx context.Context, in *NodeGetBatchRequest, opts ...grpc.CallOption) (*NodeGetBatchResponse, error)
+	// StreamRange streams every record whose key hashes into one of the ranges
+	StreamRange(ctx context.Context, in *NodeRangeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NodeRecord], error)
+	// Scan streams the records of a key range in key order, tombstones included
+	Scan(ctx context.Context, in *NodeScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NodeRecord], error)
+	// Purge physically removes a key the node no longer owns
+	Purge(ctx context.Context, in *NodePurgeRequest, opts ...grpc.CallOption) (*NodePurgeResponse, error)
+	// Revert undoes a conditional write that lost to a concurrent write
+	Revert(ctx context.Context, in *NodeRevertRequest, opts ...grpc.CallOption) (*NodeRevertResponse, error)
+	// Sweep physically removes records that expired before a given time
+	Sweep(ctx context.Context, in *NodeSweepRequest, opts ...grpc.CallOption) (*NodeSweepResponse, error)
+	// Versions lists the current and replaced versions of a key
+	Versions(ctx context.Context, in *NodeVersionsRequest, opts ...grpc.CallOption) (*NodeVersionsResponse, error)
+	// KeepVersions adds replaced versions of a key moved from another node
+	KeepVersions(ctx context.Context, in *NodeKeepVersionsRequest, opts ...grpc.CallOption) (*NodeKeepVersionsResponse, error)
+	// Two-phase commit of multi-key transactions: a prepared transaction is
+	// staged durably and reserves its keys until it is committed or aborted
+	TxnPrepare(ctx context.Context, in *NodeTxnPrepareRequest, opts ...grpc.CallOption) (*NodeTxnPrepareResponse, error)
+	TxnCommit(ctx context.Context, in *NodeTxnDecision, opts ...grpc.CallOption) (*NodeTxnDecisionResponse, error)
+	TxnAbort(ctx context.Context, in *NodeTxnDecision, opts ...grpc.CallOption) (*NodeTxnDecisionResponse, error)
+	// Hinted handoff: keep writes for an unreachable replica until it is back
+	StoreHint(ctx context.Context, in *NodeHint, opts ...grpc.CallOption) (*NodeStoreHintResponse, error)
+	StreamHints(ctx context.Context, in *NodeStreamHintsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NodeHint], error)
+	DeleteHint(ctx context.Context, in *NodeDeleteHintRequest, opts ...grpc.CallOption) (*NodeDeleteHintResponse, error)
+	// MerkleTree summarizes a hash range for anti-entropy repair
+	MerkleTree(ctx context.Context, in *NodeMerkleRequest, opts ...grpc.CallOption) (*NodeMerkleResponse, error)
+	// Raft replication groups, used when the cluster runs in strong mode
+	RaftStart(ctx context.Context, in *NodeRaftGroup, opts ...grpc.CallOption) (*NodeRaftStartResponse, error)
+	RaftMessage(ctx context.Context, in *NodeRaftEnvelope, opts ...grpc.CallOption) (*NodeRaftMessageResponse, error)
+	RaftWrite(ctx context.Context, in *NodeRaftWriteRequest, opts ...grpc.CallOption) (*NodeRaftWriteResponse, error)
+	RaftRead(ctx context.Context, in *NodeRaftReadRequest, opts ...grpc.CallOption) (*NodeGetResponse, error)
+}
+
+type storageNodeClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewStorageNodeClient(cc grpc.ClientConnInterface) StorageNodeClient {
+	return &storageNodeClient{cc}
+}
+
+func (c *storageNodeClient) Put(ctx context.Context, in *NodePutRequest, opts ...grpc.CallOption) (*NodePutResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodePutResponse)
+	err := c.cc.Invoke(ctx, StorageNode_Put_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) Get(ctx context.Context, in *NodeGetRequest, opts ...grpc.CallOption) (*NodeGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeGetResponse)
+	err := c.cc.Invoke(ctx, StorageNode_Get_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) Delete(ctx context.Context, in *NodeDeleteRequest, opts ...grpc.CallOption) (*NodeDeleteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeDeleteResponse)
+	err := c.cc.Invoke(ctx, StorageNode_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) WriteBatch(ctx context.Context, in *NodeWriteBatchRequest, opts ...grpc.CallOption) (*NodeWriteBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeWriteBatchResponse)
+	err := c.cc.Invoke(ctx, StorageNode_WriteBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) GetBatch(ctx context.Context, in *NodeGetBatchRequest, opts ...grpc.CallOption) (*NodeGetBatchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeGetBatchResponse)
+	err := c.cc.Invoke(ctx, StorageNode_GetBatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) StreamRange(ctx context.Context, in *NodeRangeRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NodeRecord], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StorageNode_ServiceDesc.Streams[0], StorageNode_StreamRange_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[NodeRangeRequest, NodeRecord]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StorageNode_StreamRangeClient = grpc.ServerStreamingClient[NodeRecord]
+
+func (c *storageNodeClient) Scan(ctx context.Context, in *NodeScanRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NodeRecord], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StorageNode_ServiceDesc.Streams[1], StorageNode_Scan_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[NodeScanRequest, NodeRecord]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StorageNode_ScanClient = grpc.ServerStreamingClient[NodeRecord]
+
+func (c *storageNodeClient) Purge(ctx context.Context, in *NodePurgeRequest, opts ...grpc.CallOption) (*NodePurgeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodePurgeResponse)
+	err := c.cc.Invoke(ctx, StorageNode_Purge_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) Revert(ctx context.Context, in *NodeRevertRequest, opts ...grpc.CallOption) (*NodeRevertResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeRevertResponse)
+	err := c.cc.Invoke(ctx, StorageNode_Revert_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) Sweep(ctx context.Context, in *NodeSweepRequest, opts ...grpc.CallOption) (*NodeSweepResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeSweepResponse)
+	err := c.cc.Invoke(ctx, StorageNode_Sweep_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) Versions(ctx context.Context, in *NodeVersionsRequest, opts ...grpc.CallOption) (*NodeVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeVersionsResponse)
+	err := c.cc.Invoke(ctx, StorageNode_Versions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) KeepVersions(ctx context.Context, in *NodeKeepVersionsRequest, opts ...grpc.CallOption) (*NodeKeepVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeKeepVersionsResponse)
+	err := c.cc.Invoke(ctx, StorageNode_KeepVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) TxnPrepare(ctx context.Context, in *NodeTxnPrepareRequest, opts ...grpc.CallOption) (*NodeTxnPrepareResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeTxnPrepareResponse)
+	err := c.cc.Invoke(ctx, StorageNode_TxnPrepare_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) TxnCommit(ctx context.Context, in *NodeTxnDecision, opts ...grpc.CallOption) (*NodeTxnDecisionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeTxnDecisionResponse)
+	err := c.cc.Invoke(ctx, StorageNode_TxnCommit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) TxnAbort(ctx context.Context, in *NodeTxnDecision, opts ...grpc.CallOption) (*NodeTxnDecisionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeTxnDecisionResponse)
+	err := c.cc.Invoke(ctx, StorageNode_TxnAbort_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) StoreHint(ctx context.Context, in *NodeHint, opts ...grpc.CallOption) (*NodeStoreHintResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeStoreHintResponse)
+	err := c.cc.Invoke(ctx, StorageNode_StoreHint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) StreamHints(ctx context.Context, in *NodeStreamHintsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[NodeHint], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StorageNode_ServiceDesc.Streams[2], StorageNode_StreamHints_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[NodeStreamHintsRequest, NodeHint]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StorageNode_StreamHintsClient = grpc.ServerStreamingClient[NodeHint]
+
+func (c *storageNodeClient) DeleteHint(ctx context.Context, in *NodeDeleteHintRequest, opts ...grpc.CallOption) (*NodeDeleteHintResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeDeleteHintResponse)
+	err := c.cc.Invoke(ctx, StorageNode_DeleteHint_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) MerkleTree(ctx context.Context, in *NodeMerkleRequest, opts ...grpc.CallOption) (*NodeMerkleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeMerkleResponse)
+	err := c.cc.Invoke(ctx, StorageNode_MerkleTree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) RaftStart(ctx context.Context, in *NodeRaftGroup, opts ...grpc.CallOption) (*NodeRaftStartResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeRaftStartResponse)
+	err := c.cc.Invoke(ctx, StorageNode_RaftStart_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) RaftMessage(ctx context.Context, in *NodeRaftEnvelope, opts ...grpc.CallOption) (*NodeRaftMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeRaftMessageResponse)
+	err := c.cc.Invoke(ctx, StorageNode_RaftMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) RaftWrite(ctx context.Context, in *NodeRaftWriteRequest, opts ...grpc.CallOption) (*NodeRaftWriteResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeRaftWriteResponse)
+	err := c.cc.Invoke(ctx, StorageNode_RaftWrite_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storageNodeClient) RaftRead(ctx context.Context, in *NodeRaftReadRequest, opts ...grpc.CallOption) (*NodeGetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(NodeGetResponse)
+	err := c.cc.Invoke(ctx, StorageNode_RaftRead_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// StorageNodeServer is the server API for StorageNode service.
+// All implementations must embed UnimplementedStorageNodeServer
+// for forward compatibility.
+//
+// StorageNode is served by every storage node process and operates on that
+// node's local LevelDB only. The KeyVal coordinator fans requests out to it.
+type StorageNodeServer interface {
+	Put(context.Context, *NodePutRequest) (*NodePutResponse, error)
+	Get(context.Context, *NodeGetRequest) (*NodeGetResponse, error)
+	Delete(context.Context, *NodeDeleteRequest) (*NodeDeleteResponse, error)
+	WriteBatch(context.Context, *NodeWriteBatchRequest) (*NodeWriteBatchResponse, error)
+	GetBatch(context.Context, *NodeGetBatchRequest) (*NodeGetBatchResponse, error)
+	// StreamRange streams every record whose key hashes into one of the ranges
+	StreamRange(*NodeRangeRequest, grpc.ServerStreamingServer[NodeRecord]) error
+	// Scan streams the records of a key range in key order, tombstones included
+	Scan(*NodeScanRequest, grpc.ServerStreamingServer[NodeRecord]) error
+	// Purge physically removes a key the node no longer owns
+	Purge(context.Context, *NodePurgeRequest) (*NodePurgeResponse, error)
+	// Revert undoes a conditional write that lost to a concurrent write
+	Revert(context.Context, *NodeRevertRequest) (*NodeRevertResponse, error)
+	// Sweep physically removes records that expired before a given time
+	Sweep(context.Context, *NodeSweepRequest) (*NodeSweepResponse, error)
+	// Versions lists the current and replaced versions of a key
+	Versions(context.Context, *NodeVersionsRequest) (*NodeVersionsResponse, error)
+	// KeepVersions adds replaced versions of a key moved from another node
+	KeepVersions(context.Context, *NodeKeepVersionsRequest) (*NodeKeepVersionsResponse, error)
+	// Two-phase commit of multi-key transactions: a prepared transaction is
+	// staged durably and reserves its keys until it is committed or aborted
+	TxnPrepare(context.Context, *NodeTxnPrepareRequest) (*NodeTxnPrepareResponse, error)
+	TxnCommit(context.Context, *NodeTxnDecision) (*NodeTxnDecisionResponse, error)
+	TxnAbort(context.Context, *NodeTxnDecision) (*NodeTxnDecisionResponse, error)
+	// Hinted handoff: keep writes for an unreachable replica until it is back
+	StoreHint(context.Context, *NodeHint) (*NodeStoreHintResponse, error)
+	StreamHints(*NodeStreamHintsRequest, grpc.ServerStreamingServer[NodeHint]) error
+	DeleteHint(context.Context, *NodeDeleteHintRequest) (*NodeDeleteHintResponse, error)
+	// MerkleTree summarizes a hash range for anti-entropy repair
+	MerkleTree(context.Context, *NodeMerkleRequest) (*NodeMerkleResponse, error)
+	// Raft replication groups, used when the cluster runs in strong mode
+	RaftStart(context.Context, *NodeRaftGroup) (*NodeRaftStartResponse, error)
+	RaftMessage(context.Context, *NodeRaftEnvelope) (*NodeRaftMessageResponse, error)
+	RaftWrite(context.Context, *NodeRaftWriteRequest) (*NodeRaftWriteResponse, error)
+	RaftRead(context.Context, *NodeRaftReadRequest) (*NodeGetResponse, error)
+	mustEmbedUnimplementedStorageNodeServer()
+}
+
+// UnimplementedStorageNodeServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedStorageNodeServer struct{}
+
+func (UnimplementedStorageNodeServer) Put(context.Context, *NodePutRequest) (*NodePutResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Put not implemented")
+}
+func (UnimplementedStorageNodeServer) Get(context.Context, *NodeGetRequest) (*NodeGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Get not implemented")
+}
+func (UnimplementedStorageNodeServer) Delete(context.Context, *NodeDeleteRequest) (*NodeDeleteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedStorageNodeServer) WriteBatch(context.Context, *NodeWriteBatchRequest) (*NodeWriteBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteBatch not implemented")
+}
+func (UnimplementedStorageNodeServer) GetBatch(context.Context, *NodeGetBatchRequest) (*NodeGetBatchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBatch not implemented")
+}
+func (UnimplementedStorageNodeServer) StreamRange(*NodeRangeRequest, grpc.ServerStreamingServer[NodeRecord]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamRange not implemented")
+}
+func (UnimplementedStorageNodeServer) Scan(*NodeScanRequest, grpc.ServerStreamingServer[NodeRecord]) error {
+	return status.Errorf(codes.Unimplemented, "method Scan not implemented")
+}
+func (UnimplementedStorageNodeServer) Purge(context.Context, *NodePurgeRequest) (*NodePurgeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Purge not implemented")
+}
+func (UnimplementedStorageNodeServer) Revert(context.Context, *NodeRevertRequest) (*NodeRevertResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revert not implemented")
+}
+func (UnimplementedStorageNodeServer) Sweep(context.Context, *NodeSweepRequest) (*NodeSweepResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Sweep not implemented")
+}
+func (UnimplementedStorageNodeServer) Versions(context.Context, *NodeVersionsRequest) (*NodeVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Versions not implemented")
+}
+func (UnimplementedStorageNodeServer) KeepVersions(context.Context, *NodeKeepVersionsRequest) (*NodeKeepVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method KeepVersions not implemented")
+}
+func (UnimplementedStorageNodeServer) TxnPrepare(context.Context, *NodeTxnPrepareRequest) (*NodeTxnPrepareResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TxnPrepare not implemented")
+}
+func (UnimplementedStorageNodeServer) TxnCommit(context.Context, *NodeTxnDecision) (*NodeTxnDecisionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TxnCommit not implemented")
+}
+func (UnimplementedStorageNodeServer) TxnAbort(context.Context, *NodeTxnDecision) (*NodeTxnDecisionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TxnAbort not implemented")
+}
+func (UnimplementedStorageNodeServer) StoreHint(context.Context, *NodeHint) (*NodeStoreHintResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StoreHint not implemented")
+}
+func (UnimplementedStorageNodeServer) StreamHints(*NodeStreamHintsRequest, grpc.ServerStreamingServer[NodeHint]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamHints not implemented")
+}
+func (UnimplementedStorageNodeServer) DeleteHint(context.Context, *NodeDeleteHintRequest) (*NodeDeleteHintResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteHint not implemented")
+}
+func (UnimplementedStorageNodeServer) MerkleTree(context.Context, *NodeMerkleRequest) (*NodeMerkleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MerkleTree not implemented")
+}
+func (UnimplementedStorageNodeServer) RaftStart(context.Context, *NodeRaftGroup) (*NodeRaftStartResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RaftStart not implemented")
+}
+func (UnimplementedStorageNodeServer) RaftMessage(context.Context, *NodeRaftEnvelope) (*NodeRaftMessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RaftMessage not implemented")
+}
+func (UnimplementedStorageNodeServer) RaftWrite(context.Context, *NodeRaftWriteRequest) (*NodeRaftWriteResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RaftWrite not implemented")
+}
+func (UnimplementedStorageNodeServer) RaftRead(context.Context, *NodeRaftReadRequest) (*NodeGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RaftRead not implemented")
+}
+func (UnimplementedStorageNodeServer) mustEmbedUnimplementedStorageNodeServer() {}
+func (UnimplementedStorageNodeServer) testEmbeddedByValue()                     {}
+
+// UnsafeStorageNodeServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to StorageNodeServer will
+// result in compilation errors.
+type UnsafeStorageNodeServer interface {
+	mustEmbedUnimplementedStorageNodeServer()
+}
+
+func RegisterStorageNodeServer(s grpc.ServiceRegistrar, srv StorageNodeServer) {
+	// If the following call pancis, it indicates UnimplementedStorageNodeServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&StorageNode_ServiceDesc, srv)
+}
+
+func _StorageNode_Put_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodePutRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).Put(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_Put_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).Put(ctx, req.(*NodePutRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_Get_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).Get(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_Get_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).Get(ctx, req.(*NodeGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeDeleteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).Delete(ctx, req.(*NodeDeleteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_WriteBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeWriteBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).WriteBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_WriteBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).WriteBatch(ctx, req.(*NodeWriteBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_GetBatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeGetBatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).GetBatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_GetBatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).GetBatch(ctx, req.(*NodeGetBatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_StreamRange_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NodeRangeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageNodeServer).StreamRange(m, &grpc.GenericServerStream[NodeRangeRequest, NodeRecord]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StorageNode_StreamRangeServer = grpc.ServerStreamingServer[NodeRecord]
+
+func _StorageNode_Scan_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NodeScanRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageNodeServer).Scan(m, &grpc.GenericServerStream[NodeScanRequest, NodeRecord]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StorageNode_ScanServer = grpc.ServerStreamingServer[NodeRecord]
+
+func _StorageNode_Purge_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodePurgeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).Purge(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_Purge_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).Purge(ctx, req.(*NodePurgeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_Revert_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeRevertRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).Revert(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_Revert_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).Revert(ctx, req.(*NodeRevertRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_Sweep_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeSweepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).Sweep(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_Sweep_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).Sweep(ctx, req.(*NodeSweepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_Versions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).Versions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_Versions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).Versions(ctx, req.(*NodeVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_KeepVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeKeepVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).KeepVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_KeepVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).KeepVersions(ctx, req.(*NodeKeepVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_TxnPrepare_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeTxnPrepareRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).TxnPrepare(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_TxnPrepare_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).TxnPrepare(ctx, req.(*NodeTxnPrepareRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_TxnCommit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeTxnDecision)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).TxnCommit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_TxnCommit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).TxnCommit(ctx, req.(*NodeTxnDecision))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_TxnAbort_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeTxnDecision)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).TxnAbort(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_TxnAbort_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).TxnAbort(ctx, req.(*NodeTxnDecision))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_StoreHint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeHint)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).StoreHint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_StoreHint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).StoreHint(ctx, req.(*NodeHint))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_StreamHints_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NodeStreamHintsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StorageNodeServer).StreamHints(m, &grpc.GenericServerStream[NodeStreamHintsRequest, NodeHint]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StorageNode_StreamHintsServer = grpc.ServerStreamingServer[NodeHint]
+
+func _StorageNode_DeleteHint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeDeleteHintRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).DeleteHint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_DeleteHint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).DeleteHint(ctx, req.(*NodeDeleteHintRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_MerkleTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeMerkleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).MerkleTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_MerkleTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).MerkleTree(ctx, req.(*NodeMerkleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_RaftStart_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeRaftGroup)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).RaftStart(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_RaftStart_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).RaftStart(ctx, req.(*NodeRaftGroup))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_RaftMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeRaftEnvelope)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).RaftMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_RaftMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).RaftMessage(ctx, req.(*NodeRaftEnvelope))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_RaftWrite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeRaftWriteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).RaftWrite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_RaftWrite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).RaftWrite(ctx, req.(*NodeRaftWriteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StorageNode_RaftRead_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeRaftReadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StorageNodeServer).RaftRead(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StorageNode_RaftRead_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StorageNodeServer).RaftRead(ctx, req.(*NodeRaftReadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// StorageNode_ServiceDesc is the grpc.ServiceDesc for StorageNode service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var StorageNode_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "badies.StorageNode",
+	HandlerType: (*StorageNodeServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Put",
+			Handler:    _StorageNode_Put_Handler,
+		},
+		{
+			MethodName: "Get",
+			Handler:    _StorageNode_Get_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _StorageNode_Delete_Handler,
+		},
+		{
+			MethodName: "WriteBatch",
+			Handler:    _StorageNode_WriteBatch_Handler,
+		},
+		{
+			MethodName: "GetBatch",
+			Handler:    _StorageNode_GetBatch_Handler,
+		},
+		{
+			MethodName: "Purge",
+			Handler:    _StorageNode_Purge_Handler,
+		},
+		{
+			MethodName: "Revert",
+			Handler:    _StorageNode_Revert_Handler,
+		},
+		{
+			MethodName: "Sweep",
+			Handler:    _StorageNode_Sweep_Handler,
+		},
+		{
+			MethodName: "Versions",
+			Handler:    _StorageNode_Versions_Handler,
+		},
+		{
+			MethodName: "KeepVersions",
+			Handler:    _StorageNode_KeepVersions_Handler,
+		},
+		{
+			MethodName: "TxnPrepare",
+			Handler:    _StorageNode_TxnPrepare_Handler,
+		},
+		{
+			MethodName: "TxnCommit",
+			Handler:    _StorageNode_TxnCommit_Handler,
+		},
+		{
+			MethodName: "TxnAbort",
+			Handler:    _StorageNode_TxnAbort_Handler,
+		},
+		{
+			MethodName: "StoreHint",
+			Handler:    _StorageNode_StoreHint_Handler,
+		},
+		{
+			MethodName: "DeleteHint",
+			Handler:    _StorageNode_DeleteHint_Handler,
+		},
+		{
+			MethodName: "MerkleTree",
+			Handler:    _StorageNode_MerkleTree_Handler,
+		},
+		{
+			MethodName: "RaftStart",
+			Handler:    _StorageNode_RaftStart_Handler,
+		},
+		{
+			MethodName: "RaftMessage",
+			Handler:    _StorageNode_RaftMessage_Handler,
+		},
+		{
+			MethodName: "RaftWrite",
+			Handler:    _StorageNode_RaftWrite_Handler,
+		},
+		{
+			MethodName: "RaftRead",
+			Handler:    _StorageNode_RaftRead_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamRange",
+			Handler:       _StorageNode_StreamRange_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "Scan",
+			Handler:       _StorageNode_Scan_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "StreamHints",
+			Handler:       _StorageNode_StreamHints_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "badies.proto",
+}
+
+const (
+	Admin_Repair_FullMethodName      = "/badies.Admin/Repair"
+	Admin_AddNode_FullMethodName     = "/badies.Admin/AddNode"
+	Admin_RemoveNode_FullMethodName  = "/badies.Admin/RemoveNode"
+	Admin_ReplaceNode_FullMethodName = "/badies.Admin/ReplaceNode"
+	Admin_ListNodes_FullMethodName   = "/badies.Admin/ListNodes"
+)
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Admin is served by the coordinator for operators
+type AdminClient interface {
+	// Repair runs anti-entropy for one node, one hash range or, if neither is
+	// given, the whole ring
+	Repair(ctx context.Context, in *RepairRequest, opts ...grpc.CallOption) (*RepairResponse, error)
+	// AddNode connects a storage node and puts it on the ring; the keys it now
+	// owns are moved to it in the background
+	AddNode(ctx context.Context, in *AddNodeRequest, opts ...grpc.CallOption) (*AddNodeResponse, error)
+	// RemoveNode takes a node off the ring. With drain, the call waits while
+	// the node's keys are copied to their new owners and only then
+	// disconnects it; otherwise the keys are re-replicated from the remaining
+	// replicas in the background.
+	RemoveNode(ctx context.Context, in *RemoveNodeRequest, opts ...grpc.CallOption) (*RemoveNodeResponse, error)
+	// ReplaceNode points a node ID at a new address and repairs the ranges
+	// the node owns onto it
+	ReplaceNode(ctx context.Context, in *ReplaceNodeRequest, opts ...grpc.CallOption) (*ReplaceNodeResponse, error)
+	ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) Repair(ctx context.Context, in *RepairRequest, opts ...grpc.CallOption) (*RepairResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RepairResponse)
+	err := c.cc.Invoke(ctx, Admin_Repair_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) AddNode(ctx context.Context, in *AddNodeRequest, opts ...grpc.CallOption) (*AddNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddNodeResponse)
+	err := c.cc.Invoke(ctx, Admin_AddNode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) RemoveNode(ctx context.Context, in *RemoveNodeRequest, opts ...grpc.CallOption) (*RemoveNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemoveNodeResponse)
+	err := c.cc.Invoke(ctx, Admin_RemoveNode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ReplaceNode(ctx context.Context, in *ReplaceNodeRequest, opts ...grpc.CallOption) (*ReplaceNodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplaceNodeResponse)
+	err := c.cc.Invoke(ctx, Admin_ReplaceNode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListNodes(ctx context.Context, in *ListNodesRequest, opts ...grpc.CallOption) (*ListNodesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListNodesResponse)
+	err := c.cc.Invoke(ctx, Admin_ListNodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility.
+//
+// Admin is served by the coordinator for operators
+type AdminServer interface {
+	// Repair runs anti-entropy for one node, one hash range or, if neither is
+	// given, the whole ring
+	Repair(context.Context, *RepairRequest) (*RepairResponse, error)
+	// AddNode connects a storage node and puts it on the ring; the keys it now
+	// owns are moved to it in the background
+	AddNode(context.Context, *AddNodeRequest) (*AddNodeResponse, error)
+	// RemoveNode takes a node off the ring. With drain, the call waits while
+	// the node's keys are copied to their new owners and only then
+	// disconnects it; otherwise the keys are re-replicated from the remaining
+	// replicas in the background.
+	RemoveNode(context.Context, *RemoveNodeRequest) (*RemoveNodeResponse, error)
+	// ReplaceNode points a node ID at a new address and repairs the ranges
+	// the node owns onto it
+	ReplaceNode(context.Context, *ReplaceNodeRequest) (*ReplaceNodeResponse, error)
+	ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServer struct{}
+
+func (UnimplementedAdminServer) Repair(context.Context, *RepairRequest) (*RepairResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Repair not implemented")
+}
+func (UnimplementedAdminServer) AddNode(context.Context, *AddNodeRequest) (*AddNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddNode not implemented")
+}
+func (UnimplementedAdminServer) RemoveNode(context.Context, *RemoveNodeRequest) (*RemoveNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveNode not implemented")
+}
+func (UnimplementedAdminServer) ReplaceNode(context.Context, *ReplaceNodeRequest) (*ReplaceNodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReplaceNode not implemented")
+}
+func (UnimplementedAdminServer) ListNodes(context.Context, *ListNodesRequest) (*ListNodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListNodes not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+func (UnimplementedAdminServer) testEmbeddedByValue()               {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_Repair_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RepairRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Repair(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_Repair_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Repair(ctx, req.(*RepairRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_AddNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).AddNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_AddNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).AddNode(ctx, req.(*AddNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_RemoveNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).RemoveNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_RemoveNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).RemoveNode(ctx, req.(*RemoveNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ReplaceNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplaceNodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ReplaceNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ReplaceNode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ReplaceNode(ctx, req.(*ReplaceNodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListNodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListNodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListNodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ListNodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListNodes(ctx, req.(*ListNodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "badies.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Repair",
+			Handler:    _Admin_Repair_Handler,
+		},
+		{
+			MethodName: "AddNode",
+			Handler:    _Admin_AddNode_Handler,
+		},
+		{
+			MethodName: "RemoveNode",
+			Handler:    _Admin_RemoveNode_Handler,
+		},
+		{
+			MethodName: "ReplaceNode",
+			Handler:    _Admin_ReplaceNode_Handler,
+		},
+		{
+			MethodName: "ListNodes",
+			Handler:    _Admin_ListNodes_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "badies.proto",
+}
+
+const (
+	Gossip_Ping_FullMethodName    = "/badies.Gossip/Ping"
+	Gossip_PingReq_FullMethodName = "/badies.Gossip/PingReq"
+)
+
+// GossipClient is the client API for Gossip service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Gossip is served by the coordinator and every storage node. Members probe
+// each other SWIM-style and piggyback membership updates on the messages.
+type GossipClient interface {
+	// Ping is a direct probe; the ack proves the member is alive
+	Ping(ctx context.Context, in *GossipPing, opts ...grpc.CallOption) (*GossipAck, error)
+	// PingReq asks a member to probe another one that did not answer a Ping
+	PingReq(ctx context.Context, in *GossipPingReq, opts ...grpc.CallOption) (*GossipAck, error)
+}
+
+type gossipClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewGossipClient(cc grpc.ClientConnInterface) GossipClient {
+	return &gossipClient{cc}
+}
+
+func (c *gossipClient) Ping(ctx context.Context, in *GossipPing, opts ...grpc.CallOption) (*GossipAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GossipAck)
+	err := c.cc.Invoke(ctx, Gossip_Ping_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *gossipClient) PingReq(ctx context.Context, in *GossipPingReq, opts ...grpc.CallOption) (*GossipAck, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GossipAck)
+	err := c.cc.Invoke(ctx, Gossip_PingReq_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// GossipServer is the server API for Gossip service.
+// All implementations must embed UnimplementedGossipServer
+// for forward compatibility.
+//
+// Gossip is served by the coordinator and every storage node. Members probe
+// each other SWIM-style and piggyback membership updates on the messages.
+type GossipServer interface {
+	// Ping is a direct probe; the ack proves the member is alive
+	Ping(context.Context, *GossipPing) (*GossipAck, error)
+	// PingReq asks a member to probe another one that did not answer a Ping
+	PingReq(context.Context, *GossipPingReq) (*GossipAck, error)
+	mustEmbedUnimplementedGossipServer()
+}
+
+// UnimplementedGossipServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedGossipServer struct{}
+
+func (UnimplementedGossipServer) Ping(context.Context, *GossipPing) (*GossipAck, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ping not implemented")
+}
+func (UnimplementedGossipServer) PingReq(context.Context, *GossipPingReq) (*GossipAck, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PingReq not implemented")
+}
+func (UnimplementedGossipServer) mustEmbedUnimplementedGossipServer() {}
+func (UnimplementedGossipServer) testEmbeddedByValue()                {}
+
+// UnsafeGossipServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to GossipServer will
+// result in compilation errors.
+type UnsafeGossipServer interface {
+	mustEmbedUnimplementedGossipServer()
+}
+
+func RegisterGossipServer(s grpc.ServiceRegistrar, srv GossipServer) {
+	// If the following call pancis, it indicates UnimplementedGossipServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Gossip_ServiceDesc, srv)
+}
+
+func _Gossip_Ping_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GossipPing)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GossipServer).Ping(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gossip_Ping_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GossipServer).Ping(ctx, req.(*GossipPing))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Gossip_PingReq_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GossipPingReq)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(GossipServer).PingReq(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Gossip_PingReq_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(GossipServer).PingReq(ctx, req.(*GossipPingReq))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Gossip_ServiceDesc is the grpc.ServiceDesc for Gossip service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Gossip_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "badies.Gossip",
+	HandlerType: (*GossipServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Ping",
+			Handler:    _Gossip_Ping_Handler,
+		},
+		{
+			MethodName: "PingReq",
+			Handler:    _Gossip_PingReq_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "badies.proto",
@@ -0,0 +1,234 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"time"
+
+	pb "badies/proto/badiespb"
+)
+
+const (
+	// DefaultMerkleDepth gives every ring segment 256 leaf buckets
+	DefaultMerkleDepth = 8
+	// MaxMerkleDepth is the deepest tree nodes build, 65536 leaf buckets
+	MaxMerkleDepth = 16
+	// DefaultAntiEntropyInterval is the time between full background repairs
+	DefaultAntiEntropyInterval = 10 * time.Minute
+)
+
+// RepairStats summarizes an anti-entropy run
+type RepairStats struct {
+	RangesCompared int
+	RangesRepaired int
+	KeysRepaired   int64
+}
+
+func (rs *RepairStats) add(other RepairStats) {
+	rs.RangesCompared += other.RangesCompared
+	rs.RangesRepaired += other.RangesRepaired
+	rs.KeysRepaired += other.KeysRepaired
+}
+
+// AntiEntropy keeps replicas convergent for keys that are never read. For
+// every ring segment it compares Merkle trees built by each replica and only
+// syncs the leaf buckets whose hashes differ.
+type AntiEntropy struct {
+	nm   *NodeManager
+	ring *HashRing
+
+	// Depth of the Merkle tree built per segment
+	Depth int
+	// Interval between background repairs of the whole ring
+	Interval time.Duration
+}
+
+// NewAntiEntropy creates an anti-entropy process for the nodes of ring
+func NewAntiEntropy(nm *NodeManager, ring *HashRing) *AntiEntropy {
+	return &AntiEntropy{
+		nm:       nm,
+		ring:     ring,
+		Depth:    DefaultMerkleDepth,
+		Interval: DefaultAntiEntropyInterval,
+	}
+}
+
+// Run repairs the whole ring every Interval until ctx is cancelled
+func (ae *AntiEntropy) Run(ctx context.Context) {
+	ticker := time.NewTicker(ae.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			stats, err := ae.RepairAll(ctx)
+			if err != nil {
+				log.Printf("Anti-entropy run failed: %v", err)
+			}
+			log.Printf("Anti-entropy compared %d ranges, repaired %d ranges and %d keys",
+				stats.RangesCompared, stats.RangesRepaired, stats.KeysRepaired)
+		}
+	}
+}
+
+// RepairAll compares every segment of the ring
+func (ae *AntiEntropy) RepairAll(ctx context.Context) (RepairStats, error) {
+	return ae.repairSegments(ctx, ae.ring.Segments())
+}
+
+// RepairNode compares every segment nodeID replicates
+func (ae *AntiEntropy) RepairNode(ctx context.Context, nodeID string) (RepairStats, error) {
+	var segments []Segment
+	for _, seg := range ae.ring.Segments() {
+		if slices.Contains(seg.Nodes, nodeID) {
+			segments = append(segments, seg)
+		}
+	}
+	if len(segments) == 0 {
+		return RepairStats{}, fmt.Errorf("node %s replicates no range of the ring", nodeID)
+	}
+	return ae.repairSegments(ctx, segments)
+}
+
+// RepairRange compares the given hash range, split at ring segment borders
+func (ae *AntiEntropy) RepairRange(ctx context.Context, rng HashRange) (RepairStats, error) {
+	return ae.repairSegments(ctx, ae.ring.SegmentsIn(rng))
+}
+
+func (ae *AntiEntropy) repairSegments(ctx context.Context, segments []Segment) (RepairStats, error) {
+	var total RepairStats
+	for _, seg := range segments {
+		stats, err := ae.repairSegment(ctx, seg)
+		total.add(stats)
+		if err != nil {
+			return total, fmt.Errorf("range (%d, %d]: %v", seg.Range.Start, seg.Range.End, err)
+		}
+	}
+	return total, nil
+}
+
+// repairSegment compares the Merkle trees of all reachable replicas of a
+// segment and syncs the leaf buckets that differ
+func (ae *AntiEntropy) repairSegment(ctx context.Context, seg Segment) (RepairStats, error) {
+	stats := RepairStats{RangesCompared: 1}
+
+	var replicas []string
+	var trees [][][]byte
+	for _, nodeID := range seg.Nodes {
+		client, err := ae.nm.GetClient(nodeID)
+		if err != nil {
+			continue
+		}
+		resp, err := client.MerkleTree(ctx, &pb.NodeMerkleRequest{
+			Range: &pb.NodeHashRange{Start: seg.Range.Start, End: seg.Range.End},
+			Depth: uint32(ae.Depth),
+			Hash:  ae.ring.HashName(),
+		})
+		if err != nil {
+			log.Printf("Anti-entropy skipping node %s: %v", nodeID, err)
+			continue
+		}
+		replicas = append(replicas, nodeID)
+		trees = append(trees, resp.GetHashes())
+	}
+	if len(replicas) < 2 {
+		return stats, nil // nothing to compare against
+	}
+
+	leaves := diffLeaves(trees, 0, 1<<ae.Depth)
+	if len(leaves) == 0 {
+		return stats, nil
+	}
+	stats.RangesRepaired = 1
+
+	for _, leaf := range leaves {
+		sub, ok := seg.Range.SubRange(leaf, 1<<ae.Depth)
+		if !ok {
+			continue
+		}
+		repaired, err := ae.syncRange(ctx, sub, replicas)
+		stats.KeysRepaired += repaired
+		if err != nil {
+			return stats, err
+		}
+	}
+	return stats, nil
+}
+
+// diffLeaves descends from node i into every subtree whose hash is not the
+// same on all replicas and returns the indexes of the differing leaves
+func diffLeaves(trees [][][]byte, i int, leaves int) []int {
+	same := true
+	for _, tree := range trees[1:] {
+		if !bytes.Equal(tree[i], trees[0][i]) {
+			same = false
+			break
+		}
+	}
+	if same {
+		return nil
+	}
+	if i >= leaves-1 {
+		return []int{i - (leaves - 1)}
+	}
+	return append(diffLeaves(trees, 2*i+1, leaves), diffLeaves(trees, 2*i+2, leaves)...)
+}
+
+// syncRange streams a small range from every replica and pushes the newest
+// record of each key to the replicas that are missing it or hold an older
+// version. It returns the number of records written.
+func (ae *AntiEntropy) syncRange(ctx context.Context, rng HashRange, replicas []string) (int64, error) {
+	newest := make(map[string]*pb.NodeRecord)
+	held := make(map[string]map[string]uint64) // node -> key -> version
+	for _, nodeID := range replicas {
+		client, err := ae.nm.GetClient(nodeID)
+		if err != nil {
+			return 0, err
+		}
+		stream, err := client.StreamRange(ctx, &pb.NodeRangeRequest{
+			Ranges: []*pb.NodeHashRange{{Start: rng.Start, End: rng.End}},
+			Hash:   ae.ring.HashName(),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("node %s: %v", nodeID, err)
+		}
+		held[nodeID] = make(map[string]uint64)
+		for {
+			rec, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return 0, fmt.Errorf("node %s: %v", nodeID, err)
+			}
+			key := string(rec.GetKey())
+			held[nodeID][key] = rec.GetVersion()
+			if cur, ok := newest[key]; !ok || rec.GetVersion() > cur.GetVersion() {
+				newest[key] = rec
+			}
+		}
+	}
+
+	var repaired int64
+	for _, nodeID := range replicas {
+		client, err := ae.nm.GetClient(nodeID)
+		if err != nil {
+			return repaired, err
+		}
+		for key, rec := range newest {
+			if version, ok := held[nodeID][key]; ok && version >= rec.GetVersion() {
+				continue
+			}
+			if err := copyRecord(ctx, client, rec); err != nil {
+				return repaired, fmt.Errorf("node %s: %v", nodeID, err)
+			}
+			repaired++
+		}
+	}
+	return repaired, nil
+}
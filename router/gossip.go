@@ -0,0 +1,456 @@
+package router
+
+import (
+	"context"
+	"log"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	pb "badies/proto/badiespb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Defaults of the gossip failure detector
+const (
+	DefaultProbeInterval    = time.Second
+	DefaultProbeTimeout     = 500 * time.Millisecond
+	DefaultIndirectProbes   = 3
+	DefaultSuspicionTimeout = 5 * time.Second
+)
+
+const (
+	// maxPiggyback bounds the updates carried by a single message
+	maxPiggyback = 16
+	// retransmitMult scales how often an update is passed on, as
+	// retransmitMult * log2(members) messages
+	retransmitMult = 3
+)
+
+// MemberState is what the cluster believes about a member
+type MemberState int
+
+const (
+	StateAlive MemberState = iota
+	StateSuspect
+	StateDead
+)
+
+func (s MemberState) String() string {
+	switch s {
+	case StateAlive:
+		return "alive"
+	case StateSuspect:
+		return "suspect"
+	default:
+		return "dead"
+	}
+}
+
+// Member is a process taking part in gossip, a storage node or a
+// coordinator. Its incarnation starts at the time the process started and is
+// raised by the member itself to refute a rumour that it is not alive.
+type Member struct {
+	ID          string
+	Addr        string
+	State       MemberState
+	Incarnation uint64
+}
+
+// Transport carries gossip messages to the member at addr
+type Transport interface {
+	Ping(ctx context.Context, addr string, ping *pb.GossipPing) (*pb.GossipAck, error)
+	PingReq(ctx context.Context, addr string, req *pb.GossipPingReq) (*pb.GossipAck, error)
+}
+
+// Gossip is a SWIM failure detector. Every ProbeInterval it pings one
+// member; if the member does not answer within ProbeTimeout, IndirectProbes
+// other members are asked to ping it. A member nobody reached is suspected,
+// and declared dead unless it refutes the suspicion within
+// SuspicionTimeout. Membership updates travel piggybacked on the pings and
+// acks. Gossip also serves the Gossip gRPC service for its peers.
+type Gossip struct {
+	pb.UnimplementedGossipServer
+	transport Transport
+
+	ProbeInterval    time.Duration
+	ProbeTimeout     time.Duration
+	IndirectProbes   int
+	SuspicionTimeout time.Duration
+	// OnChange is called whenever a member is discovered or changes state.
+	// It runs outside the gossip lock.
+	OnChange func(Member)
+
+	mu         sync.Mutex
+	self       Member
+	members    map[string]*member
+	queue      []*broadcast // updates still to be passed on
+	probeOrder []string
+	probeNext  int
+}
+
+type member struct {
+	Member
+	suspectedAt time.Time
+}
+
+// broadcast is an update and the number of messages it went out with
+type broadcast struct {
+	update Member
+	sent   int
+}
+
+// NewGossip creates the gossip state of the member id reachable at addr
+func NewGossip(id, addr string, transport Transport) *Gossip {
+	return &Gossip{
+		transport:        transport,
+		ProbeInterval:    DefaultProbeInterval,
+		ProbeTimeout:     DefaultProbeTimeout,
+		IndirectProbes:   DefaultIndirectProbes,
+		SuspicionTimeout: DefaultSuspicionTimeout,
+		self:             Member{ID: id, Addr: addr, State: StateAlive, Incarnation: uint64(time.Now().UnixNano())},
+		members:          make(map[string]*member),
+	}
+}
+
+// Self returns this member
+func (g *Gossip) Self() Member {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.self
+}
+
+// Members returns the other known members, ordered by ID
+func (g *Gossip) Members() []Member {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	members := make([]Member, 0, len(g.members))
+	for _, m := range g.members {
+		members = append(members, m.Member)
+	}
+	slices.SortFunc(members, func(a, b Member) int { return strings.Compare(a.ID, b.ID) })
+	return members
+}
+
+// Track adds a member known from configuration, so that it is probed even
+// before it announced itself, or updates the address of a known one
+func (g *Gossip) Track(id, addr string) {
+	var notes []Member
+	g.mu.Lock()
+	if m, ok := g.members[id]; ok {
+		m.Addr = addr
+	} else if id != g.self.ID {
+		g.applyLocked(Member{ID: id, Addr: addr, State: StateAlive}, &notes)
+	}
+	g.mu.Unlock()
+	g.notify(notes)
+}
+
+// Join pings the members at addrs to announce this member and learn the
+// membership from them. It returns how many answered.
+func (g *Gossip) Join(ctx context.Context, addrs ...string) int {
+	joined := 0
+	for _, addr := range addrs {
+		if _, err := g.ping(ctx, addr); err != nil {
+			log.Printf("Gossip: could not join through %s: %v", addr, err)
+			continue
+		}
+		joined++
+	}
+	return joined
+}
+
+// Run probes a member every ProbeInterval until ctx is cancelled
+func (g *Gossip) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.ProbeInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			g.probe(ctx)
+			g.expireSuspects()
+		}
+	}
+}
+
+// probe runs one protocol period against the next member. Dead members are
+// probed too, without indirect probes, so that one that comes back is found.
+func (g *Gossip) probe(ctx context.Context) {
+	target, ok := g.nextTarget()
+	if !ok {
+		return
+	}
+	if _, err := g.ping(ctx, target.Addr); err == nil || target.State == StateDead {
+		return
+	}
+	if g.pingIndirect(ctx, target) {
+		return
+	}
+	var notes []Member
+	g.mu.Lock()
+	if cur, ok := g.members[target.ID]; ok && cur.State == StateAlive {
+		g.applyLocked(Member{ID: cur.ID, Addr: cur.Addr, State: StateSuspect, Incarnation: cur.Incarnation}, &notes)
+	}
+	g.mu.Unlock()
+	g.notify(notes)
+}
+
+// nextTarget walks the members in a random order that is reshuffled after
+// every round
+func (g *Gossip) nextTarget() (Member, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for attempts := 0; attempts <= len(g.members); attempts++ {
+		if g.probeNext >= len(g.probeOrder) {
+			g.probeOrder = g.probeOrder[:0]
+			for id := range g.members {
+				g.probeOrder = append(g.probeOrder, id)
+			}
+			rand.Shuffle(len(g.probeOrder), func(i, j int) {
+				g.probeOrder[i], g.probeOrder[j] = g.probeOrder[j], g.probeOrder[i]
+			})
+			g.probeNext = 0
+			if len(g.probeOrder) == 0 {
+				return Member{}, false
+			}
+		}
+		id := g.probeOrder[g.probeNext]
+		g.probeNext++
+		if m, ok := g.members[id]; ok {
+			return m.Member, true
+		}
+	}
+	return Member{}, false
+}
+
+// ping probes the member at addr directly and merges its answer
+func (g *Gossip) ping(ctx context.Context, addr string) (*pb.GossipAck, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.ProbeTimeout)
+	defer cancel()
+	g.mu.Lock()
+	msg := &pb.GossipPing{From: memberToPB(g.self), Updates: g.piggybackLocked()}
+	for _, m := range g.members {
+		if m.Addr == addr && m.State != StateAlive {
+			// Let a member that came back refute its death right away
+			msg.Updates = append(msg.Updates, memberToPB(m.Member))
+		}
+	}
+	g.mu.Unlock()
+
+	ack, err := g.transport.Ping(ctx, addr, msg)
+	if err != nil {
+		return nil, err
+	}
+	g.merge(ack.GetFrom(), ack.GetUpdates())
+	return ack, nil
+}
+
+// pingIndirect asks up to IndirectProbes other live members to ping target
+// and reports whether any of them reached it
+func (g *Gossip) pingIndirect(ctx context.Context, target Member) bool {
+	g.mu.Lock()
+	var helpers []Member
+	for _, m := range g.members {
+		if m.ID != target.ID && m.State == StateAlive {
+			helpers = append(helpers, m.Member)
+		}
+	}
+	rand.Shuffle(len(helpers), func(i, j int) { helpers[i], helpers[j] = helpers[j], helpers[i] })
+	helpers = helpers[:min(len(helpers), g.IndirectProbes)]
+	from, updates := memberToPB(g.self), g.piggybackLocked()
+	g.mu.Unlock()
+	if len(helpers) == 0 {
+		return false
+	}
+
+	// Helpers spend up to ProbeTimeout on their own ping
+	ctx, cancel := context.WithTimeout(ctx, 2*g.ProbeTimeout)
+	defer cancel()
+	acks := make(chan *pb.GossipAck, len(helpers))
+	for _, helper := range helpers {
+		go func() {
+			ack, err := g.transport.PingReq(ctx, helper.Addr, &pb.GossipPingReq{
+				From:          from,
+				TargetAddress: target.Addr,
+				Updates:       updates,
+			})
+			if err != nil {
+				ack = nil
+			}
+			acks <- ack
+		}()
+	}
+	for range helpers {
+		if ack := <-acks; ack != nil {
+			g.merge(ack.GetFrom(), ack.GetUpdates())
+			return true
+		}
+	}
+	return false
+}
+
+// expireSuspects declares members dead whose suspicion was not refuted in
+// time
+func (g *Gossip) expireSuspects() {
+	var notes []Member
+	g.mu.Lock()
+	for _, m := range g.members {
+		if m.State == StateSuspect && time.Since(m.suspectedAt) > g.SuspicionTimeout {
+			g.applyLocked(Member{ID: m.ID, Addr: m.Addr, State: StateDead, Incarnation: m.Incarnation}, &notes)
+		}
+	}
+	g.mu.Unlock()
+	g.notify(notes)
+}
+
+// Ping answers a direct probe. A member this one did not know yet gets the
+// whole membership in the ack.
+func (g *Gossip) Ping(ctx context.Context, ping *pb.GossipPing) (*pb.GossipAck, error) {
+	var notes []Member
+	g.mu.Lock()
+	from, known := g.members[ping.GetFrom().GetId()]
+	g.applyLocked(memberFromPB(ping.GetFrom()), &notes)
+	for _, u := range ping.GetUpdates() {
+		g.applyLocked(memberFromPB(u), &notes)
+	}
+	ack := &pb.GossipAck{From: memberToPB(g.self)}
+	if known {
+		ack.Updates = g.piggybackLocked()
+		if from.State != StateAlive {
+			// The sender is believed suspect or dead; tell it so it refutes
+			ack.Updates = append(ack.Updates, memberToPB(from.Member))
+		}
+	} else {
+		for _, m := range g.members {
+			ack.Updates = append(ack.Updates, memberToPB(m.Member))
+		}
+	}
+	g.mu.Unlock()
+	g.notify(notes)
+	return ack, nil
+}
+
+// PingReq probes a member on behalf of another one and passes on its ack
+func (g *Gossip) PingReq(ctx context.Context, req *pb.GossipPingReq) (*pb.GossipAck, error) {
+	g.merge(req.GetFrom(), req.GetUpdates())
+	ack, err := g.ping(ctx, req.GetTargetAddress())
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "%s did not answer: %v", req.GetTargetAddress(), err)
+	}
+	return ack, nil
+}
+
+// merge applies the sender of a message and the updates it carried
+func (g *Gossip) merge(from *pb.GossipMember, updates []*pb.GossipMember) {
+	var notes []Member
+	g.mu.Lock()
+	if from != nil {
+		g.applyLocked(memberFromPB(from), &notes)
+	}
+	for _, u := range updates {
+		g.applyLocked(memberFromPB(u), &notes)
+	}
+	g.mu.Unlock()
+	g.notify(notes)
+}
+
+// applyLocked merges an update into the membership. Newer incarnations win;
+// at the same incarnation suspect beats alive and dead beats both. A rumour
+// that this member is not alive is refuted with a higher incarnation.
+// Members whose state changed are appended to notes. g.mu must be held.
+func (g *Gossip) applyLocked(u Member, notes *[]Member) {
+	if u.ID == "" {
+		return
+	}
+	if u.ID == g.self.ID {
+		if u.State != StateAlive && u.Incarnation >= g.self.Incarnation {
+			g.self.Incarnation = u.Incarnation + 1
+			log.Printf("Gossip: refuting that %s is %s", u.ID, u.State)
+			g.enqueueLocked(g.self)
+		}
+		return
+	}
+
+	cur, known := g.members[u.ID]
+	if !known {
+		cur = &member{Member: u}
+		g.members[u.ID] = cur
+	} else if !supersedes(u, cur.Member) {
+		return
+	}
+	changed := !known || cur.State != u.State
+	if u.State == StateSuspect && (changed || cur.suspectedAt.IsZero()) {
+		cur.suspectedAt = time.Now()
+	}
+	if u.Addr == "" {
+		u.Addr = cur.Addr
+	}
+	cur.Member = u
+	g.enqueueLocked(u)
+	if changed {
+		*notes = append(*notes, u)
+	}
+}
+
+// supersedes reports whether update u replaces what is known as cur
+func supersedes(u, cur Member) bool {
+	if u.Incarnation != cur.Incarnation {
+		return u.Incarnation > cur.Incarnation
+	}
+	return u.State > cur.State
+}
+
+// enqueueLocked queues an update to be piggybacked, replacing an older
+// update about the same member
+func (g *Gossip) enqueueLocked(u Member) {
+	g.queue = slices.DeleteFunc(g.queue, func(b *broadcast) bool { return b.update.ID == u.ID })
+	g.queue = append(g.queue, &broadcast{update: u})
+}
+
+// piggybackLocked takes the updates sent least often so far for the next
+// message and drops those that went out often enough
+func (g *Gossip) piggybackLocked() []*pb.GossipMember {
+	limit := retransmitMult * int(math.Ceil(math.Log2(float64(len(g.members)+2))))
+	slices.SortStableFunc(g.queue, func(a, b *broadcast) int { return a.sent - b.sent })
+	var updates []*pb.GossipMember
+	for _, b := range g.queue[:min(len(g.queue), maxPiggyback)] {
+		updates = append(updates, memberToPB(b.update))
+		b.sent++
+	}
+	g.queue = slices.DeleteFunc(g.queue, func(b *broadcast) bool { return b.sent >= limit })
+	return updates
+}
+
+func (g *Gossip) notify(notes []Member) {
+	for _, m := range notes {
+		log.Printf("Gossip: %s at %s is %s", m.ID, m.Addr, m.State)
+		if g.OnChange != nil {
+			g.OnChange(m)
+		}
+	}
+}
+
+func memberToPB(m Member) *pb.GossipMember {
+	return &pb.GossipMember{
+		Id:          m.ID,
+		Address:     m.Addr,
+		State:       pb.GossipMember_State(m.State),
+		Incarnation: m.Incarnation,
+	}
+}
+
+func memberFromPB(m *pb.GossipMember) Member {
+	return Member{
+		ID:          m.GetId(),
+		Addr:        m.GetAddress(),
+		State:       MemberState(m.GetState()),
+		Incarnation: m.GetIncarnation(),
+	}
+}
@@ -0,0 +1,218 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+// gossipCluster is n gossip members on a MemoryNetwork, each configured with
+// all the others, as the server and the nodes are. Tests drive the protocol
+// periods themselves instead of running the members.
+type gossipCluster struct {
+	network *MemoryNetwork
+	members []*Gossip
+}
+
+func newGossipCluster(n int) *gossipCluster {
+	c := &gossipCluster{network: NewMemoryNetwork()}
+	for i := 1; i <= n; i++ {
+		g := c.network.Add(fmt.Sprintf("node%d", i), fmt.Sprintf("addr%d", i))
+		// Suspicions only expire when a test calls expire
+		g.SuspicionTimeout = 0
+		c.members = append(c.members, g)
+	}
+	for _, g := range c.members {
+		for _, other := range c.members {
+			g.Track(other.Self().ID, other.Self().Addr)
+		}
+	}
+	return c
+}
+
+// member returns node i, counting from 1
+func (c *gossipCluster) member(i int) *Gossip {
+	return c.members[i-1]
+}
+
+// rounds lets every member probe each of the others, n times over
+func (c *gossipCluster) rounds(n int) {
+	for range n {
+		for _, g := range c.members {
+			for range g.Members() {
+				g.probe(context.Background())
+			}
+		}
+	}
+}
+
+// expire declares every suspect dead
+func (c *gossipCluster) expire() {
+	for _, g := range c.members {
+		g.expireSuspects()
+	}
+}
+
+// stateOf returns what g believes about the member id
+func stateOf(g *Gossip, id string) Member {
+	for _, m := range g.Members() {
+		if m.ID == id {
+			return m
+		}
+	}
+	return Member{}
+}
+
+func TestGossipSuspectsThenDeclaresUnreachableMemberDead(t *testing.T) {
+	c := newGossipCluster(4)
+	c.rounds(1)
+	c.network.SetDown("addr4", true)
+
+	c.rounds(1)
+	for _, g := range c.members[:3] {
+		if got := stateOf(g, "node4").State; got != StateSuspect {
+			t.Errorf("%s believes node4 is %s before the suspicion expired, want suspect", g.Self().ID, got)
+		}
+	}
+	c.expire()
+	c.rounds(1)
+	for _, g := range c.members[:3] {
+		if got := stateOf(g, "node4").State; got != StateDead {
+			t.Errorf("%s believes node4 is %s, want dead", g.Self().ID, got)
+		}
+	}
+}
+
+func TestGossipReachesMemberThroughIndirectProbes(t *testing.T) {
+	c := newGossipCluster(3)
+	c.rounds(1)
+	// Cut only the link from node1 to node3: node1 sends through a network
+	// that holds nothing but node2, which still reaches node3
+	direct := NewMemoryNetwork()
+	direct.members = map[string]*Gossip{"addr2": c.member(2)}
+	c.member(1).transport = &memoryTransport{network: direct, from: "addr1"}
+	before := c.member(3).Self().Incarnation
+	for range 4 {
+		c.member(1).probe(context.Background())
+	}
+	if m := stateOf(c.member(1), "node3"); m.State != StateAlive || m.Incarnation != before {
+		t.Errorf("node1 believes node3 is %s at incarnation %d although node2 reaches it, want alive at %d",
+			m.State, m.Incarnation, before)
+	}
+}
+
+func TestGossipSuspicionIsRefuted(t *testing.T) {
+	c := newGossipCluster(3)
+	c.rounds(1)
+	before := c.member(3).Self().Incarnation
+
+	c.network.SetDown("addr3", true)
+	c.rounds(1)
+	if got := stateOf(c.member(1), "node3").State; got != StateSuspect {
+		t.Fatalf("node1 believes node3 is %s, want suspect", got)
+	}
+	// node3 comes back before the suspicion expired and hears of it with
+	// the next probe
+	c.network.SetDown("addr3", false)
+	c.rounds(1)
+	c.expire()
+
+	if after := c.member(3).Self().Incarnation; after <= before {
+		t.Errorf("node3 kept incarnation %d, want it raised above %d to refute", after, before)
+	}
+	for _, g := range c.members[:2] {
+		if m := stateOf(g, "node3"); m.State != StateAlive || m.Incarnation != c.member(3).Self().Incarnation {
+			t.Errorf("%s believes node3 is %s at incarnation %d, want alive at %d",
+				g.Self().ID, m.State, m.Incarnation, c.member(3).Self().Incarnation)
+		}
+	}
+}
+
+func TestGossipPartitionHeals(t *testing.T) {
+	c := newGossipCluster(4)
+	c.rounds(1)
+	c.network.Partition([]string{"addr1", "addr2"}, []string{"addr3", "addr4"})
+	c.rounds(1)
+	c.expire()
+	c.rounds(1)
+
+	// Each side declares the other one dead
+	for i, g := range c.members {
+		for j, other := range c.members {
+			sameSide := (i < 2) == (j < 2)
+			if i == j {
+				continue
+			}
+			want := StateDead
+			if sameSide {
+				want = StateAlive
+			}
+			if got := stateOf(g, other.Self().ID).State; got != want {
+				t.Errorf("%s believes %s is %s during the partition, want %s", g.Self().ID, other.Self().ID, got, want)
+			}
+		}
+	}
+
+	// Dead members are still probed, so they refute their death once the
+	// partition heals
+	c.network.Heal()
+	c.rounds(3)
+	for _, g := range c.members {
+		for _, m := range g.Members() {
+			if m.State != StateAlive {
+				t.Errorf("%s believes %s is %s after the partition healed", g.Self().ID, m.ID, m.State)
+			}
+		}
+	}
+}
+
+func TestGossipFeedsRingFallbacks(t *testing.T) {
+	c := newGossipCluster(5)
+	ring := NewHashRing(10, 2)
+	for _, g := range c.members {
+		ring.AddNode(g.Self().ID)
+	}
+	// Wired like the server: dead members are marked down on the ring
+	c.member(1).OnChange = func(m Member) {
+		ring.SetDown(m.ID, m.State == StateDead)
+	}
+	// key lives elsewhere and may fall back to node5; owned lives on node5
+	key, owned := "", ""
+	for i := 0; key == "" || owned == ""; i++ {
+		candidate := fmt.Sprintf("key-%d", i)
+		if slices.Contains(ring.GetNodes(candidate), "node5") {
+			owned = candidate
+		} else {
+			key = candidate
+		}
+	}
+	if fallbacks := ring.GetFallbackNodes(key); !slices.Contains(fallbacks, "node5") {
+		t.Fatalf("GetFallbackNodes(%q) = %v, want node5 among them while it is alive", key, fallbacks)
+	}
+
+	c.network.SetDown("addr5", true)
+	c.rounds(1)
+	if ring.IsDown("node5") {
+		t.Fatalf("node5 is down on the ring while it is only suspected")
+	}
+	c.expire()
+	if !ring.IsDown("node5") {
+		t.Fatalf("node5 is not down on the ring after it was declared dead")
+	}
+	if fallbacks := ring.GetFallbackNodes(key); slices.Contains(fallbacks, "node5") {
+		t.Errorf("GetFallbackNodes(%q) = %v offers the dead node5", key, fallbacks)
+	}
+	if nodes := ring.GetNodes(owned); !slices.Contains(nodes, "node5") {
+		t.Errorf("GetNodes(%q) = %v, a dead node must keep its place on the ring", owned, nodes)
+	}
+
+	c.network.SetDown("addr5", false)
+	c.rounds(2)
+	if ring.IsDown("node5") {
+		t.Errorf("node5 is still down on the ring after it came back")
+	}
+	if fallbacks := ring.GetFallbackNodes(key); !slices.Contains(fallbacks, "node5") {
+		t.Errorf("GetFallbackNodes(%q) = %v, want node5 back among them", key, fallbacks)
+	}
+}
@@ -0,0 +1,150 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	pb "badies/proto/badiespb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// GRPCTransport sends gossip messages over the Gossip gRPC service, keeping
+// one connection per address
+type GRPCTransport struct {
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+// NewGRPCTransport creates a transport without connections
+func NewGRPCTransport() *GRPCTransport {
+	return &GRPCTransport{conns: make(map[string]*grpc.ClientConn)}
+}
+
+func (t *GRPCTransport) client(addr string) (pb.GossipClient, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	conn, ok := t.conns[addr]
+	if !ok {
+		var err error
+		conn, err = grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create gossip client for %s: %v", addr, err)
+		}
+		t.conns[addr] = conn
+	}
+	return pb.NewGossipClient(conn), nil
+}
+
+func (t *GRPCTransport) Ping(ctx context.Context, addr string, ping *pb.GossipPing) (*pb.GossipAck, error) {
+	client, err := t.client(addr)
+	if err != nil {
+		return nil, err
+	}
+	return client.Ping(ctx, ping)
+}
+
+func (t *GRPCTransport) PingReq(ctx context.Context, addr string, req *pb.GossipPingReq) (*pb.GossipAck, error) {
+	client, err := t.client(addr)
+	if err != nil {
+		return nil, err
+	}
+	return client.PingReq(ctx, req)
+}
+
+// Close closes every connection
+func (t *GRPCTransport) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for addr, conn := range t.conns {
+		conn.Close()
+		delete(t.conns, addr)
+	}
+}
+
+// MemoryNetwork connects gossip members within one process, so failure
+// detection can be exercised without sockets. Members can be taken down and
+// brought back, and the network can be partitioned.
+type MemoryNetwork struct {
+	mu      sync.Mutex
+	members map[string]*Gossip
+	down    map[string]bool
+	sides   map[string]int // side of each address while partitioned
+}
+
+// NewMemoryNetwork creates an empty network
+func NewMemoryNetwork() *MemoryNetwork {
+	return &MemoryNetwork{members: make(map[string]*Gossip), down: make(map[string]bool)}
+}
+
+// Add creates a member reachable at addr on the network
+func (n *MemoryNetwork) Add(id, addr string) *Gossip {
+	g := NewGossip(id, addr, &memoryTransport{network: n, from: addr})
+	n.mu.Lock()
+	n.members[addr] = g
+	n.mu.Unlock()
+	return g
+}
+
+// SetDown cuts the member at addr off the network, or reconnects it
+func (n *MemoryNetwork) SetDown(addr string, down bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.down[addr] = down
+}
+
+// Partition splits the network into sides, given as lists of addresses.
+// Members only reach members on their own side; the members in no list form
+// one more side together.
+func (n *MemoryNetwork) Partition(sides ...[]string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sides = make(map[string]int)
+	for i, side := range sides {
+		for _, addr := range side {
+			n.sides[addr] = i + 1
+		}
+	}
+}
+
+// Heal joins the sides of a partition again
+func (n *MemoryNetwork) Heal() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sides = nil
+}
+
+// reach returns the member at to if both ends are up and on the same side
+func (n *MemoryNetwork) reach(from, to string) (*Gossip, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	g, ok := n.members[to]
+	if !ok || n.down[to] || n.down[from] || n.sides[from] != n.sides[to] {
+		return nil, fmt.Errorf("%s is unreachable from %s", to, from)
+	}
+	return g, nil
+}
+
+// memoryTransport is the Transport of one member of a MemoryNetwork
+type memoryTransport struct {
+	network *MemoryNetwork
+	from    string
+}
+
+func (t *memoryTransport) Ping(ctx context.Context, addr string, ping *pb.GossipPing) (*pb.GossipAck, error) {
+	g, err := t.network.reach(t.from, addr)
+	if err != nil {
+		return nil, err
+	}
+	return g.Ping(ctx, ping)
+}
+
+func (t *memoryTransport) PingReq(ctx context.Context, addr string, req *pb.GossipPingReq) (*pb.GossipAck, error) {
+	g, err := t.network.reach(t.from, addr)
+	if err != nil {
+		return nil, err
+	}
+	return g.PingReq(ctx, req)
+}
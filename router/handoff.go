@@ -0,0 +1,94 @@
+package router
+
+import (
+	"context"
+	"io"
+	"log"
+	"time"
+
+	pb "badies/proto/badiespb"
+)
+
+// DefaultHintReplayInterval is how often held hints are retried
+const DefaultHintReplayInterval = 10 * time.Second
+
+// HintReplayer delivers hints held by storage nodes to the replicas they
+// were written for, once those replicas are reachable again
+type HintReplayer struct {
+	nm *NodeManager
+
+	// Interval between replay passes
+	Interval time.Duration
+}
+
+// NewHintReplayer creates a replayer for the nodes in nm
+func NewHintReplayer(nm *NodeManager) *HintReplayer {
+	return &HintReplayer{nm: nm, Interval: DefaultHintReplayInterval}
+}
+
+// Run replays hints every Interval until ctx is cancelled
+func (hr *HintReplayer) Run(ctx context.Context) {
+	ticker := time.NewTicker(hr.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if delivered := hr.ReplayOnce(ctx); delivered > 0 {
+				log.Printf("Hinted handoff delivered %d hints", delivered)
+			}
+		}
+	}
+}
+
+// ReplayOnce walks the hints of every node once and returns how many were
+// delivered. Targets that fail are skipped for the rest of the pass.
+func (hr *HintReplayer) ReplayOnce(ctx context.Context) int {
+	delivered := 0
+	for _, holder := range hr.nm.ListNodes() {
+		holderClient, err := hr.nm.GetClient(holder)
+		if err != nil {
+			continue
+		}
+		stream, err := holderClient.StreamHints(ctx, &pb.NodeStreamHintsRequest{})
+		if err != nil {
+			continue
+		}
+
+		unreachable := make(map[string]bool)
+		for {
+			hint, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				log.Printf("Streaming hints from node %s failed: %v", holder, err)
+				break
+			}
+			target := hint.GetTarget()
+			if unreachable[target] {
+				continue
+			}
+			targetClient, err := hr.nm.GetClient(target)
+			if err == nil {
+				err = copyRecord(ctx, targetClient, hint.GetRecord())
+			}
+			if err != nil {
+				unreachable[target] = true
+				continue
+			}
+
+			_, err = holderClient.DeleteHint(ctx, &pb.NodeDeleteHintRequest{
+				Target:  target,
+				Key:     hint.GetRecord().GetKey(),
+				Version: hint.GetRecord().GetVersion(),
+			})
+			if err != nil {
+				log.Printf("Failed to drop delivered hint for %s on node %s: %v", target, holder, err)
+			}
+			delivered++
+		}
+	}
+	return delivered
+}
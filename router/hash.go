@@ -0,0 +1,86 @@
+package router
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"hash/fnv"
+	"slices"
+	"strings"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/twmb/murmur3"
+)
+
+// HashFunc places a key on the uint32 hash circle
+type HashFunc func(key []byte) uint32
+
+// DefaultHash is the hash of rings that do not name one, which is what every
+// ring used before hashes could be chosen
+const DefaultHash = "crc32"
+
+// OrderedHash names the order-preserving position of the range partitioner
+const OrderedHash = "ordered"
+
+var hashes = map[string]HashFunc{
+	"crc32":   crc32.ChecksumIEEE,
+	"fnv":     fnv32a,
+	"murmur3": murmur3.Sum32,
+	"xxhash":  xxhash32,
+	"ordered": orderedPosition,
+}
+
+// LookupHash returns the hash function with the given name. An empty name
+// is DefaultHash.
+func LookupHash(name string) (HashFunc, error) {
+	if name == "" {
+		name = DefaultHash
+	}
+	hash, ok := hashes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown hash %q, want one of %s", name, strings.Join(HashNames(), ", "))
+	}
+	return hash, nil
+}
+
+// HashNames returns the names LookupHash accepts, sorted
+func HashNames() []string {
+	names := make([]string, 0, len(hashes))
+	for name := range hashes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// HashKey returns the position of key under DefaultHash
+func HashKey(key string) uint32 {
+	return crc32.ChecksumIEEE([]byte(key))
+}
+
+func fnv32a(key []byte) uint32 {
+	h := fnv.New32a()
+	h.Write(key)
+	return h.Sum32()
+}
+
+// xxhash32 folds the 64-bit xxHash into the circle
+func xxhash32(key []byte) uint32 {
+	h := xxhash.Sum64(key)
+	return uint32(h) ^ uint32(h>>32)
+}
+
+// OrderedPositionBytes is how many leading bytes of a key decide its
+// position under OrderedHash, all that fit on the uint32 circle
+const OrderedPositionBytes = 4
+
+// orderedPosition keeps the byte order of keys: the position is the first
+// OrderedPositionBytes of the key, big endian and padded with zeros. Keys
+// sharing those bytes share a position, so no split point can separate them:
+// keys with a longer common prefix, such as "user:", all live in one
+// partition. The skew tool reports the largest share of keys at one position.
+func orderedPosition(key []byte) uint32 {
+	var prefix [OrderedPositionBytes]byte
+	copy(prefix[:], key)
+	return binary.BigEndian.Uint32(prefix[:])
+}
@@ -0,0 +1,109 @@
+package router
+
+import (
+	"slices"
+	"sort"
+)
+
+// HashRange is the arc (Start, End] of the hash circle. A range with
+// Start >= End wraps around zero, so Start == End covers the whole circle.
+type HashRange struct {
+	Start uint32
+	End   uint32
+}
+
+// Contains reports whether hash falls into the range
+func (r HashRange) Contains(hash uint32) bool {
+	if r.Start < r.End {
+		return hash > r.Start && hash <= r.End
+	}
+	return hash > r.Start || hash <= r.End
+}
+
+// Len returns the number of hash positions in the range
+func (r HashRange) Len() uint64 {
+	if r.Start == r.End {
+		return 1 << 32
+	}
+	return uint64(r.End - r.Start) // wraps around zero as intended
+}
+
+// Bucket splits the range into n equal buckets and returns the one hash
+// falls into. hash must be contained in the range.
+func (r HashRange) Bucket(hash uint32, n int) int {
+	offset := uint64(hash - r.Start - 1)
+	return int(offset * uint64(n) / r.Len())
+}
+
+// SubRange returns bucket i of n as its own range. ok is false if the bucket
+// is empty, which happens when the range is shorter than n.
+func (r HashRange) SubRange(i, n int) (sub HashRange, ok bool) {
+	length := r.Len()
+	lo := (uint64(i)*length + uint64(n) - 1) / uint64(n)
+	hi := (uint64(i+1)*length + uint64(n) - 1) / uint64(n)
+	if lo == hi {
+		return HashRange{}, false
+	}
+	return HashRange{Start: r.Start + uint32(lo), End: r.Start + uint32(hi)}, true
+}
+
+// Segment is a range of the ring together with the nodes that replicate it
+type Segment struct {
+	Range HashRange
+	Nodes []string
+}
+
+// Segments returns the ranges between consecutive tokens on the ring with
+// their replica sets. Adjacent ranges with the same replicas are merged.
+func (h *HashRing) Segments() []Segment {
+	return h.segmentsOf(HashRange{}, true)
+}
+
+// SegmentsIn splits rng at the ring's tokens and returns the pieces with
+// their replica sets
+func (h *HashRing) SegmentsIn(rng HashRange) []Segment {
+	return h.segmentsOf(rng, false)
+}
+
+func (h *HashRing) segmentsOf(rng HashRange, whole bool) []Segment {
+	tokens := h.tokens()
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	var bounds []uint32
+	if whole {
+		bounds = tokens
+	} else {
+		// Cut points inside the range, ordered from its start, then its end
+		for _, t := range tokens {
+			if rng.Contains(t) && t != rng.End {
+				bounds = append(bounds, t)
+			}
+		}
+		sort.Slice(bounds, func(i, j int) bool {
+			return bounds[i]-rng.Start < bounds[j]-rng.Start
+		})
+		bounds = append(bounds, rng.End)
+	}
+
+	var segments []Segment
+	for i, end := range bounds {
+		var start uint32
+		switch {
+		case whole:
+			start = bounds[(i+len(bounds)-1)%len(bounds)]
+		case i == 0:
+			start = rng.Start
+		default:
+			start = bounds[i-1]
+		}
+		nodes := h.ownersOf(end)
+		if n := len(segments); n > 0 && segments[n-1].Range.End == start && slices.Equal(segments[n-1].Nodes, nodes) {
+			segments[n-1].Range.End = end
+			continue
+		}
+		segments = append(segments, Segment{Range: HashRange{Start: start, End: end}, Nodes: nodes})
+	}
+	return segments
+}
@@ -1,115 +1,255 @@
 package router
 
 import (
-	"hash/crc32"
+	"slices"
 	"sort"
-	"strconv"
 	"sync"
 )
 
 type HashRing struct {
-	replicas   int
-	hashCircle map[uint32]string
-	sortedKeys []uint32
-	nodes      map[string]bool
-	mu         sync.RWMutex
+	partitioner       Partitioner
+	replicationFactor int                 // distinct nodes returned by GetNodes
+	nodes             map[string]int      // node ID -> weight
+	order             []string            // node IDs in the order they joined
+	nodeTokens        map[string][]uint32 // tokens of each node, for a TokenAssigner
+	parts             []Partition         // placement, sorted by End and never modified
+	epoch             uint64              // bumped by every membership change
+	down              map[string]bool     // nodes failure detection declared dead
+	mu                sync.RWMutex
 }
 
-func NewHashRing(replicas int) *HashRing {
+// NewHashRing creates a consistent hashing ring that places vnodes virtual
+// nodes on the circle for every unit of node weight, hashed with
+// DefaultHash, and returns replicationFactor distinct nodes for each key
+func NewHashRing(vnodes int, replicationFactor int) *HashRing {
+	hash, _ := LookupHash(DefaultHash)
+	return NewPartitionedRing(&consistentPartitioner{
+		cfg:  PartitionerConfig{Name: Consistent, Hash: DefaultHash, VNodes: vnodes},
+		hash: hash,
+	}, replicationFactor)
+}
+
+// NewPartitionedRing creates a ring that places keys with p and returns
+// replicationFactor distinct nodes for each key
+func NewPartitionedRing(p Partitioner, replicationFactor int) *HashRing {
 	return &HashRing{
-		replicas:   replicas,
-		hashCircle: make(map[uint32]string),
-		nodes:      make(map[string]bool),
+		partitioner:       p,
+		replicationFactor: replicationFactor,
+		nodes:             make(map[string]int),
+		nodeTokens:        make(map[string][]uint32),
 	}
 }
 
-func HashKey(key string) uint32 {
-	return crc32.ChecksumIEEE([]byte(key))
+// AddNode adds a node with weight 1
+func (h *HashRing) AddNode(nodeID string) {
+	h.AddNodeWithWeight(nodeID, 1)
 }
 
-func (h *HashRing) AddNode(nodeID string) {
+// AddNodeWithWeight adds a node that counts weight times as much as a node
+// of weight 1, so bigger nodes receive a proportionally larger key share
+func (h *HashRing) AddNodeWithWeight(nodeID string, weight int) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	if h.nodes[nodeID] {
+	if _, exists := h.nodes[nodeID]; exists {
 		return
 	}
-
-	h.nodes[nodeID] = true
-	for i := 0; i < h.replicas; i++ {
-		virtualNode := nodeID + "#" + strconv.Itoa(i)
-		hash := HashKey(virtualNode)
-		h.hashCircle[hash] = virtualNode
-		h.sortedKeys = append(h.sortedKeys, hash)
+	if weight < 1 {
+		weight = 1
 	}
-	sort.Slice(h.sortedKeys, func(i, j int) bool {
-		return h.sortedKeys[i] < h.sortedKeys[j]
-	})
+	h.epoch++
+	if ta, ok := h.partitioner.(TokenAssigner); ok {
+		h.nodeTokens[nodeID] = ta.AssignTokens(nodeID, weight, h.placedNodesLocked())
+	}
+	h.nodes[nodeID] = weight
+	h.order = append(h.order, nodeID)
+	h.placeLocked()
 }
 
+// RemoveNode takes a node and all of its tokens off the ring
 func (h *HashRing) RemoveNode(nodeID string) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
-	if !h.nodes[nodeID] {
+	if _, exists := h.nodes[nodeID]; !exists {
 		return
 	}
-
+	h.epoch++
 	delete(h.nodes, nodeID)
-	newSortedKeys := []uint32{}
+	delete(h.nodeTokens, nodeID)
+	h.order = slices.DeleteFunc(slices.Clone(h.order), func(id string) bool { return id == nodeID })
+	h.placeLocked()
+}
 
-	for _, hash := range h.sortedKeys {
-		if h.hashCircle[hash] != nodeID {
-			newSortedKeys = append(newSortedKeys, hash)
-		} else {
-			delete(h.hashCircle, hash)
-		}
+// placeLocked recomputes the partitions after a membership change. The
+// caller must hold h.mu.
+func (h *HashRing) placeLocked() {
+	h.parts = h.partitioner.Place(h.placedNodesLocked(), h.replicationFactor)
+}
+
+// placedNodesLocked returns the nodes in join order. The caller must hold
+// h.mu.
+func (h *HashRing) placedNodesLocked() []PlacedNode {
+	nodes := make([]PlacedNode, 0, len(h.order))
+	for _, nodeID := range h.order {
+		nodes = append(nodes, PlacedNode{ID: nodeID, Weight: h.nodes[nodeID], Tokens: h.nodeTokens[nodeID]})
 	}
-	h.sortedKeys = newSortedKeys
+	return nodes
+}
+
+// Partitioner returns the partitioner that places the ring's keys
+func (h *HashRing) Partitioner() Partitioner {
+	return h.partitioner
+}
+
+// HashName returns the name of the hash that positions keys, for nodes
+// that select keys by position
+func (h *HashRing) HashName() string {
+	return h.partitioner.Config().Hash
+}
+
+// Position returns where key lies on the circle
+func (h *HashRing) Position(key string) uint32 {
+	return h.partitioner.Position([]byte(key))
 }
 
+// GetNodes returns the distinct physical nodes responsible for key, primary
+// first. It returns fewer than the replication factor when the ring has fewer
+// nodes.
 func (h *HashRing) GetNodes(key string) []string {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	if len(h.sortedKeys) == 0 {
+	if len(h.parts) == 0 {
 		return nil
 	}
+	return slices.Clone(h.parts[h.partitionLocked(h.Position(key))].Owners)
+}
 
-	hash := HashKey(key)
-	result := make([]string, 0, 3)
+// GetFallbackNodes returns the nodes that replicate the partitions following
+// the one of key, in ring order, without the replicas of key. They stand in
+// for unreachable replicas.
+func (h *HashRing) GetFallbackNodes(key string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if len(h.parts) == 0 || len(h.nodes) <= h.replicationFactor {
+		return nil
+	}
+	i := h.partitionLocked(h.Position(key))
 	seen := make(map[string]bool)
+	for _, nodeID := range h.parts[i].Owners {
+		seen[nodeID] = true
+	}
+	var fallbacks []string
+	for j := 1; j < len(h.parts) && len(seen) < len(h.nodes); j++ {
+		for _, nodeID := range h.parts[(i+j)%len(h.parts)].Owners {
+			if seen[nodeID] {
+				continue
+			}
+			seen[nodeID] = true
+			if !h.down[nodeID] {
+				fallbacks = append(fallbacks, nodeID)
+			}
+		}
+	}
+	return fallbacks
+}
 
-	startIdx := sort.Search(len(h.sortedKeys), func(i int) bool {
-		return h.sortedKeys[i] >= hash
-	})
+// SetDown marks a node as dead or alive again. A dead node keeps its place
+// on the ring, so its keys do not move, but it is no longer offered as a
+// fallback and callers can skip it without waiting for a timeout.
+func (h *HashRing) SetDown(nodeID string, down bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if down {
+		if h.down == nil {
+			h.down = make(map[string]bool)
+		}
+		h.down[nodeID] = true
+	} else {
+		delete(h.down, nodeID)
+	}
+}
+
+// IsDown reports whether the node was marked as dead
+func (h *HashRing) IsDown(nodeID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.down[nodeID]
+}
 
-	if startIdx == len(h.sortedKeys) {
-		startIdx = 0
+// partitionLocked returns the index of the partition holding position. The
+// caller must hold h.mu and the ring must not be empty.
+func (h *HashRing) partitionLocked(position uint32) int {
+	i := sort.Search(len(h.parts), func(i int) bool {
+		return h.parts[i].End >= position
+	})
+	if i == len(h.parts) {
+		i = 0
 	}
+	return i
+}
 
-	for i := 0; i < len(h.sortedKeys) && len(result) < 3; i++ {
-		idx := (startIdx + i) % len(h.sortedKeys)
-		node := h.hashCircle[h.sortedKeys[idx]]
+// Clone returns an independent copy of the ring
+func (h *HashRing) Clone() *HashRing {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.cloneLocked()
+}
 
-		if !seen[node] {
-			result = append(result, node)
-			seen[node] = true
-		}
+func (h *HashRing) cloneLocked() *HashRing {
+	c := NewPartitionedRing(h.partitioner, h.replicationFactor)
+	for node, weight := range h.nodes {
+		c.nodes[node] = weight
+	}
+	for node, tokens := range h.nodeTokens {
+		c.nodeTokens[node] = tokens
 	}
+	c.order = slices.Clone(h.order)
+	c.parts = h.parts
+	c.epoch = h.epoch
+	return c
+}
 
-	return result
+// tokens returns the sorted ends of the ring's partitions
+func (h *HashRing) tokens() []uint32 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	tokens := make([]uint32, len(h.parts))
+	for i, part := range h.parts {
+		tokens[i] = part.End
+	}
+	return tokens
+}
+
+// ownersOf returns the nodes responsible for a hash position
+func (h *HashRing) ownersOf(hash uint32) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if len(h.parts) == 0 {
+		return nil
+	}
+	return slices.Clone(h.parts[h.partitionLocked(hash)].Owners)
 }
 
+// GetAllNodes returns the ring's nodes in the order they joined
 func (h *HashRing) GetAllNodes() []string {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
+	return slices.Clone(h.order)
+}
 
-	nodes := make([]string, 0, len(h.nodes))
-	for node := range h.nodes {
-		nodes = append(nodes, node)
-	}
-	return nodes
+// Weight returns the weight of a node, or 0 if it is not on the ring
+func (h *HashRing) Weight(nodeID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.nodes[nodeID]
+}
+
+// ReplicationFactor returns the number of nodes each key is stored on
+func (h *HashRing) ReplicationFactor() int {
+	return h.replicationFactor
 }
 
 func (h *HashRing) IsEmpty() bool {
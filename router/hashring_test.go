@@ -0,0 +1,232 @@
+package router
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// randomRing builds a ring of n nodes with weights from 1 to maxWeight
+func randomRing(rng *rand.Rand, vnodes, rf, n, maxWeight int) *HashRing {
+	ring := NewHashRing(vnodes, rf)
+	for i := 0; i < n; i++ {
+		ring.AddNodeWithWeight(fmt.Sprintf("node%d", i+1), 1+rng.IntN(maxWeight))
+	}
+	return ring
+}
+
+func randomKeys(rng *rand.Rand, n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key-%d-%d", i, rng.Uint64())
+	}
+	return keys
+}
+
+func TestRemoveNodeRemovesVirtualNodes(t *testing.T) {
+	ring := NewHashRing(10, 3)
+	for i := 1; i <= 5; i++ {
+		ring.AddNode("node" + strconv.Itoa(i))
+	}
+	epoch := ring.Epoch()
+	ring.RemoveNode("node3")
+
+	if ring.Epoch() != epoch+1 {
+		t.Errorf("epoch = %d after removal, want %d", ring.Epoch(), epoch+1)
+	}
+	if len(ring.parts) != 40 || len(ring.nodeTokens) != 4 {
+		t.Errorf("ring holds %d partitions and tokens of %d nodes, want 40 and 4", len(ring.parts), len(ring.nodeTokens))
+	}
+	for _, part := range ring.parts {
+		if slices.Contains(part.Owners, "node3") {
+			t.Fatalf("partition ending at %d is still replicated on node3", part.End)
+		}
+	}
+	if slices.Contains(ring.GetAllNodes(), "node3") {
+		t.Errorf("GetAllNodes still lists node3")
+	}
+	for _, key := range randomKeys(rand.New(rand.NewPCG(1, 0)), 2000) {
+		if nodes := ring.GetNodes(key); slices.Contains(nodes, "node3") {
+			t.Fatalf("GetNodes(%q) = %v still returns the removed node", key, nodes)
+		}
+	}
+}
+
+func TestRemoveNodeKeepsPrefixedNodes(t *testing.T) {
+	// node1 must not take node10's virtual nodes along
+	ring := NewHashRing(10, 2)
+	ring.AddNode("node1")
+	ring.AddNode("node10")
+	ring.RemoveNode("node1")
+	if len(ring.parts) != 10 {
+		t.Fatalf("ring holds %d partitions, want one per token of node10", len(ring.parts))
+	}
+	if nodes := ring.GetNodes("key"); !slices.Equal(nodes, []string{"node10"}) {
+		t.Errorf("GetNodes = %v, want [node10]", nodes)
+	}
+}
+
+func TestReplicasAreDistinctMembers(t *testing.T) {
+	rng := rand.New(rand.NewPCG(2, 0))
+	for trial := 0; trial < 50; trial++ {
+		n, rf := 1+rng.IntN(10), 1+rng.IntN(5)
+		ring := randomRing(rng, 1+rng.IntN(20), rf, n, 4)
+		members := ring.GetAllNodes()
+		for _, key := range randomKeys(rng, 200) {
+			nodes := ring.GetNodes(key)
+			if len(nodes) != min(rf, n) {
+				t.Fatalf("n=%d rf=%d: GetNodes(%q) = %v, want %d nodes", n, rf, key, nodes, min(rf, n))
+			}
+			seen := make(map[string]bool)
+			for _, node := range nodes {
+				if seen[node] {
+					t.Fatalf("n=%d rf=%d: GetNodes(%q) = %v repeats %s", n, rf, key, nodes, node)
+				}
+				if !slices.Contains(members, node) {
+					t.Fatalf("n=%d rf=%d: GetNodes(%q) = %v returns unknown node %s", n, rf, key, nodes, node)
+				}
+				seen[node] = true
+			}
+			if again := ring.GetNodes(key); !slices.Equal(nodes, again) {
+				t.Fatalf("GetNodes(%q) is not deterministic: %v then %v", key, nodes, again)
+			}
+		}
+	}
+}
+
+func TestFallbackNodesFollowReplicas(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 0))
+	ring := randomRing(rng, 10, 3, 7, 1)
+	ring.SetDown("node2", true)
+	for _, key := range randomKeys(rng, 500) {
+		replicas := ring.GetNodes(key)
+		fallbacks := ring.GetFallbackNodes(key)
+		for _, node := range fallbacks {
+			if slices.Contains(replicas, node) {
+				t.Fatalf("fallback %s of %q is a replica %v", node, key, replicas)
+			}
+			if node == "node2" {
+				t.Fatalf("fallbacks %v of %q include a node that is down", fallbacks, key)
+			}
+		}
+		want := 7 - 3
+		if !slices.Contains(replicas, "node2") {
+			want--
+		}
+		if len(fallbacks) != want {
+			t.Fatalf("GetFallbackNodes(%q) = %v, want %d nodes", key, fallbacks, want)
+		}
+	}
+}
+
+// TestBalance checks that primaries spread in proportion to node weight.
+// CRC32 over virtual node names is uneven, so the bound is loose.
+func TestBalance(t *testing.T) {
+	const vnodes, keyCount, tolerance = 200, 50000, 0.35
+	for _, tc := range []struct {
+		name    string
+		weights map[string]int
+	}{
+		{"equal", map[string]int{"node1": 1, "node2": 1, "node3": 1, "node4": 1, "node5": 1}},
+		{"weighted", map[string]int{"node1": 1, "node2": 2, "node3": 1, "node4": 3}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ring := NewHashRing(vnodes, 3)
+			total := 0
+			for node, weight := range tc.weights {
+				ring.AddNodeWithWeight(node, weight)
+				total += weight
+			}
+			primaries := make(map[string]int)
+			for _, key := range randomKeys(rand.New(rand.NewPCG(4, 0)), keyCount) {
+				primaries[ring.GetNodes(key)[0]]++
+			}
+			for node, weight := range tc.weights {
+				want := float64(keyCount) * float64(weight) / float64(total)
+				if got := float64(primaries[node]); got < want*(1-tolerance) || got > want*(1+tolerance) {
+					t.Errorf("%s (weight %d) is primary for %.0f keys, want %.0f ± %.0f%%",
+						node, weight, got, want, tolerance*100)
+				}
+			}
+		})
+	}
+}
+
+func TestAddNodeMovesOnlyKeysToNewNode(t *testing.T) {
+	rng := rand.New(rand.NewPCG(5, 0))
+	for trial := 0; trial < 20; trial++ {
+		n, rf := 3+rng.IntN(8), 1+rng.IntN(3)
+		before := randomRing(rng, 50, rf, n, 1)
+		after := before.Clone()
+		after.AddNode("new")
+
+		moved := 0
+		keys := randomKeys(rng, 5000)
+		for _, key := range keys {
+			was, is := before.GetNodes(key), after.GetNodes(key)
+			if slices.Equal(was, is) {
+				continue
+			}
+			moved++
+			// The new node takes one slot; the others keep their order
+			if !slices.Contains(is, "new") {
+				t.Fatalf("%q moved from %v to %v without the new node", key, was, is)
+			}
+			rest := slices.DeleteFunc(slices.Clone(is), func(node string) bool { return node == "new" })
+			if !slices.Equal(rest, was[:len(rest)]) {
+				t.Fatalf("%q moved from %v to %v, more than the new node changed", key, was, is)
+			}
+		}
+		// The new node should take about rf/(n+1) of the replica slots
+		expected := float64(len(keys)) * float64(rf) / float64(n+1)
+		if float64(moved) > 2*expected {
+			t.Errorf("n=%d rf=%d: %d of %d keys moved, expected about %.0f", n, rf, moved, len(keys), expected)
+		}
+	}
+}
+
+func TestRemoveNodeMovesOnlyItsKeys(t *testing.T) {
+	rng := rand.New(rand.NewPCG(6, 0))
+	for trial := 0; trial < 20; trial++ {
+		n, rf := 3+rng.IntN(8), 1+rng.IntN(3)
+		before := randomRing(rng, 50, rf, n, 3)
+		removed := fmt.Sprintf("node%d", 1+rng.IntN(n))
+		after := before.Clone()
+		after.RemoveNode(removed)
+
+		for _, key := range randomKeys(rng, 5000) {
+			was, is := before.GetNodes(key), after.GetNodes(key)
+			if !slices.Contains(was, removed) {
+				if !slices.Equal(was, is) {
+					t.Fatalf("%q moved from %v to %v, but did not live on %s", key, was, is, removed)
+				}
+				continue
+			}
+			// The survivors keep their order and one node fills the gap
+			kept := slices.DeleteFunc(slices.Clone(was), func(node string) bool { return node == removed })
+			if !slices.Equal(is[:len(kept)], kept) {
+				t.Fatalf("%q moved from %v to %v after removing %s", key, was, is, removed)
+			}
+		}
+	}
+}
+
+func TestRestoreHashRingReproducesPlacement(t *testing.T) {
+	rng := rand.New(rand.NewPCG(7, 0))
+	ring := randomRing(rng, 20, 3, 6, 3)
+	ring.RemoveNode("node4")
+	restored, err := RestoreHashRing(ring.Topology(), 3)
+	if err != nil {
+		t.Fatalf("RestoreHashRing: %v", err)
+	}
+	if restored.Epoch() != ring.Epoch() {
+		t.Errorf("restored epoch %d, want %d", restored.Epoch(), ring.Epoch())
+	}
+	for _, key := range randomKeys(rng, 2000) {
+		if want, got := ring.GetNodes(key), restored.GetNodes(key); !slices.Equal(want, got) {
+			t.Fatalf("restored ring places %q on %v, want %v", key, got, want)
+		}
+	}
+}
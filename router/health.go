@@ -0,0 +1,298 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	pb "badies/proto/badiespb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// Defaults of the per-node circuit breakers
+const (
+	DefaultFailureThreshold    = 5
+	DefaultMaxErrorRate        = 0.5
+	DefaultBreakerCooldown     = 5 * time.Second
+	DefaultHealthCheckInterval = time.Second
+)
+
+const (
+	// errorRateAlpha and latencyAlpha weigh the latest call in the moving
+	// averages of a node's error rate and latency
+	errorRateAlpha = 0.1
+	latencyAlpha   = 0.2
+	// minCallsForErrorRate is how many calls the error rate needs before it
+	// can open a circuit
+	minCallsForErrorRate = 20
+	// healthCheckTimeout bounds a single health probe
+	healthCheckTimeout = 2 * time.Second
+)
+
+// CircuitState is the state of a node's circuit breaker
+type CircuitState int
+
+const (
+	// CircuitClosed lets calls through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails calls at once until a health probe succeeds
+	CircuitOpen
+	// CircuitHalfOpen is an open circuit whose node is being probed
+	CircuitHalfOpen
+)
+
+func (c CircuitState) String() string {
+	switch c {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	default:
+		return "half-open"
+	}
+}
+
+// NodeHealth is what the coordinator observed of a node's calls
+type NodeHealth struct {
+	Circuit             CircuitState
+	ErrorRate           float64       // moving average of failed calls, from 0 to 1
+	Latency             time.Duration // moving average of answered calls
+	ConsecutiveFailures int
+	LastError           string
+	OpenedAt            time.Time // when the circuit last opened
+}
+
+// nodeHealth tracks the calls to one node
+type nodeHealth struct {
+	mu sync.Mutex
+	NodeHealth
+	calls int // calls since the circuit last closed
+}
+
+// isNodeFailure reports whether err says the node itself is in trouble,
+// as opposed to a rejected request or a caller that gave up.
+// ResourceExhausted is a rejected request too, such as a message over the
+// size limit.
+func isNodeFailure(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Internal, codes.Unknown, codes.DataLoss:
+		return true
+	}
+	return false
+}
+
+// allow fails a call at once while the circuit of the node is open
+func (h *nodeHealth) allow(nodeID string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.Circuit == CircuitClosed {
+		return nil
+	}
+	return status.Errorf(codes.Unavailable, "node %s: circuit breaker open since %s: %s",
+		nodeID, h.OpenedAt.Format(time.TimeOnly), h.LastError)
+}
+
+// record accounts for a finished call. A latency of 0 is not counted, for
+// streams whose duration says nothing about the node.
+func (nm *NodeManager) record(nodeID string, h *nodeHealth, latency time.Duration, err error) {
+	if status.Code(err) == codes.Canceled {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls++
+	if !isNodeFailure(err) {
+		h.ConsecutiveFailures = 0
+		h.ErrorRate *= 1 - errorRateAlpha
+		if latency > 0 {
+			if h.Latency == 0 {
+				h.Latency = latency
+			} else {
+				h.Latency += time.Duration(latencyAlpha * float64(latency-h.Latency))
+			}
+		}
+		return
+	}
+
+	h.ConsecutiveFailures++
+	h.ErrorRate += errorRateAlpha * (1 - h.ErrorRate)
+	h.LastError = err.Error()
+	if h.Circuit != CircuitClosed {
+		return
+	}
+	if h.ConsecutiveFailures >= nm.FailureThreshold ||
+		(h.calls >= minCallsForErrorRate && h.ErrorRate >= nm.MaxErrorRate) {
+		h.Circuit = CircuitOpen
+		h.OpenedAt = time.Now()
+		log.Printf("Circuit breaker of node %s opened: %d consecutive failures, error rate %.2f, last error: %v",
+			nodeID, h.ConsecutiveFailures, h.ErrorRate, err)
+	}
+}
+
+// healthInterceptors record the outcome of every call to a node and fail
+// calls at once while its circuit is open. Health probes always go through.
+func (nm *NodeManager) healthInterceptors(nodeID string, h *nodeHealth) []grpc.DialOption {
+	unary := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if method == healthpb.Health_Check_FullMethodName {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		if err := h.allow(nodeID); err != nil {
+			return err
+		}
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		nm.record(nodeID, h, time.Since(start), err)
+		return err
+	}
+	stream := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if err := h.allow(nodeID); err != nil {
+			return nil, err
+		}
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			nm.record(nodeID, h, 0, err)
+			return nil, err
+		}
+		return &trackedStream{ClientStream: cs, done: func(err error) { nm.record(nodeID, h, 0, err) }}, nil
+	}
+	return []grpc.DialOption{grpc.WithChainUnaryInterceptor(unary), grpc.WithChainStreamInterceptor(stream)}
+}
+
+// trackedStream records how a stream from a node ended
+type trackedStream struct {
+	grpc.ClientStream
+	once sync.Once
+	done func(error)
+}
+
+func (s *trackedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			s.once.Do(func() { s.done(nil) })
+		} else {
+			s.once.Do(func() { s.done(err) })
+		}
+	}
+	return err
+}
+
+// Health returns what was observed of a node's calls
+func (nm *NodeManager) Health(nodeID string) (NodeHealth, bool) {
+	nm.mu.RLock()
+	node, exists := nm.Instances[nodeID]
+	nm.mu.RUnlock()
+	if !exists {
+		return NodeHealth{}, false
+	}
+	node.health.mu.Lock()
+	defer node.health.mu.Unlock()
+	return node.health.NodeHealth, true
+}
+
+// Available reports whether calls to a node go through, i.e. the node is
+// known and its circuit is closed
+func (nm *NodeManager) Available(nodeID string) bool {
+	h, ok := nm.Health(nodeID)
+	return ok && h.Circuit == CircuitClosed
+}
+
+// RunHealthChecks probes the nodes every HealthCheckInterval until ctx is
+// cancelled. A node reporting itself as not serving has its circuit opened;
+// an open circuit is probed once BreakerCooldown passed and closes when the
+// node answers as serving.
+func (nm *NodeManager) RunHealthChecks(ctx context.Context) {
+	ticker := time.NewTicker(nm.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			nm.mu.RLock()
+			nodes := make([]*Node, 0, len(nm.Instances))
+			for _, node := range nm.Instances {
+				nodes = append(nodes, node)
+			}
+			nm.mu.RUnlock()
+
+			var wg sync.WaitGroup
+			for _, node := range nodes {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					nm.checkHealth(ctx, node)
+				}()
+			}
+			wg.Wait()
+		}
+	}
+}
+
+// checkHealth probes one node with the grpc.health.v1 service
+func (nm *NodeManager) checkHealth(ctx context.Context, node *Node) {
+	h := node.health
+	h.mu.Lock()
+	if h.Circuit == CircuitOpen && time.Since(h.OpenedAt) >= nm.BreakerCooldown {
+		h.Circuit = CircuitHalfOpen
+	}
+	circuit := h.Circuit
+	h.mu.Unlock()
+	if circuit == CircuitOpen {
+		return
+	}
+
+	pctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	resp, err := healthpb.NewHealthClient(node.conn).Check(pctx,
+		&healthpb.HealthCheckRequest{Service: pb.StorageNode_ServiceDesc.ServiceName})
+	if ctx.Err() != nil {
+		return // shutting down
+	}
+	notServing := false
+	switch {
+	case status.Code(err) == codes.Unimplemented:
+		err = nil // the node predates health checks; answering is enough
+	case err == nil && resp.GetStatus() != healthpb.HealthCheckResponse_SERVING:
+		notServing = true
+		err = status.Errorf(codes.Unavailable, "node %s reports %s", node.ID, resp.GetStatus())
+	}
+
+	if circuit == CircuitClosed {
+		switch {
+		case notServing:
+			h.mu.Lock()
+			h.LastError = err.Error()
+			if h.Circuit == CircuitClosed {
+				h.Circuit = CircuitOpen
+				h.OpenedAt = time.Now()
+				log.Printf("Circuit breaker of node %s opened: %v", node.ID, err)
+			}
+			h.mu.Unlock()
+		case err != nil:
+			nm.record(node.ID, h, 0, err)
+		}
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err != nil {
+		h.Circuit = CircuitOpen
+		h.OpenedAt = time.Now()
+		h.LastError = err.Error()
+		return
+	}
+	h.Circuit = CircuitClosed
+	h.ConsecutiveFailures = 0
+	h.ErrorRate = 0
+	h.calls = 0
+	log.Printf("Circuit breaker of node %s closed, the node is healthy again", node.ID)
+}
@@ -0,0 +1,250 @@
+package router
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pb "badies/proto/badiespb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// fakeNode is a storage node whose Get fails with the code the test sets,
+// and whose health probes can be held until the test releases them
+type fakeNode struct {
+	pb.UnimplementedStorageNodeServer
+	healthpb.UnimplementedHealthServer
+
+	code   atomic.Uint32 // code Get fails with, OK to answer
+	calls  atomic.Int32  // Gets that reached the node
+	probes atomic.Int32  // health probes that reached the node
+
+	mu      sync.Mutex
+	serving bool
+	hold    chan struct{} // health probes wait for it while set
+}
+
+func (f *fakeNode) Get(ctx context.Context, req *pb.NodeGetRequest) (*pb.NodeGetResponse, error) {
+	f.calls.Add(1)
+	if code := codes.Code(f.code.Load()); code != codes.OK {
+		return nil, status.Error(code, "injected")
+	}
+	return &pb.NodeGetResponse{}, nil
+}
+
+func (f *fakeNode) Check(ctx context.Context, req *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	f.probes.Add(1)
+	f.mu.Lock()
+	hold, serving := f.hold, f.serving
+	f.mu.Unlock()
+	if hold != nil {
+		<-hold
+	}
+	if !serving {
+		return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_NOT_SERVING}, nil
+	}
+	return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+}
+
+func (f *fakeNode) setServing(serving bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.serving = serving
+}
+
+// newFakeNode serves a fakeNode on a loopback port and connects a
+// NodeManager to it as node1
+func newFakeNode(t *testing.T) (*NodeManager, *fakeNode) {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeNode{serving: true}
+	srv := grpc.NewServer()
+	pb.RegisterStorageNodeServer(srv, fake)
+	healthpb.RegisterHealthServer(srv, fake)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	nm := NewNodeManager()
+	nm.FailureThreshold = 3
+	if err := nm.AddNode("node1", lis.Addr().String()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { nm.Close() })
+	return nm, fake
+}
+
+// get calls Get on node1 and returns the error
+func get(t *testing.T, nm *NodeManager) error {
+	t.Helper()
+	client, err := nm.GetClient("node1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Get(context.Background(), &pb.NodeGetRequest{Key: []byte("k")})
+	return err
+}
+
+func circuitOf(t *testing.T, nm *NodeManager) CircuitState {
+	t.Helper()
+	h, ok := nm.Health("node1")
+	if !ok {
+		t.Fatal("node1 is not known")
+	}
+	return h.Circuit
+}
+
+func TestCircuitBreakerOpensHalfOpensAndCloses(t *testing.T) {
+	nm, fake := newFakeNode(t)
+	node := nm.Instances["node1"]
+	fake.code.Store(uint32(codes.Unavailable))
+	for i := 1; i <= nm.FailureThreshold; i++ {
+		if got := circuitOf(t, nm); got != CircuitClosed {
+			t.Fatalf("circuit is %s after %d failures, want closed below the threshold", got, i-1)
+		}
+		if err := get(t, nm); status.Code(err) != codes.Unavailable {
+			t.Fatalf("Get = %v, want the injected failure", err)
+		}
+	}
+	if got := circuitOf(t, nm); got != CircuitOpen {
+		t.Fatalf("circuit is %s after %d consecutive failures, want open", got, nm.FailureThreshold)
+	}
+
+	// An open circuit fails calls without reaching the node
+	calls := fake.calls.Load()
+	if err := get(t, nm); status.Code(err) != codes.Unavailable || !strings.Contains(err.Error(), "circuit breaker open") {
+		t.Errorf("Get on an open circuit = %v, want it refused", err)
+	}
+	if fake.calls.Load() != calls {
+		t.Errorf("a call reached the node through an open circuit")
+	}
+
+	// No probe before the cooldown passed
+	nm.BreakerCooldown = time.Hour
+	nm.checkHealth(context.Background(), node)
+	if probes := fake.probes.Load(); probes != 0 || circuitOf(t, nm) != CircuitOpen {
+		t.Fatalf("%d probes during the cooldown, circuit %s; want none and open", probes, circuitOf(t, nm))
+	}
+
+	// After the cooldown the circuit is half-open while the probe runs and
+	// still refuses calls; a failed probe opens it again
+	nm.BreakerCooldown = 0
+	fake.setServing(false)
+	hold := make(chan struct{})
+	fake.mu.Lock()
+	fake.hold = hold
+	fake.mu.Unlock()
+	probed := make(chan struct{})
+	go func() {
+		defer close(probed)
+		nm.checkHealth(context.Background(), node)
+	}()
+	for fake.probes.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if got := circuitOf(t, nm); got != CircuitHalfOpen {
+		t.Errorf("circuit is %s while the probe runs, want half-open", got)
+	}
+	if err := get(t, nm); err == nil {
+		t.Errorf("Get went through a half-open circuit")
+	}
+	close(hold)
+	<-probed
+	if got := circuitOf(t, nm); got != CircuitOpen {
+		t.Fatalf("circuit is %s after a failed probe, want open", got)
+	}
+
+	// A successful probe closes it and forgets the failures
+	fake.mu.Lock()
+	fake.hold = nil
+	fake.mu.Unlock()
+	fake.setServing(true)
+	fake.code.Store(uint32(codes.OK))
+	nm.checkHealth(context.Background(), node)
+	h, _ := nm.Health("node1")
+	if h.Circuit != CircuitClosed || h.ConsecutiveFailures != 0 || h.ErrorRate != 0 {
+		t.Fatalf("after a successful probe: %+v, want a closed circuit without failures", h)
+	}
+	if err := get(t, nm); err != nil {
+		t.Errorf("Get after the circuit closed = %v", err)
+	}
+}
+
+func TestCircuitBreakerOpensOnErrorRate(t *testing.T) {
+	nm, fake := newFakeNode(t)
+	nm.FailureThreshold = 1000
+	nm.MaxErrorRate = 0.3
+
+	// Every other call fails, so failures never run consecutively
+	for i := 1; i <= 2*minCallsForErrorRate; i++ {
+		code := codes.OK
+		if i%2 == 1 {
+			code = codes.DeadlineExceeded
+		}
+		fake.code.Store(uint32(code))
+		get(t, nm)
+
+		h, _ := nm.Health("node1")
+		switch {
+		case i < minCallsForErrorRate && h.Circuit != CircuitClosed:
+			t.Fatalf("circuit opened after %d calls at error rate %.2f, before %d calls", i, h.ErrorRate, minCallsForErrorRate)
+		case h.Circuit == CircuitOpen:
+			if h.ErrorRate < nm.MaxErrorRate {
+				t.Fatalf("circuit opened at error rate %.2f, below %.2f", h.ErrorRate, nm.MaxErrorRate)
+			}
+			return
+		}
+	}
+	h, _ := nm.Health("node1")
+	t.Fatalf("circuit is %s after %d calls at error rate %.2f, want open", h.Circuit, 2*minCallsForErrorRate, h.ErrorRate)
+}
+
+func TestCircuitBreakerIgnoresRejectedRequests(t *testing.T) {
+	nm, fake := newFakeNode(t)
+	for _, code := range []codes.Code{
+		codes.InvalidArgument, codes.NotFound, codes.FailedPrecondition,
+		codes.ResourceExhausted, codes.OutOfRange, codes.Aborted,
+	} {
+		fake.code.Store(uint32(code))
+		for range 2 * nm.FailureThreshold {
+			get(t, nm)
+		}
+		if h, _ := nm.Health("node1"); h.Circuit != CircuitClosed || h.ConsecutiveFailures != 0 {
+			t.Errorf("%s answers counted as node failures: %+v", code, h)
+		}
+	}
+}
+
+func TestHealthProbesBypassOpenCircuit(t *testing.T) {
+	nm, fake := newFakeNode(t)
+	fake.code.Store(uint32(codes.Unavailable))
+	for range nm.FailureThreshold {
+		get(t, nm)
+	}
+	if got := circuitOf(t, nm); got != CircuitOpen {
+		t.Fatalf("circuit is %s, want open", got)
+	}
+
+	// The probe goes over the same connection as the refused calls
+	health := healthpb.NewHealthClient(nm.Instances["node1"].conn)
+	resp, err := health.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	if err != nil || resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		t.Fatalf("health probe through an open circuit = %v, %v, want SERVING", resp, err)
+	}
+	if fake.probes.Load() != 1 {
+		t.Errorf("the node saw %d probes, want 1", fake.probes.Load())
+	}
+	if got := circuitOf(t, nm); got != CircuitOpen {
+		t.Errorf("a probe outside checkHealth changed the circuit to %s", got)
+	}
+}
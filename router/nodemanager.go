@@ -3,31 +3,64 @@ package router
 import (
 	"fmt"
 	"log"
+	"slices"
 	"sync"
+	"time"
 
-	"github.com/syndtr/goleveldb/leveldb"
-	"github.com/syndtr/goleveldb/leveldb/opt"
+	pb "badies/proto/badiespb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
+// Node is a connection to a remote storage node process
+type Node struct {
+	ID     string
+	Addr   string
+	Client pb.StorageNodeClient
+	conn   *grpc.ClientConn
+	health *nodeHealth
+}
+
 type NodeManager struct {
 	mu        sync.RWMutex
-	Instances map[string]*leveldb.DB
+	Instances map[string]*Node
+
+	// DialOptions apply to every node connection, ahead of the options
+	// given for a single node
+	DialOptions []grpc.DialOption
+
+	// FailureThreshold is the number of consecutive failed calls that opens
+	// a node's circuit breaker
+	FailureThreshold int
+	// MaxErrorRate opens the circuit breaker once the moving average of
+	// failed calls reaches it
+	MaxErrorRate float64
+	// BreakerCooldown is how long a circuit stays open before the node is
+	// probed again
+	BreakerCooldown time.Duration
+	// HealthCheckInterval is the time between health probes
+	HealthCheckInterval time.Duration
 }
 
 // NewNodeManager creates a new instance of NodeManager
 func NewNodeManager() *NodeManager {
 	return &NodeManager{
-		Instances: make(map[string]*leveldb.DB),
+		Instances:           make(map[string]*Node),
+		FailureThreshold:    DefaultFailureThreshold,
+		MaxErrorRate:        DefaultMaxErrorRate,
+		BreakerCooldown:     DefaultBreakerCooldown,
+		HealthCheckInterval: DefaultHealthCheckInterval,
 	}
 }
 
-// AddNode adds a new node with the specified nodeID and database path
-func (nm *NodeManager) AddNode(nodeID string, path string) error {
-	return nm.AddNodeWithOptions(nodeID, path, nil)
+// AddNode adds a new node with the specified nodeID and StorageNode address
+func (nm *NodeManager) AddNode(nodeID string, addr string) error {
+	return nm.AddNodeWithOptions(nodeID, addr)
 }
 
-// AddNodeWithOptions adds a new node with custom LevelDB options
-func (nm *NodeManager) AddNodeWithOptions(nodeID string, path string, options *opt.Options) error {
+// AddNodeWithOptions adds a new node with custom gRPC dial options
+func (nm *NodeManager) AddNodeWithOptions(nodeID string, addr string, options ...grpc.DialOption) error {
 	nm.mu.Lock()
 	defer nm.mu.Unlock()
 
@@ -36,42 +69,74 @@ func (nm *NodeManager) AddNodeWithOptions(nodeID string, path string, options *o
 		return fmt.Errorf("node %s already exists", nodeID)
 	}
 
-	// Open the database
-	db, err := leveldb.OpenFile(path, options)
+	node, err := nm.dialNode(nodeID, addr, slices.Concat(nm.DialOptions, options))
 	if err != nil {
-		return fmt.Errorf("failed to open DB for node %s at path %s: %v", nodeID, path, err)
+		return err
 	}
 
-	nm.Instances[nodeID] = db
-	log.Printf("Successfully added node %s with database at %s", nodeID, path)
+	nm.Instances[nodeID] = node
+	log.Printf("Successfully added node %s at %s", nodeID, addr)
 	return nil
 }
 
-// GetDB retrieves the database instance for the specified nodeID
-func (nm *NodeManager) GetDB(nodeID string) (*leveldb.DB, error) {
+// dialNode creates the client connection for a node, tracking the health of
+// its calls. Connections are established lazily, so a node that is down does
+// not block startup.
+func (nm *NodeManager) dialNode(nodeID string, addr string, options []grpc.DialOption) (*Node, error) {
+	health := &nodeHealth{}
+	dialOpts := slices.Concat([]grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, options, nm.healthInterceptors(nodeID, health))
+	conn, err := grpc.NewClient(addr, dialOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for node %s at %s: %v", nodeID, addr, err)
+	}
+	return &Node{
+		ID:     nodeID,
+		Addr:   addr,
+		Client: pb.NewStorageNodeClient(conn),
+		conn:   conn,
+		health: health,
+	}, nil
+}
+
+// GetClient retrieves the StorageNode client for the specified nodeID
+func (nm *NodeManager) GetClient(nodeID string) (pb.StorageNodeClient, error) {
 	nm.mu.RLock()
 	defer nm.mu.RUnlock()
 
-	db, exists := nm.Instances[nodeID]
+	node, exists := nm.Instances[nodeID]
 	if !exists {
 		return nil, fmt.Errorf("node %s not found", nodeID)
 	}
-	return db, nil
+	return node.Client, nil
+}
+
+// GetAddr returns the address the node is served on
+func (nm *NodeManager) GetAddr(nodeID string) (string, error) {
+	nm.mu.RLock()
+	defer nm.mu.RUnlock()
+
+	node, exists := nm.Instances[nodeID]
+	if !exists {
+		return "", fmt.Errorf("node %s not found", nodeID)
+	}
+	return node.Addr, nil
 }
 
-// RemoveNode removes a node and closes its database connection
+// RemoveNode removes a node and closes its connection
 func (nm *NodeManager) RemoveNode(nodeID string) error {
 	nm.mu.Lock()
 	defer nm.mu.Unlock()
 
-	db, exists := nm.Instances[nodeID]
+	node, exists := nm.Instances[nodeID]
 	if !exists {
 		return fmt.Errorf("node %s not found", nodeID)
 	}
 
-	// Close the database
-	if err := db.Close(); err != nil {
-		return fmt.Errorf("failed to close database for node %s: %v", nodeID, err)
+	// Close the connection
+	if err := node.conn.Close(); err != nil {
+		return fmt.Errorf("failed to close connection for node %s: %v", nodeID, err)
 	}
 
 	// Remove from instances map
@@ -109,43 +174,43 @@ func (nm *NodeManager) NodeCount() int {
 	return len(nm.Instances)
 }
 
-// Close closes all database connections and cleans up resources
+// Close closes all node connections and cleans up resources
 func (nm *NodeManager) Close() error {
 	nm.mu.Lock()
 	defer nm.mu.Unlock()
 
 	var errs []error
-	for nodeID, db := range nm.Instances {
-		if err := db.Close(); err != nil {
-			errs = append(errs, fmt.Errorf("failed to close database for node %s: %v", nodeID, err))
+	for nodeID, node := range nm.Instances {
+		if err := node.conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close connection for node %s: %v", nodeID, err))
 		} else {
-			log.Printf("Successfully closed database for node %s", nodeID)
+			log.Printf("Successfully closed connection for node %s", nodeID)
 		}
 	}
 
 	// Clear the instances map
-	nm.Instances = make(map[string]*leveldb.DB)
+	nm.Instances = make(map[string]*Node)
 
 	if len(errs) > 0 {
-		return fmt.Errorf("errors occurred while closing databases: %v", errs)
+		return fmt.Errorf("errors occurred while closing connections: %v", errs)
 	}
 
-	log.Println("All node databases closed successfully")
+	log.Println("All node connections closed successfully")
 	return nil
 }
 
-// CloseNode closes the database for a specific node without removing it from the manager
+// CloseNode closes the connection for a specific node and forgets it
 func (nm *NodeManager) CloseNode(nodeID string) error {
 	nm.mu.Lock()
 	defer nm.mu.Unlock()
 
-	db, exists := nm.Instances[nodeID]
+	node, exists := nm.Instances[nodeID]
 	if !exists {
 		return fmt.Errorf("node %s not found", nodeID)
 	}
 
-	if err := db.Close(); err != nil {
-		return fmt.Errorf("failed to close database for node %s: %v", nodeID, err)
+	if err := node.conn.Close(); err != nil {
+		return fmt.Errorf("failed to close connection for node %s: %v", nodeID, err)
 	}
 
 	delete(nm.Instances, nodeID)
@@ -153,25 +218,25 @@ func (nm *NodeManager) CloseNode(nodeID string) error {
 	return nil
 }
 
-// ReplaceNode replaces an existing node's database with a new one
-func (nm *NodeManager) ReplaceNode(nodeID string, newPath string) error {
+// ReplaceNode points an existing node ID at a new StorageNode address
+func (nm *NodeManager) ReplaceNode(nodeID string, newAddr string) error {
 	nm.mu.Lock()
 	defer nm.mu.Unlock()
 
-	// Close existing database if it exists
-	if existingDB, exists := nm.Instances[nodeID]; exists {
-		if err := existingDB.Close(); err != nil {
-			log.Printf("Warning: failed to close existing database for node %s: %v", nodeID, err)
+	// Close existing connection if it exists
+	if existing, exists := nm.Instances[nodeID]; exists {
+		if err := existing.conn.Close(); err != nil {
+			log.Printf("Warning: failed to close existing connection for node %s: %v", nodeID, err)
 		}
 	}
 
-	// Open new database
-	db, err := leveldb.OpenFile(newPath, nil)
+	// Connect to the new address
+	node, err := nm.dialNode(nodeID, newAddr, nm.DialOptions)
 	if err != nil {
-		return fmt.Errorf("failed to open new DB for node %s at path %s: %v", nodeID, newPath, err)
+		return err
 	}
 
-	nm.Instances[nodeID] = db
-	log.Printf("Successfully replaced database for node %s with new path %s", nodeID, newPath)
+	nm.Instances[nodeID] = node
+	log.Printf("Successfully replaced node %s with new address %s", nodeID, newAddr)
 	return nil
 }
@@ -0,0 +1,383 @@
+package router
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"strconv"
+
+	"github.com/cespare/xxhash/v2"
+)
+
+// Partitioner names accepted in configuration
+const (
+	// Consistent places weight times VNodes hashed tokens per node on the
+	// circle; a key belongs to the nodes of the next tokens clockwise
+	Consistent = "consistent"
+	// Jump splits the circle into Partitions equal arcs and assigns them to
+	// nodes with jump consistent hashing. It moves the fewest keys when the
+	// last node joins or leaves, but removing any other node reshuffles.
+	Jump = "jump"
+	// Rendezvous splits the circle into Partitions equal arcs and gives each
+	// to the nodes with the highest weighted random score for it
+	Rendezvous = "rendezvous"
+	// Range keeps keys in byte order: a node joining splits the widest
+	// ranges, so keys next to each other share nodes and range scans touch
+	// few of them
+	Range = "range"
+)
+
+// Defaults of the partitioner configuration
+const (
+	DefaultPartitioner = Consistent
+	DefaultPartitions  = 256
+	// maxPartitions bounds the fixed partitions of jump and rendezvous
+	maxPartitions = 1 << 16
+)
+
+// PartitionerConfig selects a partitioner. Fields a partitioner does not use
+// are zero in the configuration it reports.
+type PartitionerConfig struct {
+	Name       string // Consistent, Jump, Rendezvous or Range; Consistent if empty
+	Hash       string // see LookupHash; Range always uses OrderedHash
+	VNodes     int    // tokens per unit of weight, for Consistent and Range
+	Partitions int    // fixed partitions, for Jump and Rendezvous
+}
+
+func (c PartitionerConfig) String() string {
+	switch c.Name {
+	case Consistent:
+		return fmt.Sprintf("%s (%s, %d vnodes)", c.Name, c.Hash, c.VNodes)
+	case Range:
+		return fmt.Sprintf("%s (%d vnodes)", c.Name, c.VNodes)
+	default:
+		return fmt.Sprintf("%s (%s, %d partitions)", c.Name, c.Hash, c.Partitions)
+	}
+}
+
+// Partitioner decides where keys live. It maps every key to a position on
+// the hash circle and splits the circle into partitions, each replicated on
+// up to replicationFactor distinct nodes. A partitioner holds nothing but its
+// configuration, so copies of a ring share it.
+type Partitioner interface {
+	Config() PartitionerConfig
+	Position(key []byte) uint32
+	// Place returns the partitions for nodes given in the order they joined,
+	// sorted by End
+	Place(nodes []PlacedNode, replicationFactor int) []Partition
+}
+
+// TokenAssigner is a partitioner that places nodes at tokens chosen when
+// they join. The tokens are kept in the ring's topology, so placement
+// survives a change in the way tokens are chosen.
+type TokenAssigner interface {
+	Partitioner
+	// AssignTokens returns the sorted tokens of a node joining nodes
+	AssignTokens(nodeID string, weight int, nodes []PlacedNode) []uint32
+}
+
+// PlacedNode is a ring member as a partitioner sees it
+type PlacedNode struct {
+	ID     string
+	Weight int
+	Tokens []uint32 // from AssignTokens, nil for other partitioners
+}
+
+// Partition is the arc of the circle that ends at End and starts after the
+// End of the previous partition, with its replicas, primary first
+type Partition struct {
+	End    uint32
+	Owners []string
+}
+
+// NewPartitioner returns the partitioner cfg selects, filling in defaults
+func NewPartitioner(cfg PartitionerConfig) (Partitioner, error) {
+	if cfg.Name == "" {
+		cfg.Name = DefaultPartitioner
+	}
+	switch cfg.Name {
+	case Consistent, Range:
+		if cfg.VNodes < 1 {
+			return nil, fmt.Errorf("the %s partitioner needs at least 1 vnode per weight, not %d", cfg.Name, cfg.VNodes)
+		}
+		cfg.Partitions = 0
+	case Jump, Rendezvous:
+		if cfg.Partitions == 0 {
+			cfg.Partitions = DefaultPartitions
+		}
+		if cfg.Partitions < 1 || cfg.Partitions > maxPartitions {
+			return nil, fmt.Errorf("the %s partitioner needs 1 to %d partitions, not %d", cfg.Name, maxPartitions, cfg.Partitions)
+		}
+		cfg.VNodes = 0
+	default:
+		return nil, fmt.Errorf("unknown partitioner %q, want %s, %s, %s or %s", cfg.Name, Consistent, Jump, Rendezvous, Range)
+	}
+
+	if cfg.Name == Range {
+		if cfg.Hash != "" && cfg.Hash != OrderedHash {
+			return nil, fmt.Errorf("the %s partitioner keeps keys in order and takes no hash, not %q", Range, cfg.Hash)
+		}
+		cfg.Hash = OrderedHash
+	} else if cfg.Hash == "" {
+		cfg.Hash = DefaultHash
+	} else if cfg.Hash == OrderedHash {
+		return nil, fmt.Errorf("the %s hash keeps keys in order and only suits the %s partitioner", OrderedHash, Range)
+	}
+	hash, err := LookupHash(cfg.Hash)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Name {
+	case Consistent:
+		return &consistentPartitioner{cfg: cfg, hash: hash}, nil
+	case Range:
+		return &rangePartitioner{cfg: cfg, hash: hash}, nil
+	case Jump:
+		return &jumpPartitioner{cfg: cfg, hash: hash}, nil
+	default:
+		return &rendezvousPartitioner{cfg: cfg, hash: hash}, nil
+	}
+}
+
+type consistentPartitioner struct {
+	cfg  PartitionerConfig
+	hash HashFunc
+}
+
+func (p *consistentPartitioner) Config() PartitionerConfig  { return p.cfg }
+func (p *consistentPartitioner) Position(key []byte) uint32 { return p.hash(key) }
+
+// AssignTokens hashes the names of the node's virtual nodes, "node1#0",
+// "node1#1" and so on
+func (p *consistentPartitioner) AssignTokens(nodeID string, weight int, nodes []PlacedNode) []uint32 {
+	tokens := make([]uint32, 0, p.cfg.VNodes*weight)
+	for i := 0; i < p.cfg.VNodes*weight; i++ {
+		tokens = append(tokens, p.hash([]byte(nodeID+"#"+strconv.Itoa(i))))
+	}
+	slices.Sort(tokens)
+	return slices.Compact(tokens)
+}
+
+func (p *consistentPartitioner) Place(nodes []PlacedNode, replicationFactor int) []Partition {
+	return walkPlacement(nodes, replicationFactor)
+}
+
+type rangePartitioner struct {
+	cfg  PartitionerConfig
+	hash HashFunc
+}
+
+func (p *rangePartitioner) Config() PartitionerConfig  { return p.cfg }
+func (p *rangePartitioner) Position(key []byte) uint32 { return p.hash(key) }
+
+// AssignTokens claims the node's share of the circle by weight, one piece
+// per token: each piece is cut from the widest range of the node holding the
+// most per unit of weight, so the load evens out and keys only move to the
+// new node. The first node spreads its tokens evenly.
+func (p *rangePartitioner) AssignTokens(nodeID string, weight int, nodes []PlacedNode) []uint32 {
+	count := p.cfg.VNodes * weight
+	tokens := make([]uint32, 0, count)
+	if len(nodes) == 0 {
+		for i := 0; i < count; i++ {
+			tokens = append(tokens, uint32((uint64(i+1)<<32)/uint64(count)-1))
+		}
+		return slices.Compact(tokens)
+	}
+
+	holder := make(map[uint32]int) // token -> index into nodes, len(nodes) for the new node
+	var circle []uint32
+	totalWeight := weight
+	for i, node := range nodes {
+		totalWeight += node.Weight
+		for _, token := range node.Tokens {
+			if _, taken := holder[token]; !taken {
+				holder[token] = i
+				circle = append(circle, token)
+			}
+		}
+	}
+	slices.Sort(circle)
+	rangeAt := func(j int) HashRange {
+		return HashRange{Start: circle[(j+len(circle)-1)%len(circle)], End: circle[j]}
+	}
+	held := make([]uint64, len(nodes))
+	for j, end := range circle {
+		held[holder[end]] += rangeAt(j).Len()
+	}
+
+	share := (uint64(1) << 32) * uint64(weight) / uint64(totalWeight)
+	for i := 0; i < count; i++ {
+		richest := 0
+		for n := range nodes {
+			if held[n]*uint64(nodes[richest].Weight) > held[richest]*uint64(nodes[n].Weight) {
+				richest = n
+			}
+		}
+		widest, at := uint64(0), 0
+		for j, end := range circle {
+			if holder[end] == richest && rangeAt(j).Len() > widest {
+				widest, at = rangeAt(j).Len(), j
+			}
+		}
+		if widest < 2 {
+			break // nothing left to cut
+		}
+		cut := min(max(1, share/uint64(count-i)), widest-1)
+		share -= min(share, cut)
+		token := rangeAt(at).Start + uint32(cut)
+		held[richest] -= cut
+		holder[token] = len(nodes)
+		tokens = append(tokens, token)
+		pos, _ := slices.BinarySearch(circle, token)
+		circle = slices.Insert(circle, pos, token)
+	}
+	slices.Sort(tokens)
+	return tokens
+}
+
+func (p *rangePartitioner) Place(nodes []PlacedNode, replicationFactor int) []Partition {
+	return walkPlacement(nodes, replicationFactor)
+}
+
+// walkPlacement makes a partition of every token and replicates it on the
+// distinct nodes of the tokens that follow clockwise. Should two nodes hold
+// the same token, the one that joined first keeps it.
+func walkPlacement(nodes []PlacedNode, replicationFactor int) []Partition {
+	owner := make(map[uint32]string)
+	var tokens []uint32
+	for _, node := range nodes {
+		for _, token := range node.Tokens {
+			if _, taken := owner[token]; !taken {
+				owner[token] = node.ID
+				tokens = append(tokens, token)
+			}
+		}
+	}
+	slices.Sort(tokens)
+
+	want := min(replicationFactor, len(nodes))
+	parts := make([]Partition, len(tokens))
+	for i, token := range tokens {
+		owners := make([]string, 0, want)
+		for j := 0; j < len(tokens) && len(owners) < want; j++ {
+			node := owner[tokens[(i+j)%len(tokens)]]
+			if !slices.Contains(owners, node) {
+				owners = append(owners, node)
+			}
+		}
+		parts[i] = Partition{End: token, Owners: owners}
+	}
+	return parts
+}
+
+type jumpPartitioner struct {
+	cfg  PartitionerConfig
+	hash HashFunc
+}
+
+func (p *jumpPartitioner) Config() PartitionerConfig  { return p.cfg }
+func (p *jumpPartitioner) Position(key []byte) uint32 { return p.hash(key) }
+
+// Place gives every node one bucket per unit of weight, in join order, and
+// jump hashes each partition into them. Further replicas come from hashing
+// the partition again until enough distinct nodes were found.
+func (p *jumpPartitioner) Place(nodes []PlacedNode, replicationFactor int) []Partition {
+	var buckets []string
+	for _, node := range nodes {
+		for i := 0; i < node.Weight; i++ {
+			buckets = append(buckets, node.ID)
+		}
+	}
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	want := min(replicationFactor, len(nodes))
+	parts := evenPartitions(p.cfg.Partitions)
+	for i := range parts {
+		owners := make([]string, 0, want)
+		for seed := uint64(i); len(owners) < want; {
+			seed = splitmix64(seed)
+			node := buckets[jumpHash(seed, len(buckets))]
+			if !slices.Contains(owners, node) {
+				owners = append(owners, node)
+			}
+		}
+		parts[i].Owners = owners
+	}
+	return parts
+}
+
+// jumpHash is the jump consistent hash of Lamping and Veach: it maps key to
+// one of n buckets and moves only 1/(n+1) of the keys when a bucket is
+// appended
+func jumpHash(key uint64, n int) int {
+	var b, j int64 = -1, 0
+	for j < int64(n) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(b)
+}
+
+type rendezvousPartitioner struct {
+	cfg  PartitionerConfig
+	hash HashFunc
+}
+
+func (p *rendezvousPartitioner) Config() PartitionerConfig  { return p.cfg }
+func (p *rendezvousPartitioner) Position(key []byte) uint32 { return p.hash(key) }
+
+// Place ranks the nodes for every partition by the weighted score
+// -weight/ln(u), where u is a uniform hash of the partition and the node,
+// and replicates the partition on the highest ranked. A node joining or
+// leaving only changes the partitions it ranks high for.
+func (p *rendezvousPartitioner) Place(nodes []PlacedNode, replicationFactor int) []Partition {
+	if len(nodes) == 0 {
+		return nil
+	}
+	seeds := make([]uint64, len(nodes))
+	for i, node := range nodes {
+		seeds[i] = xxhash.Sum64String(node.ID)
+	}
+
+	want := min(replicationFactor, len(nodes))
+	parts := evenPartitions(p.cfg.Partitions)
+	scores := make([]float64, len(nodes))
+	ranked := make([]int, len(nodes))
+	for i := range parts {
+		partition := splitmix64(uint64(i))
+		for j, node := range nodes {
+			u := (float64(splitmix64(partition^seeds[j])>>11) + 0.5) / (1 << 53)
+			scores[j] = -float64(node.Weight) / math.Log(u)
+			ranked[j] = j
+		}
+		sort.SliceStable(ranked, func(a, b int) bool { return scores[ranked[a]] > scores[ranked[b]] })
+		owners := make([]string, want)
+		for j := range owners {
+			owners[j] = nodes[ranked[j]].ID
+		}
+		parts[i].Owners = owners
+	}
+	return parts
+}
+
+// evenPartitions splits the circle into n arcs of equal length
+func evenPartitions(n int) []Partition {
+	parts := make([]Partition, n)
+	for i := range parts {
+		parts[i].End = uint32((uint64(i+1)<<32+uint64(n)-1)/uint64(n) - 1)
+	}
+	return parts
+}
+
+// splitmix64 scrambles x into a well distributed 64-bit value
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
@@ -0,0 +1,283 @@
+package router
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// testPartitioners returns one partitioner of every kind, with enough
+// tokens or partitions to balance well
+func testPartitioners(t *testing.T) []Partitioner {
+	t.Helper()
+	var partitioners []Partitioner
+	for _, cfg := range []PartitionerConfig{
+		{Name: Consistent, Hash: "crc32", VNodes: 200},
+		{Name: Consistent, Hash: "fnv", VNodes: 200},
+		{Name: Consistent, Hash: "murmur3", VNodes: 200},
+		{Name: Consistent, Hash: "xxhash", VNodes: 200},
+		{Name: Jump, Hash: "xxhash", Partitions: 4096},
+		{Name: Rendezvous, Hash: "murmur3", Partitions: 4096},
+		{Name: Range, VNodes: 16},
+	} {
+		p, err := NewPartitioner(cfg)
+		if err != nil {
+			t.Fatalf("NewPartitioner(%+v): %v", cfg, err)
+		}
+		partitioners = append(partitioners, p)
+	}
+	return partitioners
+}
+
+// uniformKeys returns random binary keys, spread evenly in byte order too
+func uniformKeys(rng *rand.Rand, n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		key := make([]byte, 8)
+		for j := range key {
+			key[j] = byte(rng.UintN(256))
+		}
+		keys[i] = string(key)
+	}
+	return keys
+}
+
+func TestNewPartitionerConfig(t *testing.T) {
+	for _, tc := range []struct {
+		in   PartitionerConfig
+		want PartitionerConfig
+	}{
+		{PartitionerConfig{VNodes: 3}, PartitionerConfig{Name: Consistent, Hash: DefaultHash, VNodes: 3}},
+		{PartitionerConfig{Name: Jump, VNodes: 3}, PartitionerConfig{Name: Jump, Hash: DefaultHash, Partitions: DefaultPartitions}},
+		{PartitionerConfig{Name: Rendezvous, Hash: "fnv", Partitions: 64}, PartitionerConfig{Name: Rendezvous, Hash: "fnv", Partitions: 64}},
+		{PartitionerConfig{Name: Range, VNodes: 4, Partitions: 64}, PartitionerConfig{Name: Range, Hash: OrderedHash, VNodes: 4}},
+	} {
+		p, err := NewPartitioner(tc.in)
+		if err != nil {
+			t.Fatalf("NewPartitioner(%+v): %v", tc.in, err)
+		}
+		if got := p.Config(); got != tc.want {
+			t.Errorf("NewPartitioner(%+v).Config() = %+v, want %+v", tc.in, got, tc.want)
+		}
+	}
+
+	for _, cfg := range []PartitionerConfig{
+		{Name: "modulo", VNodes: 3},
+		{Name: Consistent},
+		{Name: Consistent, Hash: "md5", VNodes: 3},
+		{Name: Jump, Hash: OrderedHash},
+		{Name: Jump, Partitions: -1},
+		{Name: Rendezvous, Partitions: maxPartitions + 1},
+		{Name: Range, Hash: "xxhash", VNodes: 3},
+	} {
+		if _, err := NewPartitioner(cfg); err == nil {
+			t.Errorf("NewPartitioner(%+v) accepted an invalid configuration", cfg)
+		}
+	}
+}
+
+func TestPartitionersPlaceDistinctReplicas(t *testing.T) {
+	rng := rand.New(rand.NewPCG(10, 0))
+	for _, p := range testPartitioners(t) {
+		t.Run(p.Config().String(), func(t *testing.T) {
+			for trial := 0; trial < 10; trial++ {
+				n, rf := 1+rng.IntN(8), 1+rng.IntN(4)
+				ring := NewPartitionedRing(p, rf)
+				for i := 0; i < n; i++ {
+					ring.AddNodeWithWeight(fmt.Sprintf("node%d", i+1), 1+rng.IntN(3))
+				}
+				for i, part := range ring.parts {
+					if i > 0 && part.End <= ring.parts[i-1].End {
+						t.Fatalf("partition %d ends at %d, after %d", i, part.End, ring.parts[i-1].End)
+					}
+					if len(part.Owners) != min(rf, n) {
+						t.Fatalf("n=%d rf=%d: partition %d is replicated on %v", n, rf, i, part.Owners)
+					}
+					if len(slices.Compact(slices.Sorted(slices.Values(part.Owners)))) != len(part.Owners) {
+						t.Fatalf("partition %d is replicated on %v, which repeats a node", i, part.Owners)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestPartitionerBalance(t *testing.T) {
+	const keyCount, tolerance = 50000, 0.15
+	weights := []int{1, 2, 1, 3, 1}
+	keys := uniformKeys(rand.New(rand.NewPCG(11, 0)), keyCount)
+	for _, p := range testPartitioners(t) {
+		t.Run(p.Config().String(), func(t *testing.T) {
+			if cfg := p.Config(); cfg.Name == Consistent && (cfg.Hash == "crc32" || cfg.Hash == "fnv") {
+				t.Skip("CRC32 and FNV-1a cluster similar virtual node names, see TestBalance")
+			}
+			ring := NewPartitionedRing(p, 3)
+			total := 0
+			for i, weight := range weights {
+				ring.AddNodeWithWeight(fmt.Sprintf("node%d", i+1), weight)
+				total += weight
+			}
+			primaries := make(map[string]int)
+			for _, key := range keys {
+				primaries[ring.GetNodes(key)[0]]++
+			}
+			for i, weight := range weights {
+				node := fmt.Sprintf("node%d", i+1)
+				want := float64(keyCount) * float64(weight) / float64(total)
+				if got := float64(primaries[node]); got < want*(1-tolerance) || got > want*(1+tolerance) {
+					t.Errorf("%s (weight %d) is primary for %.0f keys, want %.0f ± %.0f%%",
+						node, weight, got, want, tolerance*100)
+				}
+			}
+		})
+	}
+}
+
+func TestPartitionerJoinMovesKeysToNewNode(t *testing.T) {
+	rng := rand.New(rand.NewPCG(12, 0))
+	keys := uniformKeys(rng, 5000)
+	for _, p := range testPartitioners(t) {
+		t.Run(p.Config().String(), func(t *testing.T) {
+			for trial := 0; trial < 5; trial++ {
+				n, rf := 3+rng.IntN(6), 1+rng.IntN(3)
+				before := NewPartitionedRing(p, rf)
+				for i := 0; i < n; i++ {
+					before.AddNode(fmt.Sprintf("node%d", i+1))
+				}
+				after := before.Clone()
+				after.AddNode("new")
+
+				moved := 0
+				for _, key := range keys {
+					was, is := before.GetNodes(key), after.GetNodes(key)
+					if slices.Equal(was, is) {
+						continue
+					}
+					moved++
+					if !slices.Contains(is, "new") {
+						t.Fatalf("%q moved from %v to %v without the new node", key, was, is)
+					}
+					// Jump may retry into the new bucket more than once, so
+					// only the others are held to losing a single replica
+					lost := 0
+					for _, node := range was {
+						if !slices.Contains(is, node) {
+							lost++
+						}
+					}
+					if p.Config().Name != Jump && lost != 1 {
+						t.Fatalf("%q moved from %v to %v, more than the new node changed", key, was, is)
+					}
+				}
+				expected := float64(len(keys)) * float64(rf) / float64(n+1)
+				if float64(moved) > 1.5*expected {
+					t.Errorf("n=%d rf=%d: %d of %d keys moved, expected about %.0f", n, rf, moved, len(keys), expected)
+				}
+			}
+		})
+	}
+}
+
+func TestPartitionerLeaveMovesOnlyItsKeys(t *testing.T) {
+	rng := rand.New(rand.NewPCG(13, 0))
+	keys := uniformKeys(rng, 5000)
+	for _, p := range testPartitioners(t) {
+		t.Run(p.Config().String(), func(t *testing.T) {
+			for trial := 0; trial < 5; trial++ {
+				n, rf := 3+rng.IntN(6), 1+rng.IntN(3)
+				before := NewPartitionedRing(p, rf)
+				for i := 0; i < n; i++ {
+					before.AddNodeWithWeight(fmt.Sprintf("node%d", i+1), 1+rng.IntN(3))
+				}
+				// Jump only keeps its promise for the node that joined last
+				removed := fmt.Sprintf("node%d", n)
+				if p.Config().Name != Jump {
+					removed = fmt.Sprintf("node%d", 1+rng.IntN(n))
+				}
+				after := before.Clone()
+				after.RemoveNode(removed)
+
+				for _, key := range keys {
+					was, is := before.GetNodes(key), after.GetNodes(key)
+					if !slices.Contains(was, removed) && !slices.Equal(was, is) {
+						t.Fatalf("%q moved from %v to %v, but did not live on %s", key, was, is, removed)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRangePartitionerKeepsKeyOrder(t *testing.T) {
+	p, err := NewPartitioner(PartitionerConfig{Name: Range, VNodes: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring := NewPartitionedRing(p, 2)
+	for i := 1; i <= 5; i++ {
+		ring.AddNode(fmt.Sprintf("node%d", i))
+	}
+	keys := uniformKeys(rand.New(rand.NewPCG(14, 0)), 5000)
+	slices.Sort(keys)
+
+	// Sorted keys walk the partitions in order, so the primary changes at
+	// most once per partition
+	changes := 0
+	for i := 1; i < len(keys); i++ {
+		if ring.Position(keys[i]) < ring.Position(keys[i-1]) {
+			t.Fatalf("%q sorts after %q but lies before it on the circle", keys[i], keys[i-1])
+		}
+		if ring.GetNodes(keys[i])[0] != ring.GetNodes(keys[i-1])[0] {
+			changes++
+		}
+	}
+	if changes > len(ring.parts) {
+		t.Errorf("the primary changed %d times along sorted keys, more than the %d partitions", changes, len(ring.parts))
+	}
+}
+
+func TestRestoreHashRingReproducesEveryPartitioner(t *testing.T) {
+	rng := rand.New(rand.NewPCG(15, 0))
+	keys := uniformKeys(rng, 2000)
+	for _, p := range testPartitioners(t) {
+		t.Run(p.Config().String(), func(t *testing.T) {
+			ring := NewPartitionedRing(p, 3)
+			for i := 0; i < 6; i++ {
+				ring.AddNodeWithWeight(fmt.Sprintf("node%d", i+1), 1+rng.IntN(3))
+			}
+			ring.RemoveNode("node2")
+			restored, err := RestoreHashRing(ring.Topology(), 3)
+			if err != nil {
+				t.Fatalf("RestoreHashRing: %v", err)
+			}
+			if restored.Partitioner().Config() != p.Config() {
+				t.Errorf("restored partitioner %s, want %s", restored.Partitioner().Config(), p.Config())
+			}
+			for _, key := range keys {
+				if want, got := ring.GetNodes(key), restored.GetNodes(key); !slices.Equal(want, got) {
+					t.Fatalf("restored ring places %q on %v, want %v", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreHashRingDefaultsToConsistentCRC32(t *testing.T) {
+	// Rings stored before partitioners could be chosen only carry vnodes
+	ring := randomRing(rand.New(rand.NewPCG(16, 0)), 20, 3, 5, 2)
+	topology := ring.Topology()
+	topology.Partitioner = PartitionerConfig{VNodes: 20}
+	restored, err := RestoreHashRing(topology, 3)
+	if err != nil {
+		t.Fatalf("RestoreHashRing: %v", err)
+	}
+	if got := restored.Partitioner().Config(); got != ring.Partitioner().Config() {
+		t.Fatalf("restored partitioner %s, want %s", got, ring.Partitioner().Config())
+	}
+	for _, key := range randomKeys(rand.New(rand.NewPCG(17, 0)), 2000) {
+		if want, got := ring.GetNodes(key), restored.GetNodes(key); !slices.Equal(want, got) {
+			t.Fatalf("restored ring places %q on %v, want %v", key, got, want)
+		}
+	}
+}
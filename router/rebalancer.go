@@ -0,0 +1,328 @@
+package router
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	pb "badies/proto/badiespb"
+)
+
+// RangeMove is a hash range whose replica set changed between two rings
+type RangeMove struct {
+	Range HashRange
+	From  []string // owners before the change
+	To    []string // owners after the change
+}
+
+// DiffRanges splits the circle at the tokens of both rings and returns the
+// ranges whose owners differ between before and after. Adjacent ranges with
+// the same owners are merged.
+func DiffRanges(before, after *HashRing) []RangeMove {
+	bounds := mergeTokens(before.tokens(), after.tokens())
+	var moves []RangeMove
+	for i, end := range bounds {
+		start := bounds[(i+len(bounds)-1)%len(bounds)]
+		from := before.ownersOf(end)
+		to := after.ownersOf(end)
+		if sameNodes(from, to) {
+			continue
+		}
+		if n := len(moves); n > 0 && moves[n-1].Range.End == start &&
+			sameNodes(moves[n-1].From, from) && sameNodes(moves[n-1].To, to) {
+			moves[n-1].Range.End = end
+			continue
+		}
+		moves = append(moves, RangeMove{Range: HashRange{Start: start, End: end}, From: from, To: to})
+	}
+	return moves
+}
+
+// mergeTokens returns the sorted, de-duplicated union of two token lists
+func mergeTokens(a, b []uint32) []uint32 {
+	merged := append(append([]uint32{}, a...), b...)
+	sort.Slice(merged, func(i, j int) bool { return merged[i] < merged[j] })
+	return slices.Compact(merged)
+}
+
+// sameNodes reports whether two node lists hold the same nodes in any order
+func sameNodes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, node := range a {
+		if !slices.Contains(b, node) {
+			return false
+		}
+	}
+	return true
+}
+
+// RebalanceProgress reports how far a rebalance has come
+type RebalanceProgress struct {
+	RangesTotal int
+	RangesDone  int
+	KeysScanned int
+	KeysCopied  int
+	KeysPurged  int
+	Errors      int
+}
+
+// Rebalancer moves keys between storage nodes after the ring changed: every
+// affected range is streamed from its old owners, copied to the nodes that
+// newly own it and purged from the nodes that no longer do.
+type Rebalancer struct {
+	nm *NodeManager
+
+	// KeysPerSecond throttles how many keys are moved per second, 0 means
+	// unlimited
+	KeysPerSecond int
+	// OnProgress is called after every range and every progressInterval keys
+	OnProgress func(RebalanceProgress)
+
+	mu sync.Mutex // only one rebalance runs at a time
+}
+
+// progressInterval is the number of scanned keys between progress reports
+const progressInterval = 1000
+
+// NewRebalancer creates a rebalancer that moves data through nm
+func NewRebalancer(nm *NodeManager) *Rebalancer {
+	return &Rebalancer{nm: nm}
+}
+
+// rangeTask is one moved range and the state of copying it
+type rangeTask struct {
+	move      RangeMove
+	targets   []string // nodes that newly own the range
+	fallbacks []string // owners that keep the range, to copy from when a source fails
+	copied    bool     // some source streamed the whole range
+	failed    bool     // some source failed to stream the range
+}
+
+// assignment is a range one source streams. A source that no longer owns
+// the range purges every key that reached all targets.
+type assignment struct {
+	task  *rangeTask
+	purge bool
+}
+
+// Rebalance moves the data stored for the ring before into the placement of
+// the ring after and returns how much it moved. Individual key failures are
+// counted and reported at the end; the rebalance can be re-run safely since
+// writes are versioned.
+//
+// Every range is copied from one of its old owners: one that is leaving the
+// range, since it has to stream its keys to purge them anyway, or else one
+// that keeps it. All ranges of a source are streamed in one request, and a
+// range whose source fails is streamed again from the next owner that keeps
+// it. Kept versions of each key move along with it.
+func (r *Rebalancer) Rebalance(ctx context.Context, before, after *HashRing) (RebalanceProgress, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	moves := DiffRanges(before, after)
+	progress := RebalanceProgress{RangesTotal: len(moves)}
+	log.Printf("Rebalance started: %d hash ranges changed owners", len(moves))
+
+	var throttle <-chan time.Time
+	if r.KeysPerSecond > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(r.KeysPerSecond))
+		defer ticker.Stop()
+		throttle = ticker.C
+	}
+
+	work := make(map[string][]assignment)
+	for _, move := range moves {
+		task := &rangeTask{move: move}
+		var leaving []string
+		for _, node := range move.To {
+			if !slices.Contains(move.From, node) {
+				task.targets = append(task.targets, node)
+			}
+		}
+		for _, node := range move.From {
+			if slices.Contains(move.To, node) {
+				task.fallbacks = append(task.fallbacks, node)
+			} else {
+				leaving = append(leaving, node)
+			}
+		}
+		for _, source := range leaving {
+			work[source] = append(work[source], assignment{task: task, purge: true})
+		}
+		if len(leaving) == 0 && len(task.targets) > 0 {
+			r.fallBack(task, work)
+		}
+	}
+
+	for len(work) > 0 {
+		tasks := make(map[*rangeTask]bool)
+		for _, assigned := range work {
+			for _, a := range assigned {
+				tasks[a.task] = true
+			}
+		}
+		for _, source := range slices.Sorted(maps.Keys(work)) {
+			if err := r.drainSource(ctx, source, work[source], after, &progress, throttle); err != nil {
+				if ctx.Err() != nil {
+					return progress, fmt.Errorf("rebalance cancelled after %d of %d ranges: %v", progress.RangesDone, progress.RangesTotal, err)
+				}
+				log.Printf("Rebalance of %d ranges from %s failed: %v", len(work[source]), source, err)
+				progress.Errors++
+				for _, a := range work[source] {
+					a.task.failed = true
+				}
+			} else {
+				for _, a := range work[source] {
+					a.task.copied = true
+				}
+			}
+		}
+
+		// Ranges that no source copied are tried again from the next owner
+		// that keeps them
+		work = make(map[string][]assignment)
+		for task := range tasks {
+			if !task.failed || task.copied || len(task.targets) == 0 || !r.fallBack(task, work) {
+				progress.RangesDone++
+			}
+			task.failed = false
+		}
+		r.report(progress)
+	}
+
+	if progress.Errors > 0 {
+		return progress, fmt.Errorf("rebalance finished with %d errors", progress.Errors)
+	}
+	log.Printf("Rebalance finished: copied %d keys, purged %d keys", progress.KeysCopied, progress.KeysPurged)
+	return progress, nil
+}
+
+// fallBack assigns task to the next owner that keeps its range and reports
+// whether there was one left
+func (r *Rebalancer) fallBack(task *rangeTask, work map[string][]assignment) bool {
+	if len(task.fallbacks) == 0 {
+		return false
+	}
+	source := task.fallbacks[0]
+	task.fallbacks = task.fallbacks[1:]
+	work[source] = append(work[source], assignment{task: task})
+	return true
+}
+
+// drainSource streams the assigned ranges from source in one request and
+// copies every key to the targets of its range. Keys of ranges the source
+// no longer owns are purged once they reached all targets.
+func (r *Rebalancer) drainSource(ctx context.Context, source string, work []assignment, ring *HashRing,
+	progress *RebalanceProgress, throttle <-chan time.Time) error {
+	client, err := r.nm.GetClient(source)
+	if err != nil {
+		return err
+	}
+	ranges := make([]*pb.NodeHashRange, len(work))
+	for i, a := range work {
+		ranges[i] = &pb.NodeHashRange{Start: a.task.move.Range.Start, End: a.task.move.Range.End}
+	}
+	stream, err := client.StreamRange(ctx, &pb.NodeRangeRequest{Ranges: ranges, Hash: ring.HashName(), History: true})
+	if err != nil {
+		return err
+	}
+
+	for {
+		rec, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		progress.KeysScanned++
+		if throttle != nil {
+			select {
+			case <-throttle:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		a := assignmentOf(work, ring.Position(string(rec.GetKey())))
+		if a == nil {
+			continue
+		}
+		copied := true
+		for _, target := range a.task.targets {
+			targetClient, err := r.nm.GetClient(target)
+			if err == nil {
+				err = copyRecord(ctx, targetClient, rec)
+			}
+			if err != nil {
+				log.Printf("Rebalance failed to copy key '%s' from %s to %s: %v", rec.GetKey(), source, target, err)
+				progress.Errors++
+				copied = false
+				continue
+			}
+			progress.KeysCopied++
+		}
+
+		if a.purge && copied {
+			_, err := client.Purge(ctx, &pb.NodePurgeRequest{Key: rec.GetKey(), Version: rec.GetVersion()})
+			if err != nil {
+				log.Printf("Rebalance failed to purge key '%s' from %s: %v", rec.GetKey(), source, err)
+				progress.Errors++
+			} else {
+				progress.KeysPurged++
+			}
+		}
+
+		if progress.KeysScanned%progressInterval == 0 {
+			r.report(*progress)
+		}
+	}
+}
+
+// assignmentOf returns the assignment whose range holds the position hash
+func assignmentOf(work []assignment, hash uint32) *assignment {
+	for i := range work {
+		if work[i].task.move.Range.Contains(hash) {
+			return &work[i]
+		}
+	}
+	return nil
+}
+
+func (r *Rebalancer) report(progress RebalanceProgress) {
+	log.Printf("Rebalance progress: %d/%d ranges, %d keys scanned, %d copied, %d purged, %d errors",
+		progress.RangesDone, progress.RangesTotal, progress.KeysScanned, progress.KeysCopied, progress.KeysPurged, progress.Errors)
+	if r.OnProgress != nil {
+		r.OnProgress(progress)
+	}
+}
+
+// copyRecord writes a record streamed from one node to another node, keeping
+// its version so the copy never overwrites newer data, and then the kept
+// versions that came with it
+func copyRecord(ctx context.Context, client pb.StorageNodeClient, rec *pb.NodeRecord) error {
+	var err error
+	if rec.GetTombstone() {
+		_, err = client.Delete(ctx, &pb.NodeDeleteRequest{Key: rec.GetKey(), Version: rec.GetVersion()})
+	} else {
+		_, err = client.Put(ctx, &pb.NodePutRequest{
+			Key:       rec.GetKey(),
+			Value:     rec.GetValue(),
+			Version:   rec.GetVersion(),
+			ExpiresAt: rec.GetExpiresAt(),
+		})
+	}
+	if err != nil || len(rec.GetHistory()) == 0 {
+		return err
+	}
+	_, err = client.KeepVersions(ctx, &pb.NodeKeepVersionsRequest{Key: rec.GetKey(), Versions: rec.GetHistory()})
+	return err
+}
@@ -0,0 +1,70 @@
+package router
+
+import (
+	"context"
+	"log"
+	"time"
+
+	pb "badies/proto/badiespb"
+)
+
+const (
+	// DefaultSweepInterval is the time between sweeps of expired keys
+	DefaultSweepInterval = time.Minute
+	// DefaultSweepGrace is how long an expired key is kept before it is
+	// removed, giving replicas that missed its last write time to catch up
+	DefaultSweepGrace = time.Hour
+)
+
+// Sweeper physically removes expired keys from every storage node. Expired
+// keys are already hidden from reads; the grace period keeps them around
+// long enough that a replica still holding an older version without a TTL
+// is repaired before the newer one disappears.
+type Sweeper struct {
+	nm *NodeManager
+
+	// Interval between sweeps
+	Interval time.Duration
+	// Grace is how long after expiry a key is removed
+	Grace time.Duration
+}
+
+// NewSweeper creates a sweeper for the nodes in nm
+func NewSweeper(nm *NodeManager) *Sweeper {
+	return &Sweeper{nm: nm, Interval: DefaultSweepInterval, Grace: DefaultSweepGrace}
+}
+
+// Run sweeps every Interval until ctx is cancelled
+func (sw *Sweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(sw.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if swept := sw.SweepOnce(ctx); swept > 0 {
+				log.Printf("Sweeper removed %d expired keys", swept)
+			}
+		}
+	}
+}
+
+// SweepOnce sweeps every node once and returns the number of keys removed
+func (sw *Sweeper) SweepOnce(ctx context.Context) int64 {
+	cutoff := time.Now().Add(-sw.Grace).UnixNano()
+	var swept int64
+	for _, nodeID := range sw.nm.ListNodes() {
+		client, err := sw.nm.GetClient(nodeID)
+		if err != nil {
+			continue
+		}
+		resp, err := client.Sweep(ctx, &pb.NodeSweepRequest{ExpiredBefore: cutoff})
+		if err != nil {
+			log.Printf("Sweeping node %s failed: %v", nodeID, err)
+			continue
+		}
+		swept += resp.GetSwept()
+	}
+	return swept
+}
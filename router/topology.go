@@ -0,0 +1,99 @@
+package router
+
+import (
+	"fmt"
+	"slices"
+)
+
+// EpochHeader is the gRPC metadata key carrying a ring epoch. The
+// coordinator sends it with every call to a node and returns it to clients.
+const EpochHeader = "x-ring-epoch"
+
+// Topology is the placement of a ring: its partitioner, its nodes with
+// their weights and tokens, and the epoch it was taken at. Restoring the
+// stored tokens, rather than assigning them again, keeps keys where they
+// are even if the way tokens are derived changes.
+type Topology struct {
+	Epoch       uint64
+	Partitioner PartitionerConfig
+	Nodes       []TopologyNode // in the order they joined
+}
+
+// TopologyNode is one node of a Topology
+type TopologyNode struct {
+	ID     string
+	Weight int
+	Tokens []uint32 // sorted tokens, for partitioners that assign them
+}
+
+// Epoch returns the ring's epoch. It starts at 0 and grows with every node
+// added or removed, so a ring can be told apart from an older copy.
+func (h *HashRing) Epoch() uint64 {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.epoch
+}
+
+// Topology returns the ring's current placement
+func (h *HashRing) Topology() Topology {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	t := Topology{Epoch: h.epoch, Partitioner: h.partitioner.Config()}
+	held := make(map[uint32]bool)
+	for _, nodeID := range h.order {
+		var tokens []uint32
+		for _, token := range h.nodeTokens[nodeID] {
+			if held[token] {
+				continue // two nodes drew the same token, the first one keeps it
+			}
+			held[token] = true
+			tokens = append(tokens, token)
+		}
+		t.Nodes = append(t.Nodes, TopologyNode{ID: nodeID, Weight: h.nodes[nodeID], Tokens: tokens})
+	}
+	return t
+}
+
+// RestoreHashRing rebuilds a ring from a topology taken with Topology. A
+// topology without a partitioner is a consistent hashing ring over
+// DefaultHash.
+func RestoreHashRing(t Topology, replicationFactor int) (*HashRing, error) {
+	p, err := NewPartitioner(t.Partitioner)
+	if err != nil {
+		return nil, err
+	}
+	_, assigned := p.(TokenAssigner)
+	h := NewPartitionedRing(p, replicationFactor)
+	h.epoch = t.Epoch
+	holder := make(map[uint32]string)
+	for _, node := range t.Nodes {
+		if _, exists := h.nodes[node.ID]; exists {
+			return nil, fmt.Errorf("node %s appears twice in the topology", node.ID)
+		}
+		if node.Weight < 1 {
+			return nil, fmt.Errorf("node %s has no weight in the topology", node.ID)
+		}
+		if assigned && len(node.Tokens) == 0 {
+			return nil, fmt.Errorf("node %s has no tokens in the topology", node.ID)
+		}
+		if !assigned && len(node.Tokens) > 0 {
+			return nil, fmt.Errorf("node %s has tokens in the topology, but the %s partitioner assigns none", node.ID, p.Config().Name)
+		}
+		for _, token := range node.Tokens {
+			if owner, taken := holder[token]; taken {
+				return nil, fmt.Errorf("token %d is held by both %s and %s", token, owner, node.ID)
+			}
+			holder[token] = node.ID
+		}
+		h.nodes[node.ID] = node.Weight
+		h.order = append(h.order, node.ID)
+		if assigned {
+			tokens := slices.Clone(node.Tokens)
+			slices.Sort(tokens)
+			h.nodeTokens[node.ID] = tokens
+		}
+	}
+	h.placeLocked()
+	return h, nil
+}
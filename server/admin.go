@@ -0,0 +1,324 @@
+package main
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+
+	"badies/meta"
+	pb "badies/proto/badiespb"
+	"badies/router"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// adminServer implements the Admin service for operators
+type adminServer struct {
+	pb.UnimplementedAdminServer
+	ctx         context.Context // cancelled at shutdown, bounds work that outlives a call
+	meta        *meta.Store     // keeps the ring across restarts
+	nodeManager *router.NodeManager
+	ring        *router.HashRing
+	rebalancer  *router.Rebalancer
+	antiEntropy *router.AntiEntropy
+	gossip      *router.Gossip // probes the nodes for failure detection
+	groups      *raftGroups    // set in strong consistency mode
+	writeQuorum int
+
+	mu          sync.Mutex // serializes membership changes
+	draining    map[string]*drain
+	rebalances  []rebalanceJob // pending rebalances in ring epoch order
+	rebalancing bool           // a worker is running the pending rebalances
+}
+
+// rebalanceJob moves the keys of one membership change. Changes are
+// rebalanced one at a time in the order they were made, since a change can
+// only stream keys from the nodes an earlier one copied them onto.
+type rebalanceJob struct {
+	before, after *router.HashRing
+	finish        func(router.RebalanceProgress, error) // called with the outcome
+}
+
+// drain tracks a node that left the ring while its keys are moved away. A
+// failed drain stays listed so that RemoveNode can retry it.
+type drain struct {
+	weight   int
+	before   *router.HashRing // the ring that still held the node, to retry from
+	done     chan struct{}    // closed when the drain finished
+	progress router.RebalanceProgress
+	err      error
+}
+
+// Repair runs anti-entropy for a node, a hash range or the whole ring
+func (a *adminServer) Repair(ctx context.Context, req *pb.RepairRequest) (*pb.RepairResponse, error) {
+	var stats router.RepairStats
+	var err error
+	switch target := req.GetTarget().(type) {
+	case *pb.RepairRequest_NodeId:
+		log.Printf("Admin: repairing ranges of node %s", target.NodeId)
+		stats, err = a.antiEntropy.RepairNode(ctx, target.NodeId)
+	case *pb.RepairRequest_Range:
+		rng := router.HashRange{Start: target.Range.GetStart(), End: target.Range.GetEnd()}
+		log.Printf("Admin: repairing range (%d, %d]", rng.Start, rng.End)
+		stats, err = a.antiEntropy.RepairRange(ctx, rng)
+	default:
+		log.Printf("Admin: repairing the whole ring")
+		stats, err = a.antiEntropy.RepairAll(ctx)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "repair failed after %d ranges: %v", stats.RangesCompared, err)
+	}
+	return &pb.RepairResponse{
+		RangesCompared: int32(stats.RangesCompared),
+		RangesRepaired: int32(stats.RangesRepaired),
+		KeysRepaired:   stats.KeysRepaired,
+	}, nil
+}
+
+// checkMembershipChange refuses to add, remove or replace nodes in strong
+// mode, since the raft groups keep the peers the server started with
+func (a *adminServer) checkMembershipChange() error {
+	if a.groups != nil {
+		return status.Error(codes.FailedPrecondition,
+			"nodes cannot be added, removed or replaced in strong consistency mode, the raft groups keep their peers")
+	}
+	return nil
+}
+
+// AddNode connects a node and puts it on the ring
+func (a *adminServer) AddNode(ctx context.Context, req *pb.AddNodeRequest) (*pb.AddNodeResponse, error) {
+	if err := a.checkMembershipChange(); err != nil {
+		return nil, err
+	}
+	nodeID, addr := req.GetNodeId(), req.GetAddress()
+	if nodeID == "" || addr == "" {
+		return nil, status.Error(codes.InvalidArgument, "a node needs an ID and an address")
+	}
+	if req.GetWeight() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid weight %d", req.GetWeight())
+	}
+	weight := max(int(req.GetWeight()), 1)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.draining[nodeID] != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "node %s is being removed", nodeID)
+	}
+	if a.nodeManager.NodeExists(nodeID) {
+		return nil, status.Errorf(codes.AlreadyExists, "node %s already exists", nodeID)
+	}
+	if err := a.nodeManager.AddNode(nodeID, addr); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	before := a.ring.Clone()
+	after := before.Clone()
+	after.AddNodeWithWeight(nodeID, weight)
+	if err := saveTopology(a.meta, after, a.nodeManager); err != nil {
+		a.nodeManager.RemoveNode(nodeID)
+		return nil, status.Errorf(codes.Internal, "node %s was not added: %v", nodeID, err)
+	}
+	a.ring.AddNodeWithWeight(nodeID, weight)
+	a.gossip.Track(nodeID, addr)
+	log.Printf("Admin: added node %s at %s with weight %d, ring epoch %d", nodeID, addr, weight, after.Epoch())
+
+	a.rebalance(before, after, nil)
+	return &pb.AddNodeResponse{}, nil
+}
+
+// RemoveNode takes a node off the ring, draining its keys first if asked to
+func (a *adminServer) RemoveNode(ctx context.Context, req *pb.RemoveNodeRequest) (*pb.RemoveNodeResponse, error) {
+	if err := a.checkMembershipChange(); err != nil {
+		return nil, err
+	}
+	nodeID := req.GetNodeId()
+
+	a.mu.Lock()
+	d := a.draining[nodeID]
+	var before, after *router.HashRing
+	switch {
+	case d != nil:
+		select {
+		case <-d.done:
+		default:
+			a.mu.Unlock()
+			return nil, status.Errorf(codes.FailedPrecondition, "node %s is already being drained", nodeID)
+		}
+		// Retry a failed drain from the ring the node left, since placing it
+		// again would not give it back its ranges under every partitioner
+		before = d.before
+		after = a.ring.Clone()
+	case a.ring.Weight(nodeID) == 0:
+		a.mu.Unlock()
+		return nil, status.Errorf(codes.NotFound, "node %s is not on the ring", nodeID)
+	case len(a.ring.GetAllNodes()) <= a.writeQuorum:
+		a.mu.Unlock()
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"removing node %s would leave fewer nodes than the write quorum of %d", nodeID, a.writeQuorum)
+	default:
+		before = a.ring.Clone()
+		after = before.Clone()
+		after.RemoveNode(nodeID)
+		if err := saveTopology(a.meta, after, a.nodeManager); err != nil {
+			a.mu.Unlock()
+			return nil, status.Errorf(codes.Internal, "node %s was not removed: %v", nodeID, err)
+		}
+		d = &drain{weight: a.ring.Weight(nodeID), before: before}
+		a.ring.RemoveNode(nodeID)
+	}
+
+	if !req.GetDrain() {
+		delete(a.draining, nodeID)
+		if err := a.nodeManager.RemoveNode(nodeID); err != nil {
+			log.Printf("Admin: %v", err)
+		}
+		a.rebalance(before, after, nil)
+		a.mu.Unlock()
+		log.Printf("Admin: removed node %s", nodeID)
+		return &pb.RemoveNodeResponse{}, nil
+	}
+
+	d.done = make(chan struct{})
+	d.err = nil
+	a.draining[nodeID] = d
+	log.Printf("Admin: draining node %s", nodeID)
+	a.rebalance(before, after, func(progress router.RebalanceProgress, err error) {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+		d.progress, d.err = progress, err
+		if err == nil {
+			delete(a.draining, nodeID)
+			if err := a.nodeManager.RemoveNode(nodeID); err != nil {
+				log.Printf("Admin: %v", err)
+			}
+			log.Printf("Admin: drained and removed node %s", nodeID)
+		} else {
+			log.Printf("Admin: drain of node %s failed: %v", nodeID, err)
+		}
+		close(d.done)
+	})
+	a.mu.Unlock()
+
+	select {
+	case <-d.done:
+	case <-ctx.Done():
+		return nil, status.Errorf(status.FromContextError(ctx.Err()).Code(),
+			"node %s is still draining in the background", nodeID)
+	}
+	if d.err != nil {
+		return nil, status.Errorf(codes.Unavailable,
+			"drain of node %s failed, it stays connected until RemoveNode is retried: %v", nodeID, d.err)
+	}
+	return &pb.RemoveNodeResponse{
+		KeysCopied: int64(d.progress.KeysCopied),
+		KeysPurged: int64(d.progress.KeysPurged),
+	}, nil
+}
+
+// ReplaceNode moves a node ID to a new address, such as a rebuilt machine,
+// and repairs the node's ranges onto it in the background
+func (a *adminServer) ReplaceNode(ctx context.Context, req *pb.ReplaceNodeRequest) (*pb.ReplaceNodeResponse, error) {
+	if err := a.checkMembershipChange(); err != nil {
+		return nil, err
+	}
+	nodeID, addr := req.GetNodeId(), req.GetAddress()
+	if addr == "" {
+		return nil, status.Error(codes.InvalidArgument, "a node needs an address")
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.nodeManager.NodeExists(nodeID) {
+		return nil, status.Errorf(codes.NotFound, "node %s not found", nodeID)
+	}
+	if a.draining[nodeID] != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "node %s is being removed", nodeID)
+	}
+	oldAddr, err := a.nodeManager.GetAddr(nodeID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "%v", err)
+	}
+	if err := a.nodeManager.ReplaceNode(nodeID, addr); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	if err := saveTopology(a.meta, a.ring, a.nodeManager); err != nil {
+		if rerr := a.nodeManager.ReplaceNode(nodeID, oldAddr); rerr != nil {
+			log.Printf("Admin: %v", rerr)
+		}
+		return nil, status.Errorf(codes.Internal, "node %s was not moved: %v", nodeID, err)
+	}
+	a.gossip.Track(nodeID, addr)
+	log.Printf("Admin: node %s moved to %s", nodeID, addr)
+
+	go func() {
+		stats, err := a.antiEntropy.RepairNode(a.ctx, nodeID)
+		if err != nil {
+			log.Printf("Admin: repair of replaced node %s failed: %v", nodeID, err)
+			return
+		}
+		log.Printf("Admin: repaired %d keys onto replaced node %s", stats.KeysRepaired, nodeID)
+	}()
+	return &pb.ReplaceNodeResponse{}, nil
+}
+
+// ListNodes lists the connected nodes
+func (a *adminServer) ListNodes(ctx context.Context, req *pb.ListNodesRequest) (*pb.ListNodesResponse, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	nodeIDs := a.nodeManager.ListNodes()
+	slices.Sort(nodeIDs)
+	resp := &pb.ListNodesResponse{}
+	for _, nodeID := range nodeIDs {
+		addr, err := a.nodeManager.GetAddr(nodeID)
+		if err != nil {
+			continue
+		}
+		info := &pb.NodeInfo{NodeId: nodeID, Address: addr, Weight: int32(a.ring.Weight(nodeID))}
+		if h, ok := a.nodeManager.Health(nodeID); ok {
+			info.Health = healthToPB(h)
+		}
+		if d := a.draining[nodeID]; d != nil {
+			info.State = pb.NodeInfo_DRAINING
+			info.Weight = int32(d.weight)
+		} else if a.ring.IsDown(nodeID) {
+			info.State = pb.NodeInfo_DOWN
+		}
+		resp.Nodes = append(resp.Nodes, info)
+	}
+	return resp, nil
+}
+
+// rebalance queues moving the keys of a membership change behind the
+// changes made before it. finish, if set, is called with the outcome. The
+// caller must hold a.mu.
+func (a *adminServer) rebalance(before, after *router.HashRing, finish func(router.RebalanceProgress, error)) {
+	a.rebalances = append(a.rebalances, rebalanceJob{before: before, after: after, finish: finish})
+	if !a.rebalancing {
+		a.rebalancing = true
+		go a.runRebalances()
+	}
+}
+
+// runRebalances runs the queued rebalances one at a time until none is left
+func (a *adminServer) runRebalances() {
+	for {
+		a.mu.Lock()
+		if len(a.rebalances) == 0 {
+			a.rebalancing = false
+			a.mu.Unlock()
+			return
+		}
+		job := a.rebalances[0]
+		a.rebalances = a.rebalances[1:]
+		a.mu.Unlock()
+
+		progress, err := a.rebalancer.Rebalance(a.ctx, job.before, job.after)
+		if job.finish != nil {
+			job.finish(progress, err)
+		} else if err != nil {
+			log.Printf("Rebalance failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"unicode/utf8"
+
+	pb "badies/proto/badiespb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxBatchSize bounds the number of keys in one batch call
+const maxBatchSize = 10000
+
+func checkBatchSize(n int) error {
+	if n > maxBatchSize {
+		return status.Errorf(codes.InvalidArgument, "batch of %d keys exceeds the limit of %d", n, maxBatchSize)
+	}
+	return nil
+}
+
+// BatchPut stores many key-value pairs with one write batch per storage node
+func (s *server) BatchPut(ctx context.Context, req *pb.BatchPutRequest) (*pb.BatchPutResponse, error) {
+	entries := req.GetEntries()
+	if err := checkBatchSize(len(entries)); err != nil {
+		return nil, err
+	}
+	recs := make([]*pb.NodeRecord, len(entries))
+	for i, entry := range entries {
+		recs[i] = &pb.NodeRecord{Key: []byte(entry.GetKey()), Value: []byte(entry.GetValue()), Version: s.clock.Next()}
+	}
+	return &pb.BatchPutResponse{Results: s.writeBatch(ctx, recs)}, nil
+}
+
+// BatchDelete writes tombstones for many keys with one write batch per
+// storage node
+func (s *server) BatchDelete(ctx context.Context, req *pb.BatchDeleteRequest) (*pb.BatchDeleteResponse, error) {
+	keys := req.GetKeys()
+	if err := checkBatchSize(len(keys)); err != nil {
+		return nil, err
+	}
+	recs := make([]*pb.NodeRecord, len(keys))
+	for i, key := range keys {
+		recs[i] = &pb.NodeRecord{Key: []byte(key), Version: s.clock.Next(), Tombstone: true}
+	}
+	return &pb.BatchDeleteResponse{Results: s.writeBatch(ctx, recs)}, nil
+}
+
+// groupByNode maps every replica of the given keys to the indexes of the
+// keys it stores
+func (s *server) groupByNode(keys []string) ([][]string, map[string][]int) {
+	targets := make([][]string, len(keys))
+	byNode := make(map[string][]int)
+	for i, key := range keys {
+		targets[i] = s.ring.GetNodes(key)
+		for _, nodeID := range targets[i] {
+			byNode[nodeID] = append(byNode[nodeID], i)
+		}
+	}
+	return targets, byNode
+}
+
+// writeBatch sends each replica one WriteBatch with all records it stores
+// and reports per key whether its write quorum was met. Failed replicas get
+// a hint per key, as for single writes. In strong mode every record goes
+// through its raft group on its own.
+func (s *server) writeBatch(ctx context.Context, recs []*pb.NodeRecord) []*pb.KeyResult {
+	results := make([]*pb.KeyResult, len(recs))
+	if s.groups != nil {
+		for i, rec := range recs {
+			results[i] = &pb.KeyResult{Key: string(rec.GetKey())}
+			acks, err := s.writeKey(ctx, rec)
+			if err != nil {
+				results[i].Error = status.Convert(err).Message()
+				continue
+			}
+			results[i].Success = true
+			results[i].Acks = int32(acks)
+		}
+		return results
+	}
+
+	keys := make([]string, len(recs))
+	for i, rec := range recs {
+		keys[i] = string(rec.GetKey())
+	}
+	targets, byNode := s.groupByNode(keys)
+
+	done := make(chan replicaResult, len(byNode))
+	for nodeID, indexes := range byNode {
+		go func() {
+			client, err := s.replicaClient(nodeID)
+			if err == nil {
+				batch := make([]*pb.NodeRecord, len(indexes))
+				for j, i := range indexes {
+					batch[j] = recs[i]
+				}
+				_, err = client.WriteBatch(ctx, &pb.NodeWriteBatchRequest{Records: batch})
+			}
+			done <- replicaResult{nodeID: nodeID, err: err}
+		}()
+	}
+
+	acks := make([]int, len(recs))
+	failures := make([][]replicaResult, len(recs))
+	for range byNode {
+		res := <-done
+		for _, i := range byNode[res.nodeID] {
+			if res.err != nil {
+				failures[i] = append(failures[i], res)
+			} else {
+				acks[i]++
+			}
+		}
+	}
+
+	failed := 0
+	for i, rec := range recs {
+		results[i] = &pb.KeyResult{Key: keys[i], Acks: int32(acks[i]), Success: acks[i] >= s.writeQuorum}
+		if len(failures[i]) > 0 {
+			s.handOff(ctx, keys[i], rec, targets[i], failures[i])
+		}
+		if results[i].Success {
+			s.watch.publish(rec)
+		} else {
+			failed++
+			results[i].Error = fmt.Sprintf("write quorum not met: %d of %d replicas acknowledged, need %d (%s)",
+				acks[i], len(targets[i]), s.writeQuorum, formatFailures(failures[i]))
+		}
+	}
+	log.Printf("Batch wrote %d keys through %d nodes, %d failed", len(recs), len(byNode), failed)
+	return results
+}
+
+// BatchGet reads many keys with one GetBatch per storage node. A key whose
+// value is not valid UTF-8 reports an error, since this API cannot carry it.
+func (s *server) BatchGet(ctx context.Context, req *pb.BatchGetRequest) (*pb.BatchGetResponse, error) {
+	resp, err := s.batchGet(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	for _, res := range resp.GetResults() {
+		if !utf8.ValidString(res.GetValue()) {
+			res.Value, res.Found = "", false
+			res.Error = status.Convert(errNotUTF8(res.GetKey())).Message()
+		}
+	}
+	return resp, nil
+}
+
+// batchGet needs readQuorum answers for every key and returns the newest
+// among them; stale replicas are repaired in the background.
+func (s *server) batchGet(ctx context.Context, req *pb.BatchGetRequest) (*pb.BatchGetResponse, error) {
+	keys := req.GetKeys()
+	if err := checkBatchSize(len(keys)); err != nil {
+		return nil, err
+	}
+	results := make([]*pb.GetResult, len(keys))
+	if s.groups != nil {
+		for i, key := range keys {
+			results[i] = &pb.GetResult{Key: key}
+			rec, err := s.groups.read(ctx, key)
+			if err != nil {
+				results[i].Error = status.Convert(err).Message()
+				continue
+			}
+			results[i].Found = rec.GetFound()
+			results[i].Value = string(rec.GetValue())
+		}
+		return &pb.BatchGetResponse{Results: results}, nil
+	}
+
+	targets, byNode := s.groupByNode(keys)
+	type nodeAnswer struct {
+		nodeID  string
+		records []*pb.NodeGetResponse
+		err     error
+	}
+	done := make(chan nodeAnswer, len(byNode))
+	for nodeID, indexes := range byNode {
+		go func() {
+			client, err := s.replicaClient(nodeID)
+			if err != nil {
+				done <- nodeAnswer{nodeID: nodeID, err: err}
+				return
+			}
+			batch := make([][]byte, len(indexes))
+			for j, i := range indexes {
+				batch[j] = []byte(keys[i])
+			}
+			resp, err := client.GetBatch(ctx, &pb.NodeGetBatchRequest{Keys: batch})
+			if err == nil && len(resp.GetRecords()) != len(indexes) {
+				err = fmt.Errorf("node answered %d of %d keys", len(resp.GetRecords()), len(indexes))
+			}
+			done <- nodeAnswer{nodeID: nodeID, records: resp.GetRecords(), err: err}
+		}()
+	}
+
+	answers := make([][]replicaRead, len(keys))
+	for range byNode {
+		res := <-done
+		if res.err != nil {
+			log.Printf("Batch read from node %s failed: %v", res.nodeID, res.err)
+			continue
+		}
+		for j, i := range byNode[res.nodeID] {
+			answers[i] = append(answers[i], replicaRead{nodeID: res.nodeID, resp: res.records[j]})
+		}
+	}
+
+	for i, key := range keys {
+		results[i] = &pb.GetResult{Key: key}
+		if len(answers[i]) < s.readQuorum {
+			results[i].Error = fmt.Sprintf("read quorum not met: %d of %d replicas answered, need %d",
+				len(answers[i]), len(targets[i]), s.readQuorum)
+			continue
+		}
+		newest := newestRead(answers[i]).resp
+		results[i].Found = newest.GetFound()
+		results[i].Value = string(newest.GetValue())
+	}
+	go func() {
+		for i, key := range keys {
+			if len(answers[i]) > 0 {
+				s.readRepair([]byte(key), answers[i], nil, 0, func() {})
+			}
+		}
+	}()
+	return &pb.BatchGetResponse{Results: results}, nil
+}
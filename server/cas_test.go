@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	pb "badies/proto/badiespb"
+)
+
+func TestLostSwapIsRevertedOnEveryReplica(t *testing.T) {
+	c := newTestCluster(t)
+	ctx := context.Background()
+	key := []byte("k")
+	const previous, swapped, concurrent = 100, 200, 300
+
+	// node1 lags behind and never received the previous version, and node3
+	// already took a concurrent write
+	for _, nodeID := range []string{"node2", "node3"} {
+		if _, err := c.nodes[nodeID].Put(ctx, &pb.NodePutRequest{Key: key, Value: []byte("previous"), Version: previous}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := c.nodes["node3"].Put(ctx, &pb.NodePutRequest{Key: key, Value: []byte("concurrent"), Version: concurrent}); err != nil {
+		t.Fatal(err)
+	}
+
+	rec := &pb.NodeRecord{Key: key, Value: []byte("swapped"), Version: swapped}
+	if _, conflict, err := c.swap(ctx, rec, previous); err != nil || !conflict {
+		t.Fatalf("swap = conflict %v, %v; want it to lose to the concurrent write", conflict, err)
+	}
+
+	stored := c.replicas(t, string(key))
+	if got := stored["node1"]; got.GetFound() {
+		t.Errorf("node1 holds version %d %q, want the swapped write dropped", got.GetVersion(), got.GetValue())
+	}
+	if got := stored["node2"]; got.GetVersion() != previous || string(got.GetValue()) != "previous" {
+		t.Errorf("node2 holds version %d %q, want the previous version back", got.GetVersion(), got.GetValue())
+	}
+	if got := stored["node3"]; got.GetVersion() != concurrent {
+		t.Errorf("node3 holds version %d, want the concurrent write kept", got.GetVersion())
+	}
+
+	// Repair from a replica that still has the swapped write cannot bring it
+	// back
+	for nodeID, client := range c.nodes {
+		resp, err := client.Put(ctx, &pb.NodePutRequest{Key: key, Value: rec.GetValue(), Version: swapped})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.GetApplied() {
+			t.Errorf("%s applied the reverted version again", nodeID)
+		}
+	}
+}
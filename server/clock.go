@@ -0,0 +1,37 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// versionClock hands out write versions. Versions are wall-clock nanoseconds
+// so that writes from different coordinators order roughly by time, and are
+// bumped when needed so that this coordinator never reuses or goes back on a
+// version.
+type versionClock struct {
+	mu   sync.Mutex
+	last uint64
+}
+
+// Next returns a version greater than every version returned before
+func (c *versionClock) Next() uint64 {
+	return c.NextAfter(0)
+}
+
+// NextAfter returns a version greater than every version returned before and
+// greater than seen, which may come from another coordinator's clock
+func (c *versionClock) NextAfter(seen uint64) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := uint64(time.Now().UnixNano())
+	if now <= c.last {
+		now = c.last + 1
+	}
+	if now <= seen {
+		now = seen + 1
+	}
+	c.last = now
+	return now
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+
+	pb "badies/proto/badiespb"
+	v2pb "badies/proto/badiesv2pb"
+	"badies/router"
+
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// reportHealth serves the server's grpc.health.v1 status every interval
+// until ctx is cancelled. The server is serving while at least writeQuorum
+// nodes on the ring are neither declared dead by gossip nor behind an open
+// circuit breaker; at shutdown it reports not serving.
+func reportHealth(ctx context.Context, hs *health.Server, nm *router.NodeManager, ring *router.HashRing, writeQuorum int, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	serving := true
+	for {
+		available := 0
+		for _, nodeID := range ring.GetAllNodes() {
+			if !ring.IsDown(nodeID) && nm.Available(nodeID) {
+				available++
+			}
+		}
+		switch {
+		case available < writeQuorum && serving:
+			log.Printf("Health: only %d nodes are available, below the write quorum of %d", available, writeQuorum)
+		case available >= writeQuorum && !serving:
+			log.Printf("Health: %d nodes are available again", available)
+		}
+		serving = available >= writeQuorum
+		servingStatus := healthpb.HealthCheckResponse_SERVING
+		if !serving {
+			servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		for _, service := range []string{"", pb.KeyVal_ServiceDesc.ServiceName, v2pb.KeyVal_ServiceDesc.ServiceName} {
+			hs.SetServingStatus(service, servingStatus)
+		}
+
+		select {
+		case <-ctx.Done():
+			hs.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// healthToPB converts what the node manager observed of a node
+func healthToPB(h router.NodeHealth) *pb.NodeHealth {
+	return &pb.NodeHealth{
+		Circuit:             pb.NodeHealth_Circuit(h.Circuit),
+		ErrorRate:           h.ErrorRate,
+		LatencyMs:           float64(h.Latency) / float64(time.Millisecond),
+		ConsecutiveFailures: int32(h.ConsecutiveFailures),
+		LastError:           h.LastError,
+	}
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"context"
+	"log"
+	"slices"
+	"unicode/utf8"
+
+	pb "badies/proto/badiespb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// askReplicas sends call to every replica of key and returns the first
+// readQuorum answers. A replica reporting that the revision was compacted
+// fails the whole read, since the others cannot have kept more.
+func askReplicas[T any](ctx context.Context, s *server, key string, call func(context.Context, pb.StorageNodeClient) (T, error)) ([]T, error) {
+	ctx, cancel := context.WithTimeout(ctx, replicaTimeout)
+	defer cancel()
+
+	targetNodes := s.ring.GetNodes(key)
+	type answer struct {
+		nodeID string
+		resp   T
+		err    error
+	}
+	results := make(chan answer, len(targetNodes))
+	for _, nodeID := range targetNodes {
+		go func() {
+			client, err := s.replicaClient(nodeID)
+			if err != nil {
+				results <- answer{nodeID: nodeID, err: err}
+				return
+			}
+			resp, err := call(ctx, client)
+			results <- answer{nodeID: nodeID, resp: resp, err: err}
+		}()
+	}
+
+	var answers []T
+	var failures []replicaResult
+	for range targetNodes {
+		res := <-results
+		if status.Code(res.err) == codes.OutOfRange {
+			return nil, status.Error(codes.OutOfRange, status.Convert(res.err).Message())
+		}
+		if res.err != nil {
+			failures = append(failures, replicaResult{nodeID: res.nodeID, err: res.err})
+			continue
+		}
+		if answers = append(answers, res.resp); len(answers) >= s.readQuorum {
+			return answers, nil
+		}
+	}
+	log.Printf("Error reading key '%s' from some replicas: %s", key, formatFailures(failures))
+	return nil, status.Errorf(codes.Unavailable,
+		"read quorum not met for key %q: %d of %d replicas answered, need %d",
+		key, len(answers), len(targetNodes), s.readQuorum)
+}
+
+// getAt reads the value key had at revision. Every replica answers with the
+// version it held at that revision and the newest one wins. Old versions are
+// never written again, so there is nothing to repair.
+func (s *server) getAt(ctx context.Context, key string, revision uint64) (*pb.GetResponse, error) {
+	answers, err := askReplicas(ctx, s, key, func(ctx context.Context, client pb.StorageNodeClient) (*pb.NodeGetResponse, error) {
+		return client.Get(ctx, &pb.NodeGetRequest{Key: []byte(key), Revision: revision})
+	})
+	if err != nil {
+		return nil, err
+	}
+	newest := answers[0]
+	for _, resp := range answers[1:] {
+		if resp.GetVersion() > newest.GetVersion() {
+			newest = resp
+		}
+	}
+	if !newest.GetFound() {
+		return &pb.GetResponse{Found: false, Revision: newest.GetVersion()}, nil
+	}
+	return &pb.GetResponse{Value: string(newest.GetValue()), Found: true, Revision: newest.GetVersion()}, nil
+}
+
+// History lists the versions of a key the replicas still keep, newest
+// first. A version whose value is not valid UTF-8 fails the call with
+// FailedPrecondition, since this API cannot carry it.
+func (s *server) History(ctx context.Context, req *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	resp, err := s.history(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	for _, ver := range resp.GetVersions() {
+		if !utf8.ValidString(ver.GetValue()) {
+			return nil, errNotUTF8(req.GetKey())
+		}
+	}
+	return resp, nil
+}
+
+// history merges the answers of readQuorum replicas, so a version missing
+// on one replica is still listed
+func (s *server) history(ctx context.Context, req *pb.HistoryRequest) (*pb.HistoryResponse, error) {
+	if req.GetLimit() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "history limit must not be negative, got %d", req.GetLimit())
+	}
+	key := req.GetKey()
+	answers, err := askReplicas(ctx, s, key, func(ctx context.Context, client pb.StorageNodeClient) (*pb.NodeVersionsResponse, error) {
+		return client.Versions(ctx, &pb.NodeVersionsRequest{Key: []byte(key), Limit: req.GetLimit()})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &pb.HistoryResponse{}
+	seen := make(map[uint64]bool)
+	for _, answer := range answers {
+		resp.CompactedRevision = max(resp.CompactedRevision, answer.GetCompactedRevision())
+		for _, rec := range answer.GetRecords() {
+			if seen[rec.GetVersion()] {
+				continue
+			}
+			seen[rec.GetVersion()] = true
+			resp.Versions = append(resp.Versions, &pb.KeyVersion{
+				Value:     string(rec.GetValue()),
+				Revision:  rec.GetVersion(),
+				Deleted:   rec.GetTombstone(),
+				ExpiresAt: rec.GetExpiresAt(),
+			})
+		}
+	}
+	slices.SortFunc(resp.Versions, func(a, b *pb.KeyVersion) int {
+		switch {
+		case a.GetRevision() > b.GetRevision():
+			return -1
+		case a.GetRevision() < b.GetRevision():
+			return 1
+		}
+		return 0
+	})
+	if limit := int(req.GetLimit()); limit > 0 && len(resp.Versions) > limit {
+		resp.Versions = resp.Versions[:limit]
+	}
+	return resp, nil
+}
@@ -2,179 +2,694 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"hash/crc32"
 	"log"
 	"net"
-	"path/filepath"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
+	"unicode/utf8"
 
+	"badies/config"
+	"badies/meta"
 	pb "badies/proto/badiespb"
+	v2pb "badies/proto/badiesv2pb"
 	"badies/router"
+	"badies/storage"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
 )
 
 type server struct {
 	pb.UnimplementedKeyValServer
 	nodeManager *router.NodeManager
 	ring        *router.HashRing
+	writeQuorum int // replicas that must acknowledge a Put (W)
+	readQuorum  int // replicas that must answer a Get (R)
+	clock       versionClock
+	groups      *raftGroups     // set in strong consistency mode
+	locks       [256]sync.Mutex // striped per-key locks serializing UpdateValue and UpdateKey
+	meta        *meta.Store     // durable coordinator state such as rename intents
+	watch       *watchHub       // change events for Watch streams
 }
 
-// Put stores a key-value pair across the nodes determined by the hash ring
+// Put stores a key-value pair across the nodes determined by the hash ring.
+// The write only succeeds once at least writeQuorum replicas acknowledged it.
 func (s *server) Put(ctx context.Context, req *pb.PutRequest) (*pb.PutResponse, error) {
 	key := req.GetKey()
 	value := []byte(req.GetValue()) // Convert string to []byte for storage
+	expiresAt, err := expiryOf(req.GetTtlMs())
+	if err != nil {
+		return nil, err
+	}
+	if s.groups != nil {
+		return s.strongPut(ctx, key, value, expiresAt)
+	}
+	version := s.clock.Next()
 	targetNodes := s.ring.GetNodes(key)
-	log.Printf("Storing key '%s' to nodes: %v", key, targetNodes)
+	log.Printf("Storing key '%s' at version %d to nodes: %v", key, version, targetNodes)
 
-	for _, nodeID := range targetNodes {
-		realNodeID := strings.Split(nodeID, "#")[0] // Strip replica info
-		db, err := s.nodeManager.GetDB(realNodeID)
-		if err != nil {
-			log.Printf("Failed to get DB for node %s: %v", realNodeID, err)
-			continue
-		}
-		err = db.Put([]byte(key), value, nil)
-		if err != nil {
-			log.Printf("Error writing key '%s' to node %s: %v", key, realNodeID, err)
-			continue
-		}
-		log.Printf("Successfully wrote key '%s' to node %s", key, realNodeID)
+	acks, failures := s.writeReplicas(ctx, targetNodes, func(ctx context.Context, client pb.StorageNodeClient) error {
+		_, err := client.Put(ctx, &pb.NodePutRequest{Key: []byte(key), Value: value, Version: version, ExpiresAt: expiresAt})
+		return err
+	})
+	if len(failures) > 0 {
+		log.Printf("Error writing key '%s' to some replicas: %s", key, formatFailures(failures))
+		s.handOff(ctx, key, &pb.NodeRecord{Key: []byte(key), Value: value, Version: version, ExpiresAt: expiresAt}, targetNodes, failures)
+	}
+	if acks < s.writeQuorum {
+		return nil, status.Errorf(codes.Unavailable,
+			"write quorum not met for key %q: %d of %d replicas acknowledged, need %d",
+			key, acks, len(targetNodes), s.writeQuorum)
 	}
-	return &pb.PutResponse{Success: true}, nil
+	log.Printf("Successfully wrote key '%s' to %d replicas", key, acks)
+	s.watch.publish(&pb.NodeRecord{Key: []byte(key), Value: value, Version: version})
+	return &pb.PutResponse{Success: true, Acks: int32(acks), Revision: version}, nil
 }
 
-// Get retrieves a value for a given key from the nodes in the hash ring
+// Get retrieves a value for a given key. Values that are not valid UTF-8
+// fail with FailedPrecondition, since this API cannot carry them.
 func (s *server) Get(ctx context.Context, req *pb.GetRequest) (*pb.GetResponse, error) {
+	resp, err := s.get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.ValidString(resp.GetValue()) {
+		return nil, errNotUTF8(req.GetKey())
+	}
+	return resp, nil
+}
+
+// get waits for readQuorum replicas and returns the newest version among
+// them; stale replicas are repaired in the background.
+func (s *server) get(ctx context.Context, req *pb.GetRequest) (*pb.GetResponse, error) {
 	key := req.GetKey()
+	if req.GetRevision() > 0 {
+		return s.getAt(ctx, key, req.GetRevision())
+	}
+	if s.groups != nil {
+		return s.strongGet(ctx, key)
+	}
 	targetNodes := s.ring.GetNodes(key)
 	log.Printf("Retrieving key '%s' from nodes: %v", key, targetNodes)
 
-	for _, nodeID := range targetNodes {
-		realNodeID := strings.Split(nodeID, "#")[0] // Strip replica info
-		db, err := s.nodeManager.GetDB(realNodeID)
-		if err != nil {
-			log.Printf("Failed to get DB for node %s: %v", realNodeID, err)
-			continue
-		}
-		value, err := db.Get([]byte(key), nil)
-		if err != nil {
-			log.Printf("Error reading key '%s' from node %s: %v", key, realNodeID, err)
-			continue
-		}
-		return &pb.GetResponse{Value: string(value), Found: true}, nil
+	rec, err := s.readReplicas(ctx, []byte(key), targetNodes)
+	if err != nil {
+		return nil, err
 	}
-	return &pb.GetResponse{Value: "", Found: false}, nil
+	if !rec.GetFound() {
+		return &pb.GetResponse{Value: "", Found: false, Revision: rec.GetVersion()}, nil
+	}
+	return &pb.GetResponse{Value: string(rec.GetValue()), Found: true, Revision: rec.GetVersion()}, nil
 }
 
-// Delete removes a key from the nodes in the hash ring
+// Delete removes a key from the nodes in the hash ring by writing a
+// tombstone, so replicas that miss the delete are repaired instead of
+// resurrecting the key
 func (s *server) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
 	key := req.GetKey()
+	if s.groups != nil {
+		return s.strongDelete(ctx, key)
+	}
+	version := s.clock.Next()
 	targetNodes := s.ring.GetNodes(key)
-	log.Printf("Deleting key '%s' from nodes: %v", key, targetNodes)
+	log.Printf("Deleting key '%s' at version %d from nodes: %v", key, version, targetNodes)
+
+	acks, failures := s.writeReplicas(ctx, targetNodes, func(ctx context.Context, client pb.StorageNodeClient) error {
+		_, err := client.Delete(ctx, &pb.NodeDeleteRequest{Key: []byte(key), Version: version})
+		return err
+	})
+	if len(failures) > 0 {
+		log.Printf("Error deleting key '%s' from some replicas: %s", key, formatFailures(failures))
+		s.handOff(ctx, key, &pb.NodeRecord{Key: []byte(key), Version: version, Tombstone: true}, targetNodes, failures)
+	}
+	if acks < s.writeQuorum {
+		return nil, status.Errorf(codes.Unavailable,
+			"write quorum not met for key %q: %d of %d replicas acknowledged, need %d",
+			key, acks, len(targetNodes), s.writeQuorum)
+	}
+	log.Printf("Deleted key '%s' from %d replicas", key, acks)
+	s.watch.publish(&pb.NodeRecord{Key: []byte(key), Version: version, Tombstone: true})
+	return &pb.DeleteResponse{Success: true, Revision: version}, nil
+}
+
+// UpdateValue atomically replaces the value of a key if it still holds the
+// old value, or the expected version when one is given. A failed comparison
+// against a value that is not valid UTF-8 fails with FailedPrecondition,
+// since this API cannot carry it.
+func (s *server) UpdateValue(ctx context.Context, req *pb.UpdateValueRequest) (*pb.UpdateValueResponse, error) {
+	resp, err := s.updateValue(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if !utf8.ValidString(resp.GetCurrentValue()) {
+		return nil, errNotUTF8(req.GetKey())
+	}
+	return resp, nil
+}
+
+// updateValue serializes calls for the same key on this coordinator, and
+// replicas reject the new value if a newer version reached them since the
+// comparison. On failure the response carries what the key holds now. The
+// key keeps its TTL.
+func (s *server) updateValue(ctx context.Context, req *pb.UpdateValueRequest) (*pb.UpdateValueResponse, error) {
+	key := req.GetKey()
+	mu := s.keyLock(key)
+	mu.Lock()
+	defer mu.Unlock()
 
-	success := false
-	for _, nodeID := range targetNodes {
-		realNodeID := strings.Split(nodeID, "#")[0]
-		db, err := s.nodeManager.GetDB(realNodeID)
+	current, err := s.readKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !casMatches(req, current) {
+		return casFailed(current), nil
+	}
+
+	rec := &pb.NodeRecord{
+		Key:       []byte(key),
+		Value:     []byte(req.GetNewValue()),
+		Version:   s.clock.NextAfter(current.GetVersion()),
+		ExpiresAt: current.GetExpiresAt(),
+	}
+	version, conflict, err := s.swap(ctx, rec, current.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+	if conflict {
+		latest, err := s.readKey(ctx, key)
 		if err != nil {
-			log.Printf("Failed to get DB for node %s: %v", realNodeID, err)
-			continue
+			return nil, err
 		}
-		err = db.Delete([]byte(key), nil)
+		return casFailed(latest), nil
+	}
+	return &pb.UpdateValueResponse{Success: true, CurrentValue: req.GetNewValue(), Version: version, Found: true}, nil
+}
+
+// swap writes rec as a conditional write against expected, the version the
+// caller compared with, and returns the version it was written at. conflict
+// reports that a concurrent write reached a replica first. In strong mode the
+// write goes through the key's raft group, which checks the condition when
+// it applies the entry.
+func (s *server) swap(ctx context.Context, rec *pb.NodeRecord, expected uint64) (version uint64, conflict bool, err error) {
+	key := string(rec.GetKey())
+	if s.groups != nil {
+		var resp *pb.NodeRaftWriteResponse
+		id := newRequestID()
+		err := s.groups.call(ctx, key, func(ctx context.Context, client pb.StorageNodeClient, group uint64) error {
+			var err error
+			resp, err = client.RaftWrite(ctx, &pb.NodeRaftWriteRequest{
+				GroupId:         group,
+				Key:             rec.GetKey(),
+				Value:           rec.GetValue(),
+				Conditional:     true,
+				ExpectedVersion: expected,
+				ExpiresAt:       rec.GetExpiresAt(),
+				RequestId:       id,
+			})
+			return err
+		})
 		if err != nil {
-			log.Printf("Error deleting key '%s' from node %s: %v", key, realNodeID, err)
-			continue
+			return 0, false, err
+		}
+		if !resp.GetConflict() {
+			s.watch.publish(&pb.NodeRecord{Key: rec.GetKey(), Value: rec.GetValue(), Version: resp.GetVersion()})
+		}
+		return resp.GetVersion(), resp.GetConflict(), nil
+	}
+
+	targetNodes := s.ring.GetNodes(key)
+	log.Printf("Swapping key '%s' from version %d to %d on nodes: %v", key, expected, rec.GetVersion(), targetNodes)
+	acks, failures := s.writeReplicas(ctx, targetNodes, func(ctx context.Context, client pb.StorageNodeClient) error {
+		resp, err := client.Put(ctx, &pb.NodePutRequest{
+			Key:             rec.GetKey(),
+			Value:           rec.GetValue(),
+			Version:         rec.GetVersion(),
+			Conditional:     true,
+			ExpectedVersion: expected,
+			ExpiresAt:       rec.GetExpiresAt(),
+		})
+		if err == nil && resp.GetConflict() {
+			return errWriteConflict
+		}
+		return err
+	})
+
+	var unreachable []replicaResult
+	for _, f := range failures {
+		if errors.Is(f.err, errWriteConflict) {
+			conflict = true
+		} else {
+			unreachable = append(unreachable, f)
 		}
-		log.Printf("Successfully deleted key '%s' from node %s", key, realNodeID)
-		success = true
 	}
-	return &pb.DeleteResponse{Success: success}, nil
+	if conflict {
+		log.Printf("Swap of key '%s' lost to a concurrent write: %s", key, formatFailures(failures))
+		s.revert(ctx, rec, expected, targetNodes)
+		return 0, true, nil
+	}
+	if len(unreachable) > 0 {
+		log.Printf("Error swapping key '%s' on some replicas: %s", key, formatFailures(unreachable))
+		s.handOff(ctx, key, rec, targetNodes, unreachable)
+	}
+	if acks < s.writeQuorum {
+		return 0, false, status.Errorf(codes.Unavailable,
+			"write quorum not met for key %q: %d of %d replicas acknowledged, need %d",
+			key, acks, len(targetNodes), s.writeQuorum)
+	}
+	s.watch.publish(rec)
+	return rec.GetVersion(), false, nil
 }
 
-// UpdateKey renames a key across the nodes in the hash ring
-func (s *server) UpdateKey(ctx context.Context, req *pb.UpdateKeyRequest) (*pb.UpdateKeyResponse, error) {
-	oldKey := req.GetOldKey()
-	newKey := req.GetNewKey()
+// keyLock returns the mutex serializing compare-and-swap calls on key
+func (s *server) keyLock(key string) *sync.Mutex {
+	return &s.locks[s.lockIndex(key)]
+}
 
-	// Get value for oldKey
-	resp, err := s.Get(ctx, &pb.GetRequest{Key: oldKey})
-	if err != nil || !resp.Found {
-		return &pb.UpdateKeyResponse{Success: false}, err
+func (s *server) lockIndex(key string) int {
+	return int(crc32.ChecksumIEEE([]byte(key)) % uint32(len(s.locks)))
+}
+
+// lockKeys takes the locks of several keys in a fixed order, so concurrent
+// multi-key calls cannot deadlock, and returns a function releasing them
+func (s *server) lockKeys(keys ...string) func() {
+	indexes := make([]int, 0, len(keys))
+	for _, key := range keys {
+		indexes = append(indexes, s.lockIndex(key))
+	}
+	slices.Sort(indexes)
+	indexes = slices.Compact(indexes)
+	for _, i := range indexes {
+		s.locks[i].Lock()
+	}
+	return func() {
+		for _, i := range indexes {
+			s.locks[i].Unlock()
+		}
 	}
+}
 
-	// Store value under newKey
-	_, err = s.Put(ctx, &pb.PutRequest{Key: newKey, Value: resp.Value})
-	if err != nil {
-		return &pb.UpdateKeyResponse{Success: false}, err
+// revert undoes a swap that lost to a concurrent write, so that read repair
+// does not spread the losing version. Every replica is asked, since a read
+// may already have repaired the write onto one that rejected it; replicas
+// that moved on to another version are left alone. A replica that cannot be
+// reached now keeps the write.
+func (s *server) revert(ctx context.Context, rec *pb.NodeRecord, previous uint64, nodes []string) {
+	_, failures := s.writeReplicas(ctx, nodes, func(ctx context.Context, client pb.StorageNodeClient) error {
+		_, err := client.Revert(ctx, &pb.NodeRevertRequest{Key: rec.GetKey(), Version: rec.GetVersion(), PreviousVersion: previous})
+		return err
+	})
+	if len(failures) > 0 {
+		log.Printf("Failed to revert the lost swap of key '%s' on some replicas: %s", rec.GetKey(), formatFailures(failures))
 	}
+}
 
-	// Delete oldKey
-	_, err = s.Delete(ctx, &pb.DeleteRequest{Key: oldKey})
-	if err != nil {
-		return &pb.UpdateKeyResponse{Success: false}, err
+// casMatches reports whether the record read for an UpdateValue call passes
+// its comparison
+func casMatches(req *pb.UpdateValueRequest, current *pb.NodeGetResponse) bool {
+	if !current.GetFound() {
+		return false
+	}
+	if req.GetExpectedVersion() != 0 {
+		return current.GetVersion() == req.GetExpectedVersion()
 	}
+	return string(current.GetValue()) == req.GetOldValue()
+}
 
-	return &pb.UpdateKeyResponse{Success: true}, nil
+// casFailed reports a failed comparison together with the current record
+func casFailed(current *pb.NodeGetResponse) *pb.UpdateValueResponse {
+	return &pb.UpdateValueResponse{
+		Success:      false,
+		CurrentValue: string(current.GetValue()),
+		Version:      current.GetVersion(),
+		Found:        current.GetFound(),
+	}
 }
 
-// UpdateValue updates the value for a key if the old value matches
-func (s *server) UpdateValue(ctx context.Context, req *pb.UpdateValueRequest) (*pb.UpdateValueResponse, error) {
-	key := req.GetKey()
-	oldValue := req.GetOldValue()
-	newValue := req.GetNewValue()
+// parseNodes parses a comma separated list of id=host:port pairs. A bare
+// address is used as its own node ID.
+func parseNodes(spec string) ([]string, map[string]string, error) {
+	var ids []string
+	addrs := make(map[string]string)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, addr, ok := strings.Cut(entry, "=")
+		if !ok {
+			id, addr = entry, entry
+		}
+		if _, dup := addrs[id]; dup {
+			return nil, nil, fmt.Errorf("node %s listed twice", id)
+		}
+		ids = append(ids, id)
+		addrs[id] = addr
+	}
+	if len(ids) == 0 {
+		return nil, nil, fmt.Errorf("no storage nodes given")
+	}
+	return ids, addrs, nil
+}
 
-	// Verify old value
-	resp, err := s.Get(ctx, &pb.GetRequest{Key: key})
-	if err != nil || !resp.Found || resp.Value != oldValue {
-		return &pb.UpdateValueResponse{Success: false}, err
+// parseWeights parses a comma separated list of id=weight pairs
+func parseWeights(spec string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, w, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected id=weight, got %q", entry)
+		}
+		weight, err := strconv.Atoi(w)
+		if err != nil || weight < 1 {
+			return nil, fmt.Errorf("invalid weight %q for node %s", w, id)
+		}
+		weights[id] = weight
 	}
+	return weights, nil
+}
+
+// defaultShutdownTimeout bounds how long a graceful shutdown waits for
+// in-flight requests
+const defaultShutdownTimeout = 10 * time.Second
+
+func main() {
+	configPath := flag.String("config", os.Getenv("BADIES_CONFIG"), "YAML cluster file; flags and BADIES_* environment variables override it")
+	port := flag.Int("port", 50051, "port to serve the KeyVal service on")
+	nodes := flag.String("nodes",
+		"node1=localhost:5001,node2=localhost:5002,node3=localhost:5003,node4=localhost:5004,node5=localhost:5005",
+		"comma separated storage nodes as id=host:port")
+	weights := flag.String("weights", "", "comma separated node weights as id=weight (default 1)")
+	vnodes := flag.Int("vnodes", 3, "virtual nodes per unit of node weight on the hash ring")
+	partitionerName := flag.String("partitioner", router.DefaultPartitioner, "how keys are placed on nodes: consistent, jump, rendezvous or range")
+	hashName := flag.String("hash", "", "hash placing keys on the ring: crc32 (default), fnv, murmur3 or xxhash; the range partitioner keeps keys in order and takes none")
+	partitions := flag.Int("partitions", router.DefaultPartitions, "fixed partitions of the jump and rendezvous partitioners")
+	replicationFactor := flag.Int("replication-factor", 3, "number of nodes each key is stored on")
+	writeQuorum := flag.Int("write-quorum", 2, "replicas that must acknowledge a Put (W)")
+	readQuorum := flag.Int("read-quorum", 2, "replicas that must answer a Get (R)")
+	rebalanceRate := flag.Int("rebalance-rate", 0, "keys moved per second while rebalancing, 0 for unlimited")
+	antiEntropyInterval := flag.Duration("anti-entropy-interval", router.DefaultAntiEntropyInterval, "time between background Merkle tree repairs of the whole ring")
+	merkleDepth := flag.Int("merkle-depth", router.DefaultMerkleDepth, "depth of the Merkle tree built per ring range")
+	hintReplayInterval := flag.Duration("hint-replay-interval", router.DefaultHintReplayInterval, "how often hinted writes are replayed to their replicas")
+	sweepInterval := flag.Duration("sweep-interval", router.DefaultSweepInterval, "time between sweeps that remove expired keys from the nodes")
+	sweepGrace := flag.Duration("sweep-grace", router.DefaultSweepGrace, "how long expired keys are kept before a sweep removes them")
+	watchHistory := flag.Int("watch-history", defaultWatchHistory, "number of recent change events kept for Watch streams resuming from a revision")
+	metaPath := flag.String("meta-path", "dbs/coordinator", "directory of the coordinator's durable metadata store")
+	consistency := flag.String("consistency", "eventual", "replication mode: eventual (quorum writes) or strong (raft group per ring range)")
+	gossipAddr := flag.String("gossip-addr", "", "address other members reach this server's Gossip service on (default localhost:<port>)")
+	gossipID := flag.String("gossip-id", "", "member ID of this server in gossip (default the gossip address)")
+	probeInterval := flag.Duration("probe-interval", router.DefaultProbeInterval, "how often gossip probes a member")
+	suspicionTimeout := flag.Duration("suspicion-timeout", router.DefaultSuspicionTimeout, "how long a suspected member has to refute before it is declared dead")
+	failureThreshold := flag.Int("failure-threshold", router.DefaultFailureThreshold, "consecutive failed calls that open a node's circuit breaker")
+	maxErrorRate := flag.Float64("max-error-rate", router.DefaultMaxErrorRate, "moving average of failed calls, from 0 to 1, that opens a node's circuit breaker")
+	breakerCooldown := flag.Duration("breaker-cooldown", router.DefaultBreakerCooldown, "how long a node's circuit breaker stays open before the node is probed")
+	healthCheckInterval := flag.Duration("health-check-interval", router.DefaultHealthCheckInterval, "time between health probes of the nodes")
+	shutdownTimeout := flag.Duration("shutdown-timeout", defaultShutdownTimeout, "how long in-flight requests may run after SIGINT or SIGTERM before they are cancelled")
+	flag.Parse()
 
-	// Store new value
-	_, err = s.Put(ctx, &pb.PutRequest{Key: key, Value: newValue})
+	var settings map[string]string
+	if *configPath != "" {
+		cluster, err := config.Load(*configPath)
+		if err != nil {
+			log.Fatalf("%v", err)
+		}
+		if settings, err = cluster.ServerSettings(); err != nil {
+			log.Fatalf("Invalid config %s: %v", *configPath, err)
+		}
+	}
+	if err := config.Apply(flag.CommandLine, "BADIES_", settings); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+
+	if *vnodes < 1 {
+		log.Fatalf("--vnodes must be at least 1, got %d", *vnodes)
+	}
+	partitioner, err := router.NewPartitioner(router.PartitionerConfig{
+		Name:       *partitionerName,
+		Hash:       *hashName,
+		VNodes:     *vnodes,
+		Partitions: *partitions,
+	})
 	if err != nil {
-		return &pb.UpdateValueResponse{Success: false}, err
+		log.Fatalf("Invalid --partitioner, --hash or --partitions: %v", err)
+	}
+	if *replicationFactor < 1 {
+		log.Fatalf("--replication-factor must be at least 1, got %d", *replicationFactor)
 	}
 
-	return &pb.UpdateValueResponse{Success: true}, nil
-}
+	if *writeQuorum < 1 || *writeQuorum > *replicationFactor {
+		log.Fatalf("--write-quorum must be between 1 and %d, got %d", *replicationFactor, *writeQuorum)
+	}
+	if *readQuorum < 1 || *readQuorum > *replicationFactor {
+		log.Fatalf("--read-quorum must be between 1 and %d, got %d", *replicationFactor, *readQuorum)
+	}
+	if *merkleDepth < 0 || *merkleDepth > router.MaxMerkleDepth {
+		log.Fatalf("--merkle-depth must be between 0 and %d, got %d", router.MaxMerkleDepth, *merkleDepth)
+	}
+	if *failureThreshold < 1 {
+		log.Fatalf("--failure-threshold must be at least 1, got %d", *failureThreshold)
+	}
+	if *maxErrorRate <= 0 || *maxErrorRate > 1 {
+		log.Fatalf("--max-error-rate must be above 0 and at most 1, got %v", *maxErrorRate)
+	}
+	if *consistency != "eventual" && *consistency != "strong" {
+		log.Fatalf("--consistency must be eventual or strong, got %q", *consistency)
+	}
+	if *consistency == "eventual" && *readQuorum+*writeQuorum <= *replicationFactor {
+		log.Printf("Warning: R + W <= %d, reads may not observe the latest write", *replicationFactor)
+	}
 
-func main() {
-	// Create NodeManager
-	nodeManager := router.NewNodeManager()
+	nodeIDs, nodeAddrs, err := parseNodes(*nodes)
+	if err != nil {
+		log.Fatalf("Invalid --nodes: %v", err)
+	}
+	nodeWeights, err := parseWeights(*weights)
+	if err != nil {
+		log.Fatalf("Invalid --weights: %v", err)
+	}
 
-	// Define node IDs and paths
-	nodeIDs := []string{"node1", "node2", "node3", "node4", "node5"}
+	// SIGINT and SIGTERM cancel ctx, which stops the background loops and
+	// starts a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	metaStore, err := meta.Open(*metaPath)
+	if err != nil {
+		log.Fatalf("Failed to open metadata store: %v", err)
+	}
+
+	// The ring is restored from the metadata store, so membership changed at
+	// runtime survives restarts; the node flags only seed the first ring
+	topology, storedAddrs, restored, err := loadTopology(metaStore)
+	if err != nil {
+		log.Fatalf("Failed to load the ring: %v", err)
+	}
+	var ring *router.HashRing
+	if restored {
+		ring, err = router.RestoreHashRing(topology, *replicationFactor)
+		if err != nil {
+			log.Fatalf("Invalid stored ring: %v", err)
+		}
+		nodeIDs, nodeAddrs = nil, storedAddrs
+		for _, node := range topology.Nodes {
+			nodeIDs = append(nodeIDs, node.ID)
+		}
+		log.Printf("Restored the ring at epoch %d with %d nodes from %s; --nodes, --weights and the partitioner flags only seed a new ring",
+			topology.Epoch, len(nodeIDs), *metaPath)
+	} else {
+		ring = router.NewPartitionedRing(partitioner, *replicationFactor)
+		for _, nodeID := range nodeIDs {
+			weight, ok := nodeWeights[nodeID]
+			if !ok {
+				weight = 1
+			}
+			ring.AddNodeWithWeight(nodeID, weight)
+		}
+	}
+	log.Printf("Placing keys with %s", ring.Partitioner().Config())
+
+	// Create NodeManager and connect to every storage node. Every call
+	// carries the ring epoch so that nodes refuse a stale coordinator, and a
+	// node that keeps failing is skipped until a health probe succeeds.
+	// Responses may be as large as the nodes accept: a Merkle tree at the
+	// deepest --merkle-depth is over gRPC's default limit of 4 MiB.
+	nodeManager := router.NewNodeManager()
+	nodeManager.DialOptions = append(epochDialOptions(ring),
+		grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(storage.MaxMessageSize)))
+	nodeManager.FailureThreshold = *failureThreshold
+	nodeManager.MaxErrorRate = *maxErrorRate
+	nodeManager.BreakerCooldown = *breakerCooldown
+	nodeManager.HealthCheckInterval = *healthCheckInterval
 	for _, nodeID := range nodeIDs {
-		dbPath := filepath.Join("dbs", nodeID)
-		err := nodeManager.AddNode(nodeID, dbPath)
+		err := nodeManager.AddNode(nodeID, nodeAddrs[nodeID])
 		if err != nil {
 			log.Fatalf("Failed to add node %s: %v", nodeID, err)
 		}
 	}
+	if !restored {
+		if err := saveTopology(metaStore, ring, nodeManager); err != nil {
+			log.Fatalf("Failed to store the ring: %v", err)
+		}
+	}
+
+	go nodeManager.RunHealthChecks(ctx)
+
+	// Moves data when the Admin service changes ring membership
+	rebalancer := router.NewRebalancer(nodeManager)
+	rebalancer.KeysPerSecond = *rebalanceRate
+
+	// Deliver writes held for unreachable replicas once they are back
+	hintReplayer := router.NewHintReplayer(nodeManager)
+	hintReplayer.Interval = *hintReplayInterval
+	go hintReplayer.Run(ctx)
+
+	// Repair divergent replicas in the background
+	antiEntropy := router.NewAntiEntropy(nodeManager, ring)
+	antiEntropy.Interval = *antiEntropyInterval
+	antiEntropy.Depth = *merkleDepth
+	go antiEntropy.Run(ctx)
 
-	// Create a hash ring and add nodes
-	ring := router.NewHashRing(3) // 3 replicas per key
+	// Remove expired keys from the nodes
+	sweeper := router.NewSweeper(nodeManager)
+	sweeper.Interval = *sweepInterval
+	sweeper.Grace = *sweepGrace
+	go sweeper.Run(ctx)
+
+	// In strong mode every ring range is replicated by its own raft group
+	var groups *raftGroups
+	if *consistency == "strong" {
+		groups = newRaftGroups(nodeManager, ring)
+		go groups.Run(ctx)
+		log.Printf("Strong consistency mode: %d raft groups", len(groups.segments))
+	}
+
+	// Gossip probes the nodes; requests skip nodes it declared dead instead
+	// of waiting for them to time out, and hints go to the next live node
+	if *gossipAddr == "" {
+		*gossipAddr = fmt.Sprintf("localhost:%d", *port)
+	}
+	if *gossipID == "" {
+		*gossipID = *gossipAddr
+	}
+	gossipTransport := router.NewGRPCTransport()
+	gossip := router.NewGossip(*gossipID, *gossipAddr, gossipTransport)
+	gossip.ProbeInterval = *probeInterval
+	gossip.SuspicionTimeout = *suspicionTimeout
+	gossip.OnChange = func(m router.Member) {
+		ring.SetDown(m.ID, m.State == router.StateDead)
+	}
 	for _, nodeID := range nodeIDs {
-		ring.AddNode(nodeID)
+		gossip.Track(nodeID, nodeAddrs[nodeID])
+	}
+	go gossip.Run(ctx)
+
+	srv := &server{
+		nodeManager: nodeManager,
+		ring:        ring,
+		writeQuorum: *writeQuorum,
+		readQuorum:  *readQuorum,
+		groups:      groups,
+		meta:        metaStore,
 	}
+	srv.watch = newWatchHub(*watchHistory, srv.clock.Next())
+
+	// Finish renames and transactions a previous run logged but did not
+	// complete
+	srv.recoverLogged(ctx)
+	go srv.runRecovery(ctx)
 
 	// Start gRPC server
-	lis, err := net.Listen("tcp", ":50051")
+	addr := fmt.Sprintf(":%d", *port)
+	lis, err := net.Listen("tcp", addr)
 	if err != nil {
 		log.Fatalf("failed to listen: %v", err)
 	}
-	grpcServer := grpc.NewServer()
-	pb.RegisterKeyValServer(grpcServer, &server{nodeManager: nodeManager, ring: ring})
+	grpcServer := grpc.NewServer(epochServerInterceptors(ring)...)
+	pb.RegisterKeyValServer(grpcServer, srv)
+	v2pb.RegisterKeyValServer(grpcServer, &serverV2{s: srv})
+	pb.RegisterGossipServer(grpcServer, gossip)
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(grpcServer, healthServer)
+	go reportHealth(ctx, healthServer, nodeManager, ring, *writeQuorum, *healthCheckInterval)
+	pb.RegisterAdminServer(grpcServer, &adminServer{
+		ctx:         ctx,
+		meta:        metaStore,
+		nodeManager: nodeManager,
+		ring:        ring,
+		rebalancer:  rebalancer,
+		antiEntropy: antiEntropy,
+		gossip:      gossip,
+		groups:      groups,
+		writeQuorum: *writeQuorum,
+		draining:    make(map[string]*drain),
+	})
 
-	log.Println("gRPC server listening on :50051")
-	if err := grpcServer.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
+	// A second signal kills the server without waiting, and open Watch
+	// streams end so that they do not hold up the drain
+	go func() {
+		<-ctx.Done()
+		stop()
+		srv.watch.close()
+	}()
+
+	log.Printf("gRPC server listening on %s", addr)
+	exitCode := 0
+	if err := serve(ctx, grpcServer, lis, *shutdownTimeout); err != nil {
+		log.Printf("Shutdown: %v", err)
+		exitCode = 1
+	}
+	gossipTransport.Close()
+	if err := nodeManager.Close(); err != nil {
+		log.Printf("Failed to close node connections: %v", err)
+		exitCode = 1
 	}
+	if err := metaStore.Close(); err != nil {
+		log.Printf("Failed to close metadata store: %v", err)
+		exitCode = 1
+	}
+	log.Printf("Server stopped")
+	os.Exit(exitCode)
+}
 
-	// Close all DBs on shutdown (optional, if server supports graceful shutdown)
-	defer func() {
-		if err := nodeManager.Close(); err != nil {
-			log.Printf("Failed to close databases: %v", err)
-		}
+// serve runs grpcServer on lis until ctx is cancelled or serving fails.
+// Once ctx is cancelled it stops accepting connections and lets in-flight
+// RPCs finish, cancelling those still running after timeout.
+func serve(ctx context.Context, grpcServer *grpc.Server, lis net.Listener, timeout time.Duration) error {
+	served := make(chan error, 1)
+	go func() { served <- grpcServer.Serve(lis) }()
+	select {
+	case err := <-served:
+		return fmt.Errorf("failed to serve: %v", err)
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down, waiting up to %v for in-flight requests", timeout)
+	drained := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(drained)
 	}()
+	select {
+	case <-drained:
+		return nil
+	case <-time.After(timeout):
+		grpcServer.Stop()
+		return fmt.Errorf("in-flight requests still running after %v were cancelled", timeout)
+	}
 }
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// recoveryInterval is how often renames and transactions that could not
+// finish are retried
+const recoveryInterval = 30 * time.Second
+
+// recoverLogged resolves the renames and transactions in the metadata store
+// that did not complete
+func (s *server) recoverLogged(ctx context.Context) {
+	if n, err := s.recoverRenames(ctx); err != nil {
+		log.Printf("Rename recovery failed: %v", err)
+	} else if n > 0 {
+		log.Printf("Completed %d pending renames", n)
+	}
+	if n, err := s.recoverTxns(ctx); err != nil {
+		log.Printf("Transaction recovery failed: %v", err)
+	} else if n > 0 {
+		log.Printf("Resolved %d pending transactions", n)
+	}
+}
+
+// runRecovery calls recoverLogged every recoveryInterval until ctx is
+// cancelled
+func (s *server) runRecovery(ctx context.Context) {
+	ticker := time.NewTicker(recoveryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.recoverLogged(ctx)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	pb "badies/proto/badiespb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// renamePrefix is the metadata key prefix of logged rename intents
+const renamePrefix = "rename/"
+
+func renameKey(intent *pb.RenameIntent) string {
+	return fmt.Sprintf("%s%020d", renamePrefix, intent.GetVersion())
+}
+
+// UpdateKey renames a key. The rename is logged as an intent in the
+// metadata store before any replica is touched and then rolled forward: the
+// value is written under new_key and old_key is deleted, both at the same
+// version; the key keeps its TTL. If the coordinator crashes or a write
+// misses its quorum, the logged intent is completed later, so a rename never
+// ends half done.
+func (s *server) UpdateKey(ctx context.Context, req *pb.UpdateKeyRequest) (*pb.UpdateKeyResponse, error) {
+	oldKey := req.GetOldKey()
+	newKey := req.GetNewKey()
+	if oldKey == newKey {
+		return nil, status.Errorf(codes.InvalidArgument, "cannot rename key %q to itself", oldKey)
+	}
+	unlock := s.lockKeys(oldKey, newKey)
+	defer unlock()
+
+	old, err := s.readKey(ctx, oldKey)
+	if err != nil {
+		return nil, err
+	}
+	if !old.GetFound() {
+		return &pb.UpdateKeyResponse{Success: false}, nil
+	}
+	existing, err := s.readKey(ctx, newKey)
+	if err != nil {
+		return nil, err
+	}
+	if existing.GetFound() && req.GetFailIfExists() {
+		return &pb.UpdateKeyResponse{Success: false, NewKeyExists: true}, nil
+	}
+
+	intent := &pb.RenameIntent{
+		OldKey:    []byte(oldKey),
+		NewKey:    []byte(newKey),
+		Value:     old.GetValue(),
+		Version:   s.clock.NextAfter(max(old.GetVersion(), existing.GetVersion())),
+		ExpiresAt: old.GetExpiresAt(),
+	}
+	data, err := proto.Marshal(intent)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encoding rename intent failed: %v", err)
+	}
+	if err := s.meta.Put(renameKey(intent), data); err != nil {
+		return nil, status.Errorf(codes.Internal, "logging rename of key %q failed: %v", oldKey, err)
+	}
+	log.Printf("Renaming key '%s' to '%s' at version %d", oldKey, newKey, intent.GetVersion())
+
+	if err := s.completeRename(ctx, intent); err != nil {
+		return nil, status.Errorf(codes.Unavailable,
+			"rename of key %q to %q is logged and will be completed in the background: %s",
+			oldKey, newKey, status.Convert(err).Message())
+	}
+	return &pb.UpdateKeyResponse{Success: true}, nil
+}
+
+// completeRename applies both writes of a logged rename and drops the
+// intent once they reached their quorums. It is safe to repeat since the
+// writes carry the intent's version and are skipped where a key already
+// holds one at least as new, in strong mode too.
+func (s *server) completeRename(ctx context.Context, intent *pb.RenameIntent) error {
+	version := intent.GetVersion()
+	put := &pb.NodeRecord{
+		Key:       intent.GetNewKey(),
+		Value:     intent.GetValue(),
+		Version:   version,
+		ExpiresAt: intent.GetExpiresAt(),
+	}
+	if err := s.writeLogged(ctx, put); err != nil {
+		return err
+	}
+	del := &pb.NodeRecord{Key: intent.GetOldKey(), Version: version, Tombstone: true}
+	if err := s.writeLogged(ctx, del); err != nil {
+		return err
+	}
+	s.watch.publish(put)
+	s.watch.publish(del)
+	return s.meta.Delete(renameKey(intent))
+}
+
+// recoverRenames rolls every logged rename forward and returns how many
+// completed
+func (s *server) recoverRenames(ctx context.Context) (int, error) {
+	var intents []*pb.RenameIntent
+	err := s.meta.Scan(renamePrefix, func(key string, value []byte) error {
+		intent := &pb.RenameIntent{}
+		if err := proto.Unmarshal(value, intent); err != nil {
+			return fmt.Errorf("corrupt rename intent %s: %v", key, err)
+		}
+		intents = append(intents, intent)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	completed := 0
+	for _, intent := range intents {
+		done, err := s.recoverRename(ctx, intent)
+		if err != nil {
+			log.Printf("Rename of key '%s' to '%s' is still pending: %v", intent.GetOldKey(), intent.GetNewKey(), err)
+			continue
+		}
+		if done {
+			completed++
+		}
+	}
+	return completed, nil
+}
+
+// recoverRename completes one intent under the locks of its keys, unless a
+// concurrent call finished it first
+func (s *server) recoverRename(ctx context.Context, intent *pb.RenameIntent) (bool, error) {
+	unlock := s.lockKeys(string(intent.GetOldKey()), string(intent.GetNewKey()))
+	defer unlock()
+
+	if _, found, err := s.meta.Get(renameKey(intent)); err != nil || !found {
+		return false, err
+	}
+	if err := s.completeRename(ctx, intent); err != nil {
+		return false, err
+	}
+	return true, nil
+}
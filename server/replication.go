@@ -0,0 +1,313 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	pb "badies/proto/badiespb"
+	"badies/router"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// replicaTimeout bounds replica calls that outlive the client request, such
+// as reads from slow replicas and read repair
+const replicaTimeout = 5 * time.Second
+
+// errWriteConflict marks a conditional write a replica rejected because it
+// already holds a newer version
+var errWriteConflict = errors.New("write conflict")
+
+// replicaOp is an operation applied to a single replica
+type replicaOp func(ctx context.Context, client pb.StorageNodeClient) error
+
+// replicaResult is the outcome of a replicaOp on one node
+type replicaResult struct {
+	nodeID string
+	err    error
+}
+
+// replicaClient returns the client of a replica, failing fast if gossip
+// declared the node dead or its circuit breaker is open, so callers do not
+// wait for it to time out
+func (s *server) replicaClient(nodeID string) (pb.StorageNodeClient, error) {
+	if s.ring.IsDown(nodeID) {
+		return nil, fmt.Errorf("node %s is down", nodeID)
+	}
+	if h, ok := s.nodeManager.Health(nodeID); ok && h.Circuit != router.CircuitClosed {
+		return nil, fmt.Errorf("node %s is unhealthy, its circuit breaker is open", nodeID)
+	}
+	return s.nodeManager.GetClient(nodeID)
+}
+
+// writeReplicas runs op against every target node concurrently and waits for
+// all of them. It returns the number of replicas that acknowledged the write
+// along with the failures of the others.
+func (s *server) writeReplicas(ctx context.Context, targetNodes []string, op replicaOp) (int, []replicaResult) {
+	results := make(chan replicaResult, len(targetNodes))
+	for _, nodeID := range targetNodes {
+		go func() {
+			client, err := s.replicaClient(nodeID)
+			if err == nil {
+				err = op(ctx, client)
+			}
+			results <- replicaResult{nodeID: nodeID, err: err}
+		}()
+	}
+
+	acks := 0
+	var failures []replicaResult
+	for range targetNodes {
+		res := <-results
+		if res.err != nil {
+			failures = append(failures, res)
+			continue
+		}
+		acks++
+	}
+	return acks, failures
+}
+
+// formatFailures renders replica failures for logs and error messages
+func formatFailures(failures []replicaResult) string {
+	parts := make([]string, 0, len(failures))
+	for _, f := range failures {
+		parts = append(parts, fmt.Sprintf("%s: %v", f.nodeID, f.err))
+	}
+	return strings.Join(parts, "; ")
+}
+
+// writeKey writes rec to the replicas of its key and fails with Unavailable
+// unless writeQuorum of them acknowledged; failed replicas get a hint. In
+// strong mode the write is committed through the key's raft group instead,
+// which assigns its own version and reports no acks. Successful writes are
+// published to watchers.
+func (s *server) writeKey(ctx context.Context, rec *pb.NodeRecord) (int, error) {
+	if s.groups != nil {
+		version, err := s.strongWrite(ctx, rec, false)
+		if err != nil {
+			return 0, err
+		}
+		s.watch.publish(&pb.NodeRecord{Key: rec.GetKey(), Value: rec.GetValue(), Version: version, Tombstone: rec.GetTombstone()})
+		return 0, nil
+	}
+	acks, err := s.replicateKey(ctx, rec)
+	if err == nil {
+		s.watch.publish(rec)
+	}
+	return acks, err
+}
+
+// writeLogged writes rec of a logged rename or transaction, which may be
+// replayed, and leaves publishing it to the caller. Replicas skip a record
+// that is not newer than the one they hold, and in strong mode the raft
+// group does the same, since the record is committed at its own version.
+func (s *server) writeLogged(ctx context.Context, rec *pb.NodeRecord) error {
+	if s.groups != nil {
+		_, err := s.strongWrite(ctx, rec, true)
+		return err
+	}
+	_, err := s.replicateKey(ctx, rec)
+	return err
+}
+
+// replicateKey is writeKey in eventual mode, without publishing
+func (s *server) replicateKey(ctx context.Context, rec *pb.NodeRecord) (int, error) {
+	key := string(rec.GetKey())
+	targetNodes := s.ring.GetNodes(key)
+	acks, failures := s.writeReplicas(ctx, targetNodes, func(ctx context.Context, client pb.StorageNodeClient) error {
+		_, err := writeRecord(ctx, client, rec.GetKey(), &pb.NodeGetResponse{
+			Value:     rec.GetValue(),
+			Version:   rec.GetVersion(),
+			Tombstone: rec.GetTombstone(),
+			ExpiresAt: rec.GetExpiresAt(),
+		})
+		return err
+	})
+	if len(failures) > 0 {
+		log.Printf("Error writing key '%s' to some replicas: %s", key, formatFailures(failures))
+		s.handOff(ctx, key, rec, targetNodes, failures)
+	}
+	if acks < s.writeQuorum {
+		return acks, status.Errorf(codes.Unavailable,
+			"write quorum not met for key %q: %d of %d replicas acknowledged, need %d",
+			key, acks, len(targetNodes), s.writeQuorum)
+	}
+	return acks, nil
+}
+
+// readKey reads the newest record of key from a read quorum, or through the
+// key's raft group in strong mode
+func (s *server) readKey(ctx context.Context, key string) (*pb.NodeGetResponse, error) {
+	if s.groups != nil {
+		return s.groups.read(ctx, key)
+	}
+	return s.readReplicas(ctx, []byte(key), s.ring.GetNodes(key))
+}
+
+// replicaRead is one replica's answer to a read
+type replicaRead struct {
+	nodeID string
+	resp   *pb.NodeGetResponse
+	err    error
+}
+
+// readReplicas queries every target node for key and returns the newest
+// record once readQuorum replicas answered. The remaining answers are awaited
+// in the background, and every replica that turns out to be stale or missing
+// the key is repaired with the newest record.
+func (s *server) readReplicas(ctx context.Context, key []byte, targetNodes []string) (*pb.NodeGetResponse, error) {
+	// Reads are detached from the request so slow replicas can still take
+	// part in read repair after the client got its answer
+	rctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), replicaTimeout)
+	results := make(chan replicaRead, len(targetNodes))
+	for _, nodeID := range targetNodes {
+		go func() {
+			client, err := s.replicaClient(nodeID)
+			if err != nil {
+				results <- replicaRead{nodeID: nodeID, err: err}
+				return
+			}
+			resp, err := client.Get(rctx, &pb.NodeGetRequest{Key: key})
+			results <- replicaRead{nodeID: nodeID, resp: resp, err: err}
+		}()
+	}
+
+	var answered []replicaRead
+	var failures []replicaResult
+	received := 0
+	for received < len(targetNodes) && len(answered) < s.readQuorum {
+		select {
+		case res := <-results:
+			received++
+			if res.err != nil {
+				failures = append(failures, replicaResult{nodeID: res.nodeID, err: res.err})
+				continue
+			}
+			answered = append(answered, res)
+		case <-ctx.Done():
+			cancel()
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	if len(failures) > 0 {
+		log.Printf("Error reading key '%s' from some replicas: %s", key, formatFailures(failures))
+	}
+	if len(answered) < s.readQuorum {
+		cancel()
+		return nil, status.Errorf(codes.Unavailable,
+			"read quorum not met for key %q: %d of %d replicas answered, need %d",
+			key, len(answered), len(targetNodes), s.readQuorum)
+	}
+
+	newest := newestRead(answered)
+	go s.readRepair(key, answered, results, len(targetNodes)-received, cancel)
+	return newest.resp, nil
+}
+
+// newestRead picks the answer with the highest version
+func newestRead(answered []replicaRead) replicaRead {
+	newest := answered[0]
+	for _, res := range answered[1:] {
+		if res.resp.GetVersion() > newest.resp.GetVersion() {
+			newest = res
+		}
+	}
+	return newest
+}
+
+// readRepair waits for the replicas that had not answered yet and writes the
+// newest record back to every replica that returned an older one
+func (s *server) readRepair(key []byte, answered []replicaRead, pending <-chan replicaRead, remaining int, cancelReads context.CancelFunc) {
+	defer cancelReads()
+	for i := 0; i < remaining; i++ {
+		if res := <-pending; res.err == nil {
+			answered = append(answered, res)
+		}
+	}
+
+	newest := newestRead(answered)
+	if newest.resp.GetVersion() == 0 {
+		return // no replica has ever seen the key
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), replicaTimeout)
+	defer cancel()
+	for _, res := range answered {
+		if res.resp.GetVersion() >= newest.resp.GetVersion() {
+			continue
+		}
+		client, err := s.nodeManager.GetClient(res.nodeID)
+		if err != nil {
+			continue
+		}
+		applied, err := writeRecord(ctx, client, key, newest.resp)
+		if err != nil {
+			log.Printf("Read repair of key '%s' on node %s failed: %v", key, res.nodeID, err)
+			continue
+		}
+		if applied {
+			log.Printf("Read repair updated key '%s' on node %s to version %d", key, res.nodeID, newest.resp.GetVersion())
+		}
+	}
+}
+
+// writeRecord stores a record read from one replica on another replica,
+// keeping its version. It reports whether the replica was actually behind.
+func writeRecord(ctx context.Context, client pb.StorageNodeClient, key []byte, rec *pb.NodeGetResponse) (bool, error) {
+	if rec.GetTombstone() {
+		resp, err := client.Delete(ctx, &pb.NodeDeleteRequest{Key: key, Version: rec.GetVersion()})
+		return resp.GetApplied(), err
+	}
+	resp, err := client.Put(ctx, &pb.NodePutRequest{
+		Key:       key,
+		Value:     rec.GetValue(),
+		Version:   rec.GetVersion(),
+		ExpiresAt: rec.GetExpiresAt(),
+	})
+	return resp.GetApplied(), err
+}
+
+// handOff stores a write for every failed replica as a hint on another
+// node, preferring the nodes that follow the replica set on the ring. The
+// hint is replayed to the replica once it is reachable again.
+func (s *server) handOff(ctx context.Context, key string, rec *pb.NodeRecord, targetNodes []string, failures []replicaResult) {
+	failed := make(map[string]bool, len(failures))
+	for _, f := range failures {
+		failed[f.nodeID] = true
+	}
+	holders := s.ring.GetFallbackNodes(key)
+	for _, nodeID := range targetNodes {
+		if !failed[nodeID] {
+			holders = append(holders, nodeID) // healthy replicas as a last resort
+		}
+	}
+
+	for _, f := range failures {
+		stored := false
+		for _, holder := range holders {
+			if failed[holder] {
+				continue
+			}
+			client, err := s.replicaClient(holder)
+			if err != nil {
+				continue
+			}
+			if _, err := client.StoreHint(ctx, &pb.NodeHint{Target: f.nodeID, Record: rec}); err != nil {
+				log.Printf("Node %s could not store hint for node %s: %v", holder, f.nodeID, err)
+				continue
+			}
+			log.Printf("Stored hint for node %s on node %s for key '%s'", f.nodeID, holder, key)
+			stored = true
+			break
+		}
+		if !stored {
+			log.Printf("No node could hold a hint for node %s, key '%s' stays behind there", f.nodeID, key)
+		}
+	}
+}
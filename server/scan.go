@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bytes"
+	"container/heap"
+	"context"
+	"io"
+	"log"
+	"time"
+	"unicode/utf8"
+
+	pb "badies/proto/badiespb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// scanStream is one node's scan, positioned at its next record
+type scanStream struct {
+	nodeID string
+	stream pb.StorageNode_ScanClient
+	head   *pb.NodeRecord
+}
+
+// scanHeap orders node streams by the key of their next record
+type scanHeap struct {
+	streams []*scanStream
+	reverse bool
+}
+
+func (h *scanHeap) Len() int { return len(h.streams) }
+func (h *scanHeap) Less(i, j int) bool {
+	c := bytes.Compare(h.streams[i].head.GetKey(), h.streams[j].head.GetKey())
+	if h.reverse {
+		return c > 0
+	}
+	return c < 0
+}
+func (h *scanHeap) Swap(i, j int) { h.streams[i], h.streams[j] = h.streams[j], h.streams[i] }
+func (h *scanHeap) Push(x any)    { h.streams = append(h.streams, x.(*scanStream)) }
+func (h *scanHeap) Pop() any {
+	n := len(h.streams)
+	st := h.streams[n-1]
+	h.streams = h.streams[:n-1]
+	return st
+}
+
+// Scan streams the key-value pairs of a key range in key order. Keys are
+// scattered over the ring by hash, so every node is scanned and the sorted
+// node streams are merged; the replicas of a key are collapsed into its
+// newest version and deleted or expired keys are skipped. The scan
+// tolerates up to replication factor - 1 unreachable nodes, since every key
+// then still has a replica that answers. Pairs that are not valid UTF-8 are
+// left out, since this API cannot carry them.
+func (s *server) Scan(req *pb.ScanRequest, stream pb.KeyVal_ScanServer) error {
+	return s.scan(stream.Context(), req, func(key, value []byte) (bool, error) {
+		if !utf8.Valid(key) || !utf8.Valid(value) {
+			return false, nil
+		}
+		return true, stream.Send(&pb.ScanResponse{Key: string(key), Value: string(value)})
+	})
+}
+
+// scan runs a Scan and hands every pair to send, which reports whether it
+// delivered the pair
+func (s *server) scan(ctx context.Context, req *pb.ScanRequest, send func(key, value []byte) (bool, error)) error {
+	if req.GetLimit() < 0 {
+		return status.Errorf(codes.InvalidArgument, "scan limit must not be negative, got %d", req.GetLimit())
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // stops the node streams once the limit is reached
+
+	nodeReq := &pb.NodeScanRequest{
+		StartKey: []byte(req.GetStartKey()),
+		EndKey:   []byte(req.GetEndKey()),
+		Prefix:   []byte(req.GetPrefix()),
+		Reverse:  req.GetReverse(),
+	}
+	nodes := s.nodeManager.ListNodes()
+	tolerated := min(s.ring.ReplicationFactor(), len(nodes)) - 1
+	failed := 0
+	fail := func(nodeID string, err error) error {
+		failed++
+		log.Printf("Scan of node %s failed: %v", nodeID, err)
+		if failed > tolerated {
+			return status.Errorf(codes.Unavailable, "scan incomplete: %d nodes failed, at most %d tolerated", failed, tolerated)
+		}
+		return nil
+	}
+
+	h := &scanHeap{reverse: req.GetReverse()}
+	for _, nodeID := range nodes {
+		st := &scanStream{nodeID: nodeID}
+		client, err := s.replicaClient(nodeID)
+		if err == nil {
+			st.stream, err = client.Scan(ctx, nodeReq)
+		}
+		if err == nil {
+			st.head, err = st.stream.Recv()
+		}
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			if err := fail(nodeID, err); err != nil {
+				return err
+			}
+			continue
+		}
+		h.streams = append(h.streams, st)
+	}
+	heap.Init(h)
+
+	sent := int32(0)
+	for h.Len() > 0 {
+		key := h.streams[0].head.GetKey()
+		var newest *pb.NodeRecord
+		for h.Len() > 0 && bytes.Equal(h.streams[0].head.GetKey(), key) {
+			st := h.streams[0]
+			if newest == nil || st.head.GetVersion() > newest.GetVersion() {
+				newest = st.head
+			}
+			var err error
+			st.head, err = st.stream.Recv()
+			switch {
+			case err == io.EOF:
+				heap.Pop(h)
+			case err != nil:
+				heap.Pop(h)
+				if err := fail(st.nodeID, err); err != nil {
+					return err
+				}
+			default:
+				heap.Fix(h, 0)
+			}
+		}
+		if newest.GetTombstone() || expired(newest.GetExpiresAt(), time.Now()) {
+			continue
+		}
+
+		delivered, err := send(newest.GetKey(), newest.GetValue())
+		if err != nil {
+			return err
+		}
+		if !delivered {
+			continue
+		}
+		sent++
+		if req.GetLimit() > 0 && sent >= req.GetLimit() {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	pb "badies/proto/badiespb"
+	"badies/router"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// raftStartInterval is how often every replica is asked to join its raft
+// groups, so nodes that were down at startup catch up
+const raftStartInterval = 30 * time.Second
+
+// raftGroups maps ring segments to the raft groups that replicate them in
+// strong consistency mode. Each segment of the ring at startup is one group
+// across its replica set; membership changes are not supported, so the
+// groups keep the placement they were created with.
+type raftGroups struct {
+	nodeManager *router.NodeManager
+	ring        *router.HashRing
+	segments    []router.Segment
+}
+
+func newRaftGroups(nodeManager *router.NodeManager, ring *router.HashRing) *raftGroups {
+	return &raftGroups{nodeManager: nodeManager, ring: ring, segments: ring.Segments()}
+}
+
+// groupID derives a stable group ID from the end of its segment
+func groupID(seg router.Segment) uint64 {
+	return uint64(seg.Range.End) + 1
+}
+
+// groupFor returns the group ID and members replicating key
+func (g *raftGroups) groupFor(key string) (uint64, []string) {
+	hash := g.ring.Position(key)
+	for _, seg := range g.segments {
+		if seg.Range.Contains(hash) {
+			return groupID(seg), seg.Nodes
+		}
+	}
+	return 0, nil
+}
+
+// Run starts every group on its members and keeps doing so every
+// raftStartInterval until ctx is cancelled
+func (g *raftGroups) Run(ctx context.Context) {
+	ticker := time.NewTicker(raftStartInterval)
+	defer ticker.Stop()
+	for {
+		g.startAll(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (g *raftGroups) startAll(ctx context.Context) {
+	for _, seg := range g.segments {
+		desc := &pb.NodeRaftGroup{
+			GroupId: groupID(seg),
+			Range:   &pb.NodeHashRange{Start: seg.Range.Start, End: seg.Range.End},
+			Hash:    g.ring.HashName(),
+		}
+		for _, nodeID := range seg.Nodes {
+			addr, err := g.nodeManager.GetAddr(nodeID)
+			if err != nil {
+				log.Printf("Raft group %d: %v", desc.GroupId, err)
+				continue
+			}
+			desc.Peers = append(desc.Peers, &pb.NodeRaftPeer{NodeId: nodeID, Addr: addr})
+		}
+		for _, nodeID := range seg.Nodes {
+			client, err := g.nodeManager.GetClient(nodeID)
+			if err != nil {
+				continue
+			}
+			startCtx, cancel := context.WithTimeout(ctx, replicaTimeout)
+			resp, err := client.RaftStart(startCtx, desc)
+			cancel()
+			if err != nil {
+				log.Printf("Failed to start raft group %d on node %s: %v", desc.GroupId, nodeID, err)
+			} else if resp.GetStarted() {
+				log.Printf("Started raft group %d on node %s", desc.GroupId, nodeID)
+			}
+		}
+	}
+}
+
+// call tries op on the members of the group owning key in ring order until
+// one of them succeeds. Members forward writes to their leader, so any live
+// member can serve a request. A write that failed on one member may still
+// have been committed, so writes carry a request ID from newRequestID that
+// stays the same across members, and the group applies it only once.
+func (g *raftGroups) call(ctx context.Context, key string, op func(ctx context.Context, client pb.StorageNodeClient, group uint64) error) error {
+	group, members := g.groupFor(key)
+	if len(members) == 0 {
+		return status.Errorf(codes.Unavailable, "no raft group replicates key %q", key)
+	}
+	var failures []string
+	for _, nodeID := range members {
+		client, err := g.nodeManager.GetClient(nodeID)
+		if err == nil {
+			callCtx, cancel := context.WithTimeout(ctx, replicaTimeout)
+			err = op(callCtx, client, group)
+			cancel()
+		}
+		if err == nil {
+			return nil
+		}
+		failures = append(failures, fmt.Sprintf("%s: %v", nodeID, err))
+	}
+	return status.Errorf(codes.Unavailable, "raft group %d failed for key %q: %s",
+		group, key, strings.Join(failures, "; "))
+}
+
+// newRequestID returns a random, non-zero ID for a raft write
+func newRequestID() uint64 {
+	for {
+		if id := rand.Uint64(); id != 0 {
+			return id
+		}
+	}
+}
+
+// strongPut commits a write through the raft group of key
+func (s *server) strongPut(ctx context.Context, key string, value []byte, expiresAt int64) (*pb.PutResponse, error) {
+	var version uint64
+	id := newRequestID()
+	err := s.groups.call(ctx, key, func(ctx context.Context, client pb.StorageNodeClient, group uint64) error {
+		resp, err := client.RaftWrite(ctx, &pb.NodeRaftWriteRequest{GroupId: group, Key: []byte(key), Value: value, ExpiresAt: expiresAt, RequestId: id})
+		version = resp.GetVersion()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Committed key '%s' at version %d through raft", key, version)
+	s.watch.publish(&pb.NodeRecord{Key: []byte(key), Value: value, Version: version})
+	return &pb.PutResponse{Success: true, Revision: version}, nil
+}
+
+// strongWrite commits rec through the raft group of its key and returns the
+// version it got. The group assigns the version, unless exact is set: then
+// rec is committed at its own version, or skipped if the key already holds
+// one at least as new.
+func (s *server) strongWrite(ctx context.Context, rec *pb.NodeRecord, exact bool) (uint64, error) {
+	req := &pb.NodeRaftWriteRequest{
+		Key:       rec.GetKey(),
+		Value:     rec.GetValue(),
+		Tombstone: rec.GetTombstone(),
+		ExpiresAt: rec.GetExpiresAt(),
+		RequestId: newRequestID(),
+	}
+	if exact {
+		req.Version = rec.GetVersion()
+	}
+	var version uint64
+	err := s.groups.call(ctx, string(rec.GetKey()), func(ctx context.Context, client pb.StorageNodeClient, group uint64) error {
+		req.GroupId = group
+		resp, err := client.RaftWrite(ctx, req)
+		version = resp.GetVersion()
+		return err
+	})
+	return version, err
+}
+
+// read performs a linearizable read of key through its raft group
+func (g *raftGroups) read(ctx context.Context, key string) (*pb.NodeGetResponse, error) {
+	var rec *pb.NodeGetResponse
+	err := g.call(ctx, key, func(ctx context.Context, client pb.StorageNodeClient, group uint64) error {
+		var err error
+		rec, err = client.RaftRead(ctx, &pb.NodeRaftReadRequest{GroupId: group, Key: []byte(key)})
+		return err
+	})
+	return rec, err
+}
+
+// strongGet performs a linearizable read of key through its raft group
+func (s *server) strongGet(ctx context.Context, key string) (*pb.GetResponse, error) {
+	rec, err := s.groups.read(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if !rec.GetFound() {
+		return &pb.GetResponse{Value: "", Found: false, Revision: rec.GetVersion()}, nil
+	}
+	return &pb.GetResponse{Value: string(rec.GetValue()), Found: true, Revision: rec.GetVersion()}, nil
+}
+
+// strongDelete commits a tombstone through the raft group of key
+func (s *server) strongDelete(ctx context.Context, key string) (*pb.DeleteResponse, error) {
+	var version uint64
+	id := newRequestID()
+	err := s.groups.call(ctx, key, func(ctx context.Context, client pb.StorageNodeClient, group uint64) error {
+		resp, err := client.RaftWrite(ctx, &pb.NodeRaftWriteRequest{GroupId: group, Key: []byte(key), Tombstone: true, RequestId: id})
+		version = resp.GetVersion()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Deleted key '%s' at version %d through raft", key, version)
+	s.watch.publish(&pb.NodeRecord{Key: []byte(key), Version: version, Tombstone: true})
+	return &pb.DeleteResponse{Success: true, Revision: version}, nil
+}
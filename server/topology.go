@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"badies/meta"
+	pb "badies/proto/badiespb"
+	"badies/router"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// topologyKey is where the ring is kept in the metadata store
+const topologyKey = "ring/topology"
+
+// loadTopology returns the stored ring and the address of every node on it.
+// found is false if no ring was stored yet.
+func loadTopology(store *meta.Store) (t router.Topology, addrs map[string]string, found bool, err error) {
+	data, found, err := store.Get(topologyKey)
+	if err != nil || !found {
+		return t, nil, false, err
+	}
+	stored := &pb.RingTopology{}
+	if err := proto.Unmarshal(data, stored); err != nil {
+		return t, nil, false, fmt.Errorf("corrupt ring topology: %v", err)
+	}
+	t = router.Topology{
+		Epoch: stored.GetEpoch(),
+		Partitioner: router.PartitionerConfig{
+			Name:       stored.GetPartitioner(),
+			Hash:       stored.GetHash(),
+			VNodes:     int(stored.GetVnodes()),
+			Partitions: int(stored.GetPartitions()),
+		},
+	}
+	addrs = make(map[string]string, len(stored.GetNodes()))
+	for _, node := range stored.GetNodes() {
+		t.Nodes = append(t.Nodes, router.TopologyNode{
+			ID:     node.GetNodeId(),
+			Weight: int(node.GetWeight()),
+			Tokens: node.GetTokens(),
+		})
+		addrs[node.GetNodeId()] = node.GetAddress()
+	}
+	return t, addrs, true, nil
+}
+
+// saveTopology durably stores ring, with the addresses nm has for its nodes
+func saveTopology(store *meta.Store, ring *router.HashRing, nm *router.NodeManager) error {
+	t := ring.Topology()
+	stored := &pb.RingTopology{
+		Epoch:       t.Epoch,
+		Partitioner: t.Partitioner.Name,
+		Hash:        t.Partitioner.Hash,
+		Vnodes:      int32(t.Partitioner.VNodes),
+		Partitions:  int32(t.Partitioner.Partitions),
+	}
+	for _, node := range t.Nodes {
+		addr, err := nm.GetAddr(node.ID)
+		if err != nil {
+			return err
+		}
+		stored.Nodes = append(stored.Nodes, &pb.RingNode{
+			NodeId:  node.ID,
+			Address: addr,
+			Weight:  int32(node.Weight),
+			Tokens:  node.Tokens,
+		})
+	}
+	data, err := proto.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("encoding ring topology failed: %v", err)
+	}
+	if err := store.Put(topologyKey, data); err != nil {
+		return fmt.Errorf("storing ring topology failed: %v", err)
+	}
+	return nil
+}
+
+// epochOf returns the ring epoch a caller sent, or 0 if it sent none
+func epochOf(ctx context.Context) (uint64, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(router.EpochHeader)
+	if len(values) == 0 {
+		return 0, nil
+	}
+	epoch, err := strconv.ParseUint(values[0], 10, 64)
+	if err != nil {
+		return 0, status.Errorf(codes.InvalidArgument, "invalid ring epoch %q", values[0])
+	}
+	return epoch, nil
+}
+
+// checkEpoch refuses callers that have seen a newer ring than this
+// coordinator, whose topology must then be stale, and returns the header
+// telling callers the epoch the call was served at
+func checkEpoch(ctx context.Context, ring *router.HashRing) (metadata.MD, error) {
+	current := ring.Epoch()
+	epoch, err := epochOf(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if epoch > current {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"this coordinator's ring is at epoch %d, behind epoch %d", current, epoch)
+	}
+	return metadata.Pairs(router.EpochHeader, strconv.FormatUint(current, 10)), nil
+}
+
+// epochServerInterceptors check the ring epoch of client calls and report
+// the current one in the response header
+func epochServerInterceptors(ring *router.HashRing) []grpc.ServerOption {
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		header, err := checkEpoch(ctx, ring)
+		if err != nil {
+			return nil, err
+		}
+		if err := grpc.SetHeader(ctx, header); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		header, err := checkEpoch(ss.Context(), ring)
+		if err != nil {
+			return err
+		}
+		if err := ss.SetHeader(header); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+	return []grpc.ServerOption{grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream)}
+}
+
+// epochDialOptions attach the ring epoch to every call to a node, so nodes
+// refuse a coordinator that runs on an older ring than another one did
+func epochDialOptions(ring *router.HashRing) []grpc.DialOption {
+	withEpoch := func(ctx context.Context) context.Context {
+		return metadata.AppendToOutgoingContext(ctx, router.EpochHeader, strconv.FormatUint(ring.Epoch(), 10))
+	}
+	unary := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withEpoch(ctx), method, req, reply, cc, opts...)
+	}
+	stream := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withEpoch(ctx), desc, cc, method, opts...)
+	}
+	return []grpc.DialOption{grpc.WithUnaryInterceptor(unary), grpc.WithStreamInterceptor(stream)}
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	pb "badies/proto/badiespb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// expiryOf turns a TTL in milliseconds into an absolute expiry time in unix
+// nanoseconds, 0 meaning the key never expires
+func expiryOf(ttlMs int64) (int64, error) {
+	if ttlMs < 0 {
+		return 0, status.Errorf(codes.InvalidArgument, "ttl must not be negative, got %d ms", ttlMs)
+	}
+	if ttlMs == 0 {
+		return 0, nil
+	}
+	return time.Now().Add(time.Duration(ttlMs) * time.Millisecond).UnixNano(), nil
+}
+
+// expired reports whether an expiry time has passed at now
+func expired(expiresAt int64, now time.Time) bool {
+	return expiresAt != 0 && expiresAt <= now.UnixNano()
+}
+
+// Expire sets the TTL of an existing key
+func (s *server) Expire(ctx context.Context, req *pb.ExpireRequest) (*pb.ExpireResponse, error) {
+	if req.GetTtlMs() <= 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "ttl must be positive, got %d ms", req.GetTtlMs())
+	}
+	expiresAt, _ := expiryOf(req.GetTtlMs())
+	ok, err := s.setExpiry(ctx, req.GetKey(), expiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ExpireResponse{Success: ok}, nil
+}
+
+// Persist removes the TTL of an existing key
+func (s *server) Persist(ctx context.Context, req *pb.PersistRequest) (*pb.PersistResponse, error) {
+	ok, err := s.setExpiry(ctx, req.GetKey(), 0)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.PersistResponse{Success: ok}, nil
+}
+
+// setExpiry rewrites the current value of key at a new version with a new
+// expiry. It is a conditional write like UpdateValue, so a concurrent write
+// is not overwritten with the old value.
+func (s *server) setExpiry(ctx context.Context, key string, expiresAt int64) (bool, error) {
+	mu := s.keyLock(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	current, err := s.readKey(ctx, key)
+	if err != nil {
+		return false, err
+	}
+	if !current.GetFound() {
+		return false, nil
+	}
+	rec := &pb.NodeRecord{
+		Key:       []byte(key),
+		Value:     current.GetValue(),
+		Version:   s.clock.NextAfter(current.GetVersion()),
+		ExpiresAt: expiresAt,
+	}
+	_, conflict, err := s.swap(ctx, rec, current.GetVersion())
+	if err != nil {
+		return false, err
+	}
+	if conflict {
+		return false, status.Errorf(codes.Aborted, "key %q was written concurrently, retry", key)
+	}
+	return true, nil
+}
@@ -0,0 +1,404 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+
+	pb "badies/proto/badiespb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// txnPrefix is the metadata key prefix of logged transactions
+const txnPrefix = "txn/"
+
+func txnLogKey(id uint64) string {
+	return fmt.Sprintf("%s%020d", txnPrefix, id)
+}
+
+// compareHolds evaluates one compare against the key's current state
+func compareHolds(cmp *pb.TxnCompare, current *pb.NodeGetResponse) bool {
+	switch cmp.GetTarget() {
+	case pb.TxnCompare_VALUE:
+		return current.GetFound() && string(current.GetValue()) == cmp.GetValue()
+	case pb.TxnCompare_VERSION:
+		return current.GetFound() && current.GetVersion() == cmp.GetVersion()
+	case pb.TxnCompare_EXISTS:
+		return current.GetFound()
+	case pb.TxnCompare_MISSING:
+		return !current.GetFound()
+	}
+	return false
+}
+
+// Txn applies a list of puts and deletes atomically if every compare holds.
+// All keys are locked and read first; if the compares hold, every write is
+// committed at one version through two-phase commit: the transaction is
+// logged, every replica involved stages its writes and checks that no key
+// changed since it was read, and only once enough replicas of every key
+// prepared is the commit decision logged and sent out. After a crash,
+// recovery aborts logged transactions without a decision and completes
+// committed ones. In strong mode the committed writes go through the raft
+// groups of their keys instead of a prepare round, still at the
+// transaction's revision.
+func (s *server) Txn(ctx context.Context, req *pb.TxnRequest) (*pb.TxnResponse, error) {
+	if err := checkBatchSize(len(req.GetCompares()) + len(req.GetOps())); err != nil {
+		return nil, err
+	}
+	var keys []string
+	opKeys := make(map[string]bool, len(req.GetOps()))
+	for _, op := range req.GetOps() {
+		if opKeys[op.GetKey()] {
+			return nil, status.Errorf(codes.InvalidArgument, "transaction writes key %q more than once", op.GetKey())
+		}
+		opKeys[op.GetKey()] = true
+		keys = append(keys, op.GetKey())
+	}
+	for _, cmp := range req.GetCompares() {
+		keys = append(keys, cmp.GetKey())
+	}
+	unlock := s.lockKeys(keys...)
+	defer unlock()
+
+	current := make(map[string]*pb.NodeGetResponse, len(keys))
+	newest := uint64(0)
+	for _, key := range keys {
+		if _, ok := current[key]; ok {
+			continue
+		}
+		rec, err := s.readKey(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		current[key] = rec
+		newest = max(newest, rec.GetVersion())
+	}
+	var failed []int32
+	for i, cmp := range req.GetCompares() {
+		if !compareHolds(cmp, current[cmp.GetKey()]) {
+			failed = append(failed, int32(i))
+		}
+	}
+	if len(failed) > 0 {
+		return &pb.TxnResponse{Success: false, FailedCompares: failed}, nil
+	}
+	if len(req.GetOps()) == 0 {
+		return &pb.TxnResponse{Success: true}, nil
+	}
+
+	version := s.clock.NextAfter(newest)
+	txn := &pb.TxnLog{TxnId: version}
+	for _, op := range req.GetOps() {
+		rec := &pb.NodeRecord{Key: []byte(op.GetKey()), Version: version}
+		if op.GetType() == pb.TxnOp_DELETE {
+			rec.Tombstone = true
+		} else {
+			expiresAt, err := expiryOf(op.GetTtlMs())
+			if err != nil {
+				return nil, err
+			}
+			rec.Value = []byte(op.GetValue())
+			rec.ExpiresAt = expiresAt
+		}
+		txn.Records = append(txn.Records, rec)
+	}
+	log.Printf("Starting transaction %d over %d keys", version, len(current))
+
+	if s.groups != nil {
+		txn.Committed = true
+		if err := s.logTxn(txn); err != nil {
+			return nil, err
+		}
+	} else if err := s.prepareTxn(ctx, txn, current); err != nil {
+		return nil, err
+	}
+
+	if err := s.completeTxn(ctx, txn); err != nil {
+		return nil, status.Errorf(codes.Unavailable,
+			"transaction %d is committed and will be completed in the background: %s",
+			version, status.Convert(err).Message())
+	}
+	return &pb.TxnResponse{Success: true, Revision: version}, nil
+}
+
+func (s *server) logTxn(txn *pb.TxnLog) error {
+	data, err := proto.Marshal(txn)
+	if err != nil {
+		return status.Errorf(codes.Internal, "encoding transaction log failed: %v", err)
+	}
+	if err := s.meta.Put(txnLogKey(txn.GetTxnId()), data); err != nil {
+		return status.Errorf(codes.Internal, "logging transaction %d failed: %v", txn.GetTxnId(), err)
+	}
+	return nil
+}
+
+// prepareTxn runs the first phase: every replica of a key the transaction
+// touches stages its writes and checks the versions that were read. If any
+// replica refuses or a key lacks writeQuorum prepared replicas, the
+// transaction is aborted everywhere; otherwise the commit decision is
+// logged, naming the replicas that prepared. Replicas that could not be
+// reached get hints for their writes.
+func (s *server) prepareTxn(ctx context.Context, txn *pb.TxnLog, current map[string]*pb.NodeGetResponse) error {
+	requests := make(map[string]*pb.NodeTxnPrepareRequest)
+	request := func(nodeID string) *pb.NodeTxnPrepareRequest {
+		if requests[nodeID] == nil {
+			requests[nodeID] = &pb.NodeTxnPrepareRequest{TxnId: txn.GetTxnId()}
+			txn.Participants = append(txn.Participants, nodeID)
+		}
+		return requests[nodeID]
+	}
+	targets := make(map[string][]string, len(current))
+	for key, rec := range current {
+		targets[key] = s.ring.GetNodes(key)
+		for _, nodeID := range targets[key] {
+			prep := request(nodeID)
+			prep.Checks = append(prep.Checks, &pb.NodeTxnCheck{Key: []byte(key), Version: rec.GetVersion()})
+		}
+	}
+	for _, rec := range txn.GetRecords() {
+		for _, nodeID := range targets[string(rec.GetKey())] {
+			prep := request(nodeID)
+			prep.Records = append(prep.Records, rec)
+		}
+	}
+	if err := s.logTxn(txn); err != nil {
+		return err
+	}
+
+	type prepareResult struct {
+		nodeID string
+		resp   *pb.NodeTxnPrepareResponse
+		err    error
+	}
+	done := make(chan prepareResult, len(requests))
+	for nodeID, prep := range requests {
+		go func() {
+			client, err := s.replicaClient(nodeID)
+			if err != nil {
+				done <- prepareResult{nodeID: nodeID, err: err}
+				return
+			}
+			rctx, cancel := context.WithTimeout(ctx, replicaTimeout)
+			defer cancel()
+			resp, err := client.TxnPrepare(rctx, prep)
+			done <- prepareResult{nodeID: nodeID, resp: resp, err: err}
+		}()
+	}
+	prepared := make(map[string]bool, len(requests))
+	var refusals []string
+	var failures []replicaResult
+	for range requests {
+		res := <-done
+		switch {
+		case res.err != nil:
+			failures = append(failures, replicaResult{nodeID: res.nodeID, err: res.err})
+		case !res.resp.GetPrepared():
+			refusals = append(refusals, fmt.Sprintf("%s: %s", res.nodeID, res.resp.GetReason()))
+		default:
+			prepared[res.nodeID] = true
+		}
+	}
+
+	abort := func() {
+		if err := s.abortTxn(txn); err != nil {
+			log.Printf("Transaction %d could not be aborted on every node, retrying later: %v", txn.GetTxnId(), err)
+		}
+	}
+	if len(refusals) > 0 {
+		abort()
+		return status.Errorf(codes.Aborted, "transaction %d aborted: %s", txn.GetTxnId(), strings.Join(refusals, "; "))
+	}
+	for key, nodes := range targets {
+		acks := 0
+		for _, nodeID := range nodes {
+			if prepared[nodeID] {
+				acks++
+			}
+		}
+		if acks < s.writeQuorum {
+			abort()
+			return status.Errorf(codes.Unavailable,
+				"transaction %d aborted: %d of %d replicas of key %q prepared, need %d (%s)",
+				txn.GetTxnId(), acks, len(nodes), key, s.writeQuorum, formatFailures(failures))
+		}
+	}
+
+	// The commit point: from here on the transaction is completed, even
+	// after a crash
+	txn.Committed = true
+	txn.Participants = txn.Participants[:0]
+	for nodeID := range prepared {
+		txn.Participants = append(txn.Participants, nodeID)
+	}
+	for _, f := range failures {
+		txn.Unconfirmed = append(txn.Unconfirmed, f.nodeID)
+	}
+	if err := s.logTxn(txn); err != nil {
+		txn.Committed = false
+		txn.Participants = append(txn.Participants, txn.Unconfirmed...)
+		abort()
+		return err
+	}
+	for _, rec := range txn.GetRecords() {
+		key := string(rec.GetKey())
+		var missed []replicaResult
+		for _, f := range failures {
+			for _, nodeID := range targets[key] {
+				if nodeID == f.nodeID {
+					missed = append(missed, f)
+				}
+			}
+		}
+		if len(missed) > 0 {
+			s.handOff(ctx, key, rec, targets[key], missed)
+		}
+	}
+	return nil
+}
+
+// abortTxn tells every participant to drop the transaction and forgets it.
+// A participant that misses the abort keeps its keys reserved until
+// recovery retries it, so the log is only dropped once all of them heard.
+func (s *server) abortTxn(txn *pb.TxnLog) error {
+	ctx, cancel := context.WithTimeout(context.Background(), replicaTimeout)
+	defer cancel()
+	nodes := slices.Concat(txn.GetParticipants(), txn.GetUnconfirmed())
+	failed := s.decideTxn(ctx, txn.GetTxnId(), nodes, func(client pb.StorageNodeClient, decision *pb.NodeTxnDecision) error {
+		_, err := client.TxnAbort(ctx, decision)
+		return err
+	})
+	if len(failed) > 0 {
+		return status.Errorf(codes.Unavailable, "abort failed on some nodes: %s", formatFailures(failed))
+	}
+	return s.meta.Delete(txnLogKey(txn.GetTxnId()))
+}
+
+// decideTxn sends a decision to every node in nodes and returns the ones
+// that failed
+func (s *server) decideTxn(ctx context.Context, id uint64, nodes []string, send func(pb.StorageNodeClient, *pb.NodeTxnDecision) error) []replicaResult {
+	decision := &pb.NodeTxnDecision{TxnId: id}
+	done := make(chan replicaResult, len(nodes))
+	for _, nodeID := range nodes {
+		go func() {
+			client, err := s.replicaClient(nodeID)
+			if err == nil {
+				err = send(client, decision)
+			}
+			done <- replicaResult{nodeID: nodeID, err: err}
+		}()
+	}
+	var failed []replicaResult
+	for range nodes {
+		if res := <-done; res.err != nil {
+			failed = append(failed, res)
+		}
+	}
+	return failed
+}
+
+// completeTxn applies a committed transaction and drops its log once every
+// write is in place. It fails if a replica that prepared did not commit;
+// nodes that never confirmed their prepare got hints instead and are only
+// told so that they release what they may have staged, which recovery
+// retries until they answer. It is safe to repeat since all writes carry
+// the transaction's version. In strong mode every write is committed at that
+// version through the raft group of its key, and skipped where a replay
+// finds the key already at a version at least as new.
+func (s *server) completeTxn(ctx context.Context, txn *pb.TxnLog) error {
+	if s.groups != nil {
+		for _, rec := range txn.GetRecords() {
+			if err := s.writeLogged(ctx, rec); err != nil {
+				return err
+			}
+		}
+		log.Printf("Committed transaction %d", txn.GetTxnId())
+		for _, rec := range txn.GetRecords() {
+			s.watch.publish(rec)
+		}
+		return s.meta.Delete(txnLogKey(txn.GetTxnId()))
+	}
+
+	commit := func(client pb.StorageNodeClient, decision *pb.NodeTxnDecision) error {
+		rctx, cancel := context.WithTimeout(ctx, replicaTimeout)
+		defer cancel()
+		_, err := client.TxnCommit(rctx, decision)
+		return err
+	}
+	if failed := s.decideTxn(ctx, txn.GetTxnId(), txn.GetParticipants(), commit); len(failed) > 0 {
+		return status.Errorf(codes.Unavailable, "commit failed on some nodes: %s", formatFailures(failed))
+	}
+	log.Printf("Committed transaction %d", txn.GetTxnId())
+	for _, rec := range txn.GetRecords() {
+		s.watch.publish(rec)
+	}
+	if failed := s.decideTxn(ctx, txn.GetTxnId(), txn.GetUnconfirmed(), commit); len(failed) > 0 {
+		// Only the participant list shrinks; the writes are done
+		txn.Participants = nil
+		txn.Unconfirmed = txn.Unconfirmed[:0]
+		for _, f := range failed {
+			txn.Unconfirmed = append(txn.Unconfirmed, f.nodeID)
+		}
+		return s.logTxn(txn)
+	}
+	return s.meta.Delete(txnLogKey(txn.GetTxnId()))
+}
+
+// recoverTxns resolves every logged transaction and returns how many it
+// resolved
+func (s *server) recoverTxns(ctx context.Context) (int, error) {
+	var txns []*pb.TxnLog
+	err := s.meta.Scan(txnPrefix, func(key string, value []byte) error {
+		txn := &pb.TxnLog{}
+		if err := proto.Unmarshal(value, txn); err != nil {
+			return fmt.Errorf("corrupt transaction log %s: %v", key, err)
+		}
+		txns = append(txns, txn)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	resolved := 0
+	for _, txn := range txns {
+		done, err := s.recoverTxn(ctx, txn)
+		if err != nil {
+			log.Printf("Transaction %d is still pending: %v", txn.GetTxnId(), err)
+			continue
+		}
+		if done {
+			resolved++
+		}
+	}
+	return resolved, nil
+}
+
+// recoverTxn completes a committed transaction or aborts an undecided one,
+// under the locks of its keys, unless a concurrent call resolved it first
+func (s *server) recoverTxn(ctx context.Context, txn *pb.TxnLog) (bool, error) {
+	keys := make([]string, len(txn.GetRecords()))
+	for i, rec := range txn.GetRecords() {
+		keys[i] = string(rec.GetKey())
+	}
+	unlock := s.lockKeys(keys...)
+	defer unlock()
+
+	if _, found, err := s.meta.Get(txnLogKey(txn.GetTxnId())); err != nil || !found {
+		return false, err
+	}
+	if !txn.GetCommitted() {
+		if err := s.abortTxn(txn); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if err := s.completeTxn(ctx, txn); err != nil {
+		return false, err
+	}
+	return true, nil
+}
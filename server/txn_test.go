@@ -0,0 +1,301 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"badies/meta"
+	pb "badies/proto/badiespb"
+	"badies/router"
+	"badies/storage"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testCluster is a coordinator in front of three storage nodes that run in
+// the test process, every node replicating every key
+type testCluster struct {
+	*server
+	nodes map[string]pb.StorageNodeClient
+}
+
+func newTestCluster(t *testing.T) *testCluster {
+	t.Helper()
+	nodeManager := router.NewNodeManager()
+	t.Cleanup(func() { nodeManager.Close() })
+	ring := router.NewHashRing(1, 3)
+	c := &testCluster{nodes: make(map[string]pb.StorageNodeClient)}
+	for i := 1; i <= 3; i++ {
+		nodeID := fmt.Sprintf("node%d", i)
+		store, err := storage.NewServer(nodeID, filepath.Join(t.TempDir(), nodeID), nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		grpcServer := grpc.NewServer(
+			grpc.UnaryInterceptor(store.UnaryEpochInterceptor),
+			grpc.StreamInterceptor(store.StreamEpochInterceptor),
+		)
+		pb.RegisterStorageNodeServer(grpcServer, store)
+		go grpcServer.Serve(lis)
+		t.Cleanup(func() {
+			grpcServer.Stop()
+			store.Close()
+		})
+
+		if err := nodeManager.AddNode(nodeID, lis.Addr().String()); err != nil {
+			t.Fatal(err)
+		}
+		ring.AddNode(nodeID)
+		if c.nodes[nodeID], err = nodeManager.GetClient(nodeID); err != nil {
+			t.Fatal(err)
+		}
+	}
+	metaStore, err := meta.Open(filepath.Join(t.TempDir(), "coordinator"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { metaStore.Close() })
+	c.server = &server{nodeManager: nodeManager, ring: ring, writeQuorum: 2, readQuorum: 2, meta: metaStore}
+	return c
+}
+
+// newStrongTestCluster is newTestCluster in strong mode, and waits until the
+// raft groups of keys commit writes
+func newStrongTestCluster(t *testing.T, keys ...string) *testCluster {
+	t.Helper()
+	c := newTestCluster(t)
+	c.groups = newRaftGroups(c.nodeManager, c.ring)
+	c.groups.startAll(context.Background())
+	deadline := time.Now().Add(10 * time.Second)
+	for _, key := range keys {
+		for {
+			_, err := c.Put(context.Background(), &pb.PutRequest{Key: key, Value: "elected"})
+			if err == nil {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("raft group of key %q did not elect a leader: %v", key, err)
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+	}
+	return c
+}
+
+// replicas returns what every node stores for key
+func (c *testCluster) replicas(t *testing.T, key string) map[string]*pb.NodeGetResponse {
+	t.Helper()
+	stored := make(map[string]*pb.NodeGetResponse, len(c.nodes))
+	for nodeID, client := range c.nodes {
+		resp, err := client.Get(context.Background(), &pb.NodeGetRequest{Key: []byte(key)})
+		if err != nil {
+			t.Fatalf("Get(%q) on %s: %v", key, nodeID, err)
+		}
+		stored[nodeID] = resp
+	}
+	return stored
+}
+
+// loggedTxns returns the IDs of the transactions left in the metadata store
+func (c *testCluster) loggedTxns(t *testing.T) []string {
+	t.Helper()
+	var logged []string
+	err := c.meta.Scan(txnPrefix, func(key string, value []byte) error {
+		logged = append(logged, key)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return logged
+}
+
+// prepareOnAll stages txn on every node, as the first phase would
+func (c *testCluster) prepareOnAll(t *testing.T, txn *pb.TxnLog) {
+	t.Helper()
+	for nodeID, client := range c.nodes {
+		resp, err := client.TxnPrepare(context.Background(), &pb.NodeTxnPrepareRequest{TxnId: txn.GetTxnId(), Records: txn.GetRecords()})
+		if err != nil || !resp.GetPrepared() {
+			t.Fatalf("TxnPrepare on %s = %v, %v", nodeID, resp, err)
+		}
+		txn.Participants = append(txn.Participants, nodeID)
+	}
+}
+
+func TestTxnCommitsEveryKeyAtOneRevision(t *testing.T) {
+	c := newTestCluster(t)
+	ctx := context.Background()
+	if _, err := c.Put(ctx, &pb.PutRequest{Key: "c", Value: "old"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.Txn(ctx, &pb.TxnRequest{
+		Compares: []*pb.TxnCompare{{Key: "a", Target: pb.TxnCompare_MISSING}},
+		Ops: []*pb.TxnOp{
+			{Type: pb.TxnOp_PUT, Key: "a", Value: "1"},
+			{Type: pb.TxnOp_PUT, Key: "b", Value: "2"},
+			{Type: pb.TxnOp_DELETE, Key: "c"},
+		},
+	})
+	if err != nil || !resp.GetSuccess() {
+		t.Fatalf("Txn = %v, %v", resp, err)
+	}
+	for key, want := range map[string]string{"a": "1", "b": "2", "c": ""} {
+		for nodeID, stored := range c.replicas(t, key) {
+			if stored.GetVersion() != resp.GetRevision() || string(stored.GetValue()) != want || stored.GetTombstone() != (want == "") {
+				t.Errorf("%s stores %q as %v, want %q at revision %d", nodeID, key, stored, want, resp.GetRevision())
+			}
+		}
+	}
+	if logged := c.loggedTxns(t); len(logged) > 0 {
+		t.Errorf("completed transaction is still logged: %v", logged)
+	}
+}
+
+func TestTxnFailedCompareWritesNothing(t *testing.T) {
+	c := newTestCluster(t)
+	ctx := context.Background()
+	if _, err := c.Put(ctx, &pb.PutRequest{Key: "a", Value: "1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := c.Txn(ctx, &pb.TxnRequest{
+		Compares: []*pb.TxnCompare{
+			{Key: "a", Target: pb.TxnCompare_VALUE, Value: "1"},
+			{Key: "a", Target: pb.TxnCompare_VALUE, Value: "2"},
+		},
+		Ops: []*pb.TxnOp{{Type: pb.TxnOp_PUT, Key: "b", Value: "2"}},
+	})
+	if err != nil || resp.GetSuccess() || len(resp.GetFailedCompares()) != 1 || resp.GetFailedCompares()[0] != 1 {
+		t.Fatalf("Txn = %v, %v, want compare 1 to fail", resp, err)
+	}
+	for nodeID, stored := range c.replicas(t, "b") {
+		if stored.GetVersion() != 0 {
+			t.Errorf("%s stores %v after a failed transaction", nodeID, stored)
+		}
+	}
+	if logged := c.loggedTxns(t); len(logged) > 0 {
+		t.Errorf("failed transaction is logged: %v", logged)
+	}
+}
+
+func TestTxnAbortsWhenAReplicaRefuses(t *testing.T) {
+	c := newTestCluster(t)
+	ctx := context.Background()
+	// Another transaction holds key a on node1 only
+	held := &pb.NodeTxnPrepareRequest{TxnId: 1, Records: []*pb.NodeRecord{{Key: []byte("a"), Value: []byte("held"), Version: 1}}}
+	if resp, err := c.nodes["node1"].TxnPrepare(ctx, held); err != nil || !resp.GetPrepared() {
+		t.Fatalf("TxnPrepare = %v, %v", resp, err)
+	}
+
+	txn := &pb.TxnRequest{Ops: []*pb.TxnOp{
+		{Type: pb.TxnOp_PUT, Key: "a", Value: "1"},
+		{Type: pb.TxnOp_PUT, Key: "b", Value: "2"},
+	}}
+	if _, err := c.Txn(ctx, txn); status.Code(err) != codes.Aborted {
+		t.Fatalf("Txn = %v, want Aborted", err)
+	}
+	for _, key := range []string{"a", "b"} {
+		for nodeID, stored := range c.replicas(t, key) {
+			if stored.GetVersion() != 0 {
+				t.Errorf("%s stores %q as %v after an aborted transaction", nodeID, key, stored)
+			}
+		}
+	}
+	if logged := c.loggedTxns(t); len(logged) > 0 {
+		t.Errorf("aborted transaction is still logged: %v", logged)
+	}
+
+	// The abort released the keys on the nodes that prepared
+	if _, err := c.nodes["node1"].TxnAbort(ctx, &pb.NodeTxnDecision{TxnId: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := c.Txn(ctx, txn); err != nil || !resp.GetSuccess() {
+		t.Fatalf("Txn after the abort = %v, %v", resp, err)
+	}
+}
+
+func TestRecoverTxnsCompletesCommittedAndAbortsUndecided(t *testing.T) {
+	c := newTestCluster(t)
+	ctx := context.Background()
+	committed := &pb.TxnLog{TxnId: c.clock.Next(), Committed: true}
+	committed.Records = []*pb.NodeRecord{{Key: []byte("a"), Value: []byte("1"), Version: committed.GetTxnId()}}
+	c.prepareOnAll(t, committed)
+	undecided := &pb.TxnLog{TxnId: c.clock.Next()}
+	undecided.Records = []*pb.NodeRecord{{Key: []byte("b"), Value: []byte("2"), Version: undecided.GetTxnId()}}
+	c.prepareOnAll(t, undecided)
+	// The coordinator crashed after logging both
+	for _, txn := range []*pb.TxnLog{committed, undecided} {
+		if err := c.logTxn(txn); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	resolved, err := c.recoverTxns(ctx)
+	if err != nil || resolved != 2 {
+		t.Fatalf("recoverTxns = %d, %v, want 2 resolved", resolved, err)
+	}
+	for nodeID, stored := range c.replicas(t, "a") {
+		if stored.GetVersion() != committed.GetTxnId() || string(stored.GetValue()) != "1" {
+			t.Errorf("%s stores %v for the committed transaction", nodeID, stored)
+		}
+	}
+	for nodeID, stored := range c.replicas(t, "b") {
+		if stored.GetVersion() != 0 {
+			t.Errorf("%s stores %v for the aborted transaction", nodeID, stored)
+		}
+	}
+	if logged := c.loggedTxns(t); len(logged) > 0 {
+		t.Errorf("recovered transactions are still logged: %v", logged)
+	}
+	if resp, err := c.Txn(ctx, &pb.TxnRequest{Ops: []*pb.TxnOp{{Type: pb.TxnOp_PUT, Key: "b", Value: "3"}}}); err != nil || !resp.GetSuccess() {
+		t.Fatalf("Txn on a key released by recovery = %v, %v", resp, err)
+	}
+}
+
+func TestStrongTxnReplayKeepsNewerWrites(t *testing.T) {
+	c := newStrongTestCluster(t, "a", "b")
+	ctx := context.Background()
+
+	resp, err := c.Txn(ctx, &pb.TxnRequest{Ops: []*pb.TxnOp{
+		{Type: pb.TxnOp_PUT, Key: "a", Value: "1"},
+		{Type: pb.TxnOp_PUT, Key: "b", Value: "1"},
+	}})
+	if err != nil || !resp.GetSuccess() {
+		t.Fatalf("Txn = %v, %v", resp, err)
+	}
+	for _, key := range []string{"a", "b"} {
+		if got, err := c.Get(ctx, &pb.GetRequest{Key: key}); err != nil || got.GetRevision() != resp.GetRevision() {
+			t.Fatalf("Get(%q) = %v, %v, want revision %d", key, got, err, resp.GetRevision())
+		}
+	}
+
+	later, err := c.Put(ctx, &pb.PutRequest{Key: "b", Value: "2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Recovery replays a transaction whose log outlived its writes
+	txn := &pb.TxnLog{TxnId: resp.GetRevision(), Committed: true, Records: []*pb.NodeRecord{
+		{Key: []byte("a"), Value: []byte("1"), Version: resp.GetRevision()},
+		{Key: []byte("b"), Value: []byte("1"), Version: resp.GetRevision()},
+	}}
+	if err := c.completeTxn(ctx, txn); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := c.Get(ctx, &pb.GetRequest{Key: "b"}); err != nil || got.GetValue() != "2" || got.GetRevision() != later.GetRevision() {
+		t.Errorf("Get(b) after the replay = %v, %v, want 2 at revision %d", got, err, later.GetRevision())
+	}
+	if got, err := c.Get(ctx, &pb.GetRequest{Key: "a"}); err != nil || got.GetRevision() != resp.GetRevision() {
+		t.Errorf("Get(a) after the replay = %v, %v, want revision %d", got, err, resp.GetRevision())
+	}
+}
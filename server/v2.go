@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+
+	pb "badies/proto/badiespb"
+	v2pb "badies/proto/badiesv2pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// serverV2 serves the binary-safe badies.v2 API. Go strings carry arbitrary
+// bytes, so it converts every call into the matching v1 call and runs it on
+// the same server; only the v1 wire format is limited to UTF-8.
+type serverV2 struct {
+	v2pb.UnimplementedKeyValServer
+	s *server
+}
+
+// errNotUTF8 refuses to return a value the v1 API cannot carry
+func errNotUTF8(key string) error {
+	return status.Errorf(codes.FailedPrecondition, "the value of key %q is not valid UTF-8, read it through the badies.v2 API", key)
+}
+
+func (v *serverV2) Put(ctx context.Context, req *v2pb.PutRequest) (*v2pb.PutResponse, error) {
+	resp, err := v.s.Put(ctx, &pb.PutRequest{Key: string(req.GetKey()), Value: string(req.GetValue()), TtlMs: req.GetTtlMs()})
+	if err != nil {
+		return nil, err
+	}
+	return &v2pb.PutResponse{Success: resp.GetSuccess(), Acks: resp.GetAcks(), Revision: resp.GetRevision()}, nil
+}
+
+func (v *serverV2) Get(ctx context.Context, req *v2pb.GetRequest) (*v2pb.GetResponse, error) {
+	resp, err := v.s.get(ctx, &pb.GetRequest{Key: string(req.GetKey()), Revision: req.GetRevision()})
+	if err != nil {
+		return nil, err
+	}
+	return &v2pb.GetResponse{Value: []byte(resp.GetValue()), Found: resp.GetFound(), Revision: resp.GetRevision()}, nil
+}
+
+func (v *serverV2) Delete(ctx context.Context, req *v2pb.DeleteRequest) (*v2pb.DeleteResponse, error) {
+	resp, err := v.s.Delete(ctx, &pb.DeleteRequest{Key: string(req.GetKey())})
+	if err != nil {
+		return nil, err
+	}
+	return &v2pb.DeleteResponse{Success: resp.GetSuccess(), Revision: resp.GetRevision()}, nil
+}
+
+func (v *serverV2) UpdateKey(ctx context.Context, req *v2pb.UpdateKeyRequest) (*v2pb.UpdateKeyResponse, error) {
+	resp, err := v.s.UpdateKey(ctx, &pb.UpdateKeyRequest{
+		OldKey:       string(req.GetOldKey()),
+		NewKey:       string(req.GetNewKey()),
+		FailIfExists: req.GetFailIfExists(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v2pb.UpdateKeyResponse{Success: resp.GetSuccess(), NewKeyExists: resp.GetNewKeyExists()}, nil
+}
+
+func (v *serverV2) UpdateValue(ctx context.Context, req *v2pb.UpdateValueRequest) (*v2pb.UpdateValueResponse, error) {
+	resp, err := v.s.updateValue(ctx, &pb.UpdateValueRequest{
+		Key:             string(req.GetKey()),
+		OldValue:        string(req.GetOldValue()),
+		NewValue:        string(req.GetNewValue()),
+		ExpectedVersion: req.GetExpectedVersion(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &v2pb.UpdateValueResponse{
+		Success:      resp.GetSuccess(),
+		CurrentValue: []byte(resp.GetCurrentValue()),
+		Version:      resp.GetVersion(),
+		Found:        resp.GetFound(),
+	}, nil
+}
+
+func (v *serverV2) Scan(req *v2pb.ScanRequest, stream v2pb.KeyVal_ScanServer) error {
+	v1 := &pb.ScanRequest{
+		StartKey: string(req.GetStartKey()),
+		EndKey:   string(req.GetEndKey()),
+		Prefix:   string(req.GetPrefix()),
+		Limit:    req.GetLimit(),
+		Reverse:  req.GetReverse(),
+	}
+	return v.s.scan(stream.Context(), v1, func(key, value []byte) (bool, error) {
+		return true, stream.Send(&v2pb.ScanResponse{Key: key, Value: value})
+	})
+}
+
+func (v *serverV2) Expire(ctx context.Context, req *v2pb.ExpireRequest) (*v2pb.ExpireResponse, error) {
+	resp, err := v.s.Expire(ctx, &pb.ExpireRequest{Key: string(req.GetKey()), TtlMs: req.GetTtlMs()})
+	if err != nil {
+		return nil, err
+	}
+	return &v2pb.ExpireResponse{Success: resp.GetSuccess()}, nil
+}
+
+func (v *serverV2) Persist(ctx context.Context, req *v2pb.PersistRequest) (*v2pb.PersistResponse, error) {
+	resp, err := v.s.Persist(ctx, &pb.PersistRequest{Key: string(req.GetKey())})
+	if err != nil {
+		return nil, err
+	}
+	return &v2pb.PersistResponse{Success: resp.GetSuccess()}, nil
+}
+
+func (v *serverV2) BatchPut(ctx context.Context, req *v2pb.BatchPutRequest) (*v2pb.BatchPutResponse, error) {
+	entries := make([]*pb.KeyValue, len(req.GetEntries()))
+	for i, entry := range req.GetEntries() {
+		entries[i] = &pb.KeyValue{Key: string(entry.GetKey()), Value: string(entry.GetValue())}
+	}
+	resp, err := v.s.BatchPut(ctx, &pb.BatchPutRequest{Entries: entries})
+	if err != nil {
+		return nil, err
+	}
+	return &v2pb.BatchPutResponse{Results: keyResultsV2(resp.GetResults())}, nil
+}
+
+func (v *serverV2) BatchGet(ctx context.Context, req *v2pb.BatchGetRequest) (*v2pb.BatchGetResponse, error) {
+	resp, err := v.s.batchGet(ctx, &pb.BatchGetRequest{Keys: stringsOf(req.GetKeys())})
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*v2pb.GetResult, len(resp.GetResults()))
+	for i, res := range resp.GetResults() {
+		results[i] = &v2pb.GetResult{
+			Key:   []byte(res.GetKey()),
+			Value: []byte(res.GetValue()),
+			Found: res.GetFound(),
+			Error: res.GetError(),
+		}
+	}
+	return &v2pb.BatchGetResponse{Results: results}, nil
+}
+
+func (v *serverV2) BatchDelete(ctx context.Context, req *v2pb.BatchDeleteRequest) (*v2pb.BatchDeleteResponse, error) {
+	resp, err := v.s.BatchDelete(ctx, &pb.BatchDeleteRequest{Keys: stringsOf(req.GetKeys())})
+	if err != nil {
+		return nil, err
+	}
+	return &v2pb.BatchDeleteResponse{Results: keyResultsV2(resp.GetResults())}, nil
+}
+
+func (v *serverV2) Watch(req *v2pb.WatchRequest, stream v2pb.KeyVal_WatchServer) error {
+	v1 := &pb.WatchRequest{Key: string(req.GetKey()), Prefix: req.GetPrefix(), StartRevision: req.GetStartRevision()}
+	return v.s.watchKeys(stream.Context(), v1, func(ev *pb.WatchEvent) error {
+		return stream.Send(&v2pb.WatchEvent{
+			Type:     v2pb.WatchEvent_Type(ev.GetType()),
+			Key:      []byte(ev.GetKey()),
+			Value:    []byte(ev.GetValue()),
+			Revision: ev.GetRevision(),
+		})
+	})
+}
+
+func (v *serverV2) History(ctx context.Context, req *v2pb.HistoryRequest) (*v2pb.HistoryResponse, error) {
+	resp, err := v.s.history(ctx, &pb.HistoryRequest{Key: string(req.GetKey()), Limit: req.GetLimit()})
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]*v2pb.KeyVersion, len(resp.GetVersions()))
+	for i, ver := range resp.GetVersions() {
+		versions[i] = &v2pb.KeyVersion{
+			Value:     []byte(ver.GetValue()),
+			Revision:  ver.GetRevision(),
+			Deleted:   ver.GetDeleted(),
+			ExpiresAt: ver.GetExpiresAt(),
+		}
+	}
+	return &v2pb.HistoryResponse{Versions: versions, CompactedRevision: resp.GetCompactedRevision()}, nil
+}
+
+func (v *serverV2) Txn(ctx context.Context, req *v2pb.TxnRequest) (*v2pb.TxnResponse, error) {
+	v1 := &pb.TxnRequest{}
+	for _, cmp := range req.GetCompares() {
+		v1.Compares = append(v1.Compares, &pb.TxnCompare{
+			Key:     string(cmp.GetKey()),
+			Target:  pb.TxnCompare_Target(cmp.GetTarget()),
+			Value:   string(cmp.GetValue()),
+			Version: cmp.GetVersion(),
+		})
+	}
+	for _, op := range req.GetOps() {
+		v1.Ops = append(v1.Ops, &pb.TxnOp{
+			Type:  pb.TxnOp_Type(op.GetType()),
+			Key:   string(op.GetKey()),
+			Value: string(op.GetValue()),
+			TtlMs: op.GetTtlMs(),
+		})
+	}
+	resp, err := v.s.Txn(ctx, v1)
+	if err != nil {
+		return nil, err
+	}
+	return &v2pb.TxnResponse{Success: resp.GetSuccess(), Revision: resp.GetRevision(), FailedCompares: resp.GetFailedCompares()}, nil
+}
+
+func stringsOf(keys [][]byte) []string {
+	strs := make([]string, len(keys))
+	for i, key := range keys {
+		strs[i] = string(key)
+	}
+	return strs
+}
+
+func keyResultsV2(results []*pb.KeyResult) []*v2pb.KeyResult {
+	out := make([]*v2pb.KeyResult, len(results))
+	for i, res := range results {
+		out[i] = &v2pb.KeyResult{Key: []byte(res.GetKey()), Success: res.GetSuccess(), Acks: res.GetAcks(), Error: res.GetError()}
+	}
+	return out
+}
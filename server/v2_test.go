@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	pb "badies/proto/badiespb"
+	v2pb "badies/proto/badiesv2pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestBinaryValuesAreRefusedByV1Reads(t *testing.T) {
+	c := newTestCluster(t)
+	v2 := &serverV2{s: c.server}
+	ctx := context.Background()
+	binary := []byte{0xff, 0xfe, 0x00}
+	if _, err := v2.Put(ctx, &v2pb.PutRequest{Key: []byte("bin"), Value: binary}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Put(ctx, &pb.PutRequest{Key: "text", Value: "plain"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Get(ctx, &pb.GetRequest{Key: "bin"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("v1 Get of a binary value = %v, want FailedPrecondition", err)
+	}
+	if _, err := c.History(ctx, &pb.HistoryRequest{Key: "bin"}); status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("v1 History of a binary value = %v, want FailedPrecondition", err)
+	}
+	_, err := c.UpdateValue(ctx, &pb.UpdateValueRequest{Key: "bin", OldValue: "other", NewValue: "new"})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("v1 UpdateValue failing against a binary value = %v, want FailedPrecondition", err)
+	}
+
+	batch, err := c.BatchGet(ctx, &pb.BatchGetRequest{Keys: []string{"bin", "text"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res := batch.GetResults()[0]; res.GetError() == "" || res.GetFound() || res.GetValue() != "" {
+		t.Errorf("v1 BatchGet result of a binary value = %v, want an error", res)
+	}
+	if res := batch.GetResults()[1]; res.GetError() != "" || res.GetValue() != "plain" {
+		t.Errorf("v1 BatchGet result of a text value = %v, want it read", res)
+	}
+
+	// The v2 API reads the same value
+	resp, err := v2.Get(ctx, &v2pb.GetRequest{Key: []byte("bin")})
+	if err != nil || !bytes.Equal(resp.GetValue(), binary) {
+		t.Errorf("v2 Get = %v, %v, want %x", resp, err, binary)
+	}
+	v2batch, err := v2.BatchGet(ctx, &v2pb.BatchGetRequest{Keys: [][]byte{[]byte("bin")}})
+	if err != nil || !bytes.Equal(v2batch.GetResults()[0].GetValue(), binary) {
+		t.Errorf("v2 BatchGet = %v, %v, want %x", v2batch, err, binary)
+	}
+	history, err := v2.History(ctx, &v2pb.HistoryRequest{Key: []byte("bin")})
+	if err != nil || len(history.GetVersions()) != 1 || !bytes.Equal(history.GetVersions()[0].GetValue(), binary) {
+		t.Errorf("v2 History = %v, %v, want the binary value", history, err)
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	pb "badies/proto/badiespb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultWatchHistory is how many recent events are kept for watchers
+	// resuming from a revision
+	defaultWatchHistory = 10000
+	// watchBuffer is how many events a watcher may fall behind before it is
+	// disconnected
+	watchBuffer = 1024
+)
+
+// watcher is one Watch stream's subscription
+type watcher struct {
+	key    string
+	prefix bool
+	events chan *pb.WatchEvent
+	// lagged is closed when the watcher fell behind and was dropped
+	lagged chan struct{}
+}
+
+func (w *watcher) matches(key string) bool {
+	if w.prefix {
+		return strings.HasPrefix(key, w.key)
+	}
+	return key == w.key
+}
+
+// watchHub fans the writes this coordinator applies out to watchers and
+// keeps the most recent events in a ring buffer so that a watcher can
+// resume where it left off. Revisions follow write versions but are bumped
+// where needed so that they increase in the order events are published.
+type watchHub struct {
+	mu       sync.Mutex
+	history  []*pb.WatchEvent // ring buffer of the latest events
+	next     int              // slot of the next event in history
+	last     uint64           // revision of the latest event
+	floor    uint64           // events before this revision are no longer available
+	watchers map[*watcher]struct{}
+	done     chan struct{} // closed when the server shuts down
+}
+
+// newWatchHub creates a hub keeping size events. Events from before start,
+// the revision at which the coordinator began publishing, cannot be
+// replayed.
+func newWatchHub(size int, start uint64) *watchHub {
+	return &watchHub{
+		history:  make([]*pb.WatchEvent, size),
+		last:     start,
+		floor:    start,
+		watchers: make(map[*watcher]struct{}),
+		done:     make(chan struct{}),
+	}
+}
+
+// close ends every watch stream so that a graceful stop does not wait on
+// them
+func (h *watchHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	select {
+	case <-h.done:
+	default:
+		close(h.done)
+	}
+}
+
+// publish records a write that reached its quorum and sends it to the
+// matching watchers
+func (h *watchHub) publish(rec *pb.NodeRecord) {
+	if h == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ev := &pb.WatchEvent{Type: pb.WatchEvent_PUT, Key: string(rec.GetKey()), Value: string(rec.GetValue())}
+	if rec.GetTombstone() {
+		ev.Type = pb.WatchEvent_DELETE
+		ev.Value = ""
+	}
+	ev.Revision = max(h.last+1, rec.GetVersion())
+	h.last = ev.Revision
+
+	if len(h.history) > 0 {
+		if evicted := h.history[h.next]; evicted != nil {
+			h.floor = evicted.GetRevision() + 1
+		}
+		h.history[h.next] = ev
+		h.next = (h.next + 1) % len(h.history)
+	}
+
+	for w := range h.watchers {
+		if !w.matches(ev.GetKey()) {
+			continue
+		}
+		select {
+		case w.events <- ev:
+		default:
+			delete(h.watchers, w)
+			close(w.lagged)
+		}
+	}
+}
+
+// subscribe registers a watcher and returns the buffered events it asked
+// to replay, along with the revision the watcher's events start after. Both
+// happen under the hub lock so no event is missed or sent twice.
+func (h *watchHub) subscribe(w *watcher, from uint64) ([]*pb.WatchEvent, uint64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var replay []*pb.WatchEvent
+	after := h.last
+	if from > 0 {
+		after = from - 1
+		if from < h.floor {
+			return nil, 0, status.Errorf(codes.OutOfRange,
+				"revision %d is no longer available, the oldest is %d", from, h.floor)
+		}
+		for i := range h.history {
+			ev := h.history[(h.next+i)%len(h.history)]
+			if ev != nil && ev.GetRevision() >= from && w.matches(ev.GetKey()) {
+				replay = append(replay, ev)
+			}
+		}
+	}
+	h.watchers[w] = struct{}{}
+	return replay, after, nil
+}
+
+func (h *watchHub) unsubscribe(w *watcher) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.watchers, w)
+}
+
+// Watch streams put and delete events for a key, or for every key under a
+// prefix, as this coordinator applies them. A client that reconnects passes
+// the revision after the last event it saw to receive the events it missed,
+// as long as they are still in the coordinator's history. Keys that simply
+// expire produce no event. Events that are not valid UTF-8 are left out,
+// since this API cannot carry them.
+func (s *server) Watch(req *pb.WatchRequest, stream pb.KeyVal_WatchServer) error {
+	return s.watchKeys(stream.Context(), req, func(ev *pb.WatchEvent) error {
+		if !utf8.ValidString(ev.GetKey()) || !utf8.ValidString(ev.GetValue()) {
+			return nil
+		}
+		return stream.Send(ev)
+	})
+}
+
+// watchKeys runs a Watch and hands every event to send
+func (s *server) watchKeys(ctx context.Context, req *pb.WatchRequest, send func(*pb.WatchEvent) error) error {
+	if req.GetKey() == "" && !req.GetPrefix() {
+		return status.Error(codes.InvalidArgument, "watch needs a key or a prefix")
+	}
+	w := &watcher{
+		key:    req.GetKey(),
+		prefix: req.GetPrefix(),
+		events: make(chan *pb.WatchEvent, watchBuffer),
+		lagged: make(chan struct{}),
+	}
+	replay, last, err := s.watch.subscribe(w, req.GetStartRevision())
+	if err != nil {
+		return err
+	}
+	defer s.watch.unsubscribe(w)
+
+	for _, ev := range replay {
+		if err := send(ev); err != nil {
+			return err
+		}
+		last = ev.GetRevision()
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.watch.done:
+			return status.Errorf(codes.Unavailable,
+				"server is shutting down; resume from revision %d", last+1)
+		case ev := <-w.events:
+			if err := send(ev); err != nil {
+				return err
+			}
+			last = ev.GetRevision()
+		case <-w.lagged:
+			// Deliver what was buffered before reporting the lag
+			for {
+				select {
+				case ev := <-w.events:
+					if err := send(ev); err != nil {
+						return err
+					}
+					last = ev.GetRevision()
+				default:
+					return status.Errorf(codes.ResourceExhausted,
+						"watcher fell behind; resume from revision %d", last+1)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// Command skew reports how evenly each partitioner and hash spreads a set of
+// keys over a cluster, and how many keys move when a node joins or leaves.
+//
+//	go run ./skew --nodes=5 --keys=100000
+//	go run ./skew --keys-file=keys.txt --partitioners=consistent,range
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"badies/router"
+)
+
+func main() {
+	nodeCount := flag.Int("nodes", 5, "number of nodes, named node1, node2 and so on")
+	weights := flag.String("weights", "", "comma separated node weights as id=weight (default 1)")
+	replicationFactor := flag.Int("replication-factor", 3, "number of nodes each key is stored on")
+	vnodes := flag.Int("vnodes", 3, "virtual nodes per unit of node weight, for consistent and range")
+	partitions := flag.Int("partitions", router.DefaultPartitions, "fixed partitions, for jump and rendezvous")
+	partitioners := flag.String("partitioners", "consistent,jump,rendezvous,range", "comma separated partitioners to report on")
+	hashes := flag.String("hashes", "crc32,fnv,murmur3,xxhash", "comma separated hashes to report on; range ignores them")
+	keyCount := flag.Int("keys", 100000, "number of generated keys")
+	keyFormat := flag.String("key-format", "key-%d", "format of the generated keys, given the key number")
+	keysFile := flag.String("keys-file", "", "read one key per line from this file instead of generating them, - for stdin")
+	flag.Parse()
+
+	if *nodeCount < 1 {
+		log.Fatalf("--nodes must be at least 1, got %d", *nodeCount)
+	}
+	nodeWeights, err := parseWeights(*weights)
+	if err != nil {
+		log.Fatalf("Invalid --weights: %v", err)
+	}
+	keys, err := loadKeys(*keysFile, *keyCount, *keyFormat)
+	if err != nil {
+		log.Fatalf("Failed to read keys: %v", err)
+	}
+	if len(keys) == 0 {
+		log.Fatalf("No keys to place")
+	}
+
+	nodes := make([]string, *nodeCount)
+	for i := range nodes {
+		nodes[i] = "node" + strconv.Itoa(i+1)
+	}
+
+	fmt.Printf("%d keys on %d nodes, replication factor %d. Skew is a node's share of primaries (or replicas)\n", len(keys), len(nodes), *replicationFactor)
+	fmt.Printf("relative to its weight; moved is the share of keys whose replicas change when a node of weight 1\n")
+	fmt.Printf("joins (ideally %.1f%%) or the first node leaves. Hotspot is the largest share of keys at one position,\n", 100*float64(min(*replicationFactor, len(nodes)+1))/float64(len(nodes)+1))
+	fmt.Printf("which no partitioner can split across nodes.\n\n")
+
+	var notes []string
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "partitioner\thash\tmax skew\tmin skew\tstddev\treplica max skew\tmoved on join\tmoved on leave\thotspot\t")
+	for _, name := range splitList(*partitioners) {
+		hashList := splitList(*hashes)
+		if name == router.Range {
+			hashList = []string{""}
+		}
+		for _, hash := range hashList {
+			p, err := router.NewPartitioner(router.PartitionerConfig{Name: name, Hash: hash, VNodes: *vnodes, Partitions: *partitions})
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+			r := measure(p, nodes, nodeWeights, *replicationFactor, keys)
+			fmt.Fprintf(w, "%s\t%s\t%+.1f%%\t%+.1f%%\t%.1f%%\t%+.1f%%\t%.1f%%\t%.1f%%\t%.1f%%\t\n",
+				name, p.Config().Hash, r.maxSkew, r.minSkew, r.stddev, r.replicaMaxSkew, r.movedOnJoin, r.movedOnLeave, r.hotspot)
+			if name == router.Range && r.hotspot > 100/float64(len(nodes)) {
+				notes = append(notes, fmt.Sprintf("%.1f%% of the keys start with %q and share one position, more than a node's share: "+
+					"range places keys by their first %d bytes only, so keys with a longer common prefix stay on one node",
+					r.hotspot, r.hotspotPrefix, router.OrderedPositionBytes))
+			}
+		}
+	}
+	w.Flush()
+	for _, note := range notes {
+		fmt.Printf("\n%s\n", note)
+	}
+}
+
+// report is what measure found for one partitioner, in percent
+type report struct {
+	maxSkew, minSkew, stddev float64
+	replicaMaxSkew           float64
+	movedOnJoin              float64
+	movedOnLeave             float64
+	hotspot                  float64 // largest share of keys at one position
+	hotspotPrefix            string  // leading bytes of a key at that position
+}
+
+// measure places keys on a ring of nodes and on the same ring with a node
+// added and with the first node removed
+func measure(p router.Partitioner, nodes []string, weights map[string]int, rf int, keys []string) report {
+	ring := router.NewPartitionedRing(p, rf)
+	total := 0
+	for _, node := range nodes {
+		weight := max(weights[node], 1)
+		ring.AddNodeWithWeight(node, weight)
+		total += weight
+	}
+	joined := ring.Clone()
+	joined.AddNode("joining")
+	left := ring.Clone()
+	left.RemoveNode(nodes[0])
+
+	primaries := make(map[string]int)
+	replicas := make(map[string]int)
+	positions := make(map[uint32]int)
+	var r report
+	top := 0
+	for _, key := range keys {
+		position := ring.Position(key)
+		positions[position]++
+		if positions[position] > top {
+			top = positions[position]
+			r.hotspotPrefix = key[:min(len(key), router.OrderedPositionBytes)]
+		}
+		owners := ring.GetNodes(key)
+		primaries[owners[0]]++
+		for _, node := range owners {
+			replicas[node]++
+		}
+		if !sameNodes(owners, joined.GetNodes(key)) {
+			r.movedOnJoin++
+		}
+		if !sameNodes(owners, left.GetNodes(key)) {
+			r.movedOnLeave++
+		}
+	}
+	r.movedOnJoin = 100 * r.movedOnJoin / float64(len(keys))
+	r.movedOnLeave = 100 * r.movedOnLeave / float64(len(keys))
+	r.hotspot = 100 * float64(top) / float64(len(keys))
+
+	r.maxSkew, r.minSkew = math.Inf(-1), math.Inf(1)
+	r.replicaMaxSkew = math.Inf(-1)
+	replicaCount := 0
+	for _, count := range replicas {
+		replicaCount += count
+	}
+	var squares float64
+	for _, node := range nodes {
+		share := float64(max(weights[node], 1)) / float64(total)
+		skew := 100 * (float64(primaries[node])/(share*float64(len(keys))) - 1)
+		r.maxSkew = max(r.maxSkew, skew)
+		r.minSkew = min(r.minSkew, skew)
+		squares += skew * skew
+		replicaSkew := 100 * (float64(replicas[node])/(share*float64(replicaCount)) - 1)
+		r.replicaMaxSkew = max(r.replicaMaxSkew, replicaSkew)
+	}
+	r.stddev = math.Sqrt(squares / float64(len(nodes)))
+	return r
+}
+
+// sameNodes reports whether two replica sets hold the same nodes
+func sameNodes(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, node := range a {
+		if !slices.Contains(b, node) {
+			return false
+		}
+	}
+	return true
+}
+
+// loadKeys reads keys from path, or generates count of them from format
+func loadKeys(path string, count int, format string) ([]string, error) {
+	if path == "" {
+		keys := make([]string, count)
+		for i := range keys {
+			keys[i] = fmt.Sprintf(format, i)
+		}
+		return keys, nil
+	}
+
+	var in io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		in = f
+	}
+	var keys []string
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		keys = append(keys, scanner.Text())
+	}
+	return keys, scanner.Err()
+}
+
+// parseWeights parses "id=weight,..." into a map
+func parseWeights(s string) (map[string]int, error) {
+	weights := make(map[string]int)
+	for _, entry := range splitList(s) {
+		id, value, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q is not id=weight", entry)
+		}
+		weight, err := strconv.Atoi(value)
+		if err != nil || weight < 1 {
+			return nil, fmt.Errorf("weight of %s must be a positive integer, got %q", id, value)
+		}
+		weights[id] = weight
+	}
+	return weights, nil
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	pb "badies/proto/badiespb"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// lockKeys takes the locks of several keys in a fixed order and returns a
+// function releasing them
+func (s *Server) lockKeys(keys [][]byte) func() {
+	indexes := make([]int, 0, len(keys))
+	for _, key := range keys {
+		indexes = append(indexes, s.lockIndex(key))
+	}
+	slices.Sort(indexes)
+	indexes = slices.Compact(indexes)
+	for _, i := range indexes {
+		s.locks[i].Lock()
+	}
+	return func() {
+		for _, i := range indexes {
+			s.locks[i].Unlock()
+		}
+	}
+}
+
+// applyBatch is apply for many records at once: every record newer than the
+// stored version of its key is written in a single LevelDB batch. Later
+// records for the same key are compared against earlier ones in the batch.
+// Replaced records are kept in the history first.
+func (s *Server) applyBatch(recs []*pb.NodeRecord) ([]bool, error) {
+	keys := make([][]byte, len(recs))
+	for i, rec := range recs {
+		keys[i] = rec.GetKey()
+	}
+	unlock := s.lockKeys(keys)
+	defer unlock()
+
+	batch := new(leveldb.Batch)
+	applied := make([]bool, len(recs))
+	latest := make(map[string]Record) // records written by this batch
+	var replacedKeys [][]byte
+	var replaced []Record
+	for i, rec := range recs {
+		current, found := latest[string(rec.GetKey())]
+		if !found {
+			var err error
+			current, found, err = s.readRecord(rec.GetKey())
+			if err != nil {
+				return nil, err
+			}
+		}
+		if found && current.Version >= rec.GetVersion() {
+			continue
+		}
+		if found {
+			replacedKeys = append(replacedKeys, rec.GetKey())
+			replaced = append(replaced, current)
+		}
+		next := fromNodeRecord(rec)
+		batch.Put(rec.GetKey(), next.encode())
+		latest[string(rec.GetKey())] = next
+		applied[i] = true
+	}
+	if batch.Len() == 0 {
+		return applied, nil
+	}
+	if len(replaced) > 0 {
+		if err := s.History.SaveBatch(replacedKeys, replaced); err != nil {
+			return nil, err
+		}
+	}
+	if err := s.db.Write(batch, nil); err != nil {
+		return nil, err
+	}
+	return applied, nil
+}
+
+// WriteBatch applies versioned puts and tombstones in one write batch
+func (s *Server) WriteBatch(ctx context.Context, req *pb.NodeWriteBatchRequest) (*pb.NodeWriteBatchResponse, error) {
+	applied, err := s.applyBatch(req.GetRecords())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: write batch failed: %v", s.nodeID, err)
+	}
+	return &pb.NodeWriteBatchResponse{Applied: applied}, nil
+}
+
+// GetBatch reads several keys at once
+func (s *Server) GetBatch(ctx context.Context, req *pb.NodeGetBatchRequest) (*pb.NodeGetBatchResponse, error) {
+	records := make([]*pb.NodeGetResponse, 0, len(req.GetKeys()))
+	for _, key := range req.GetKeys() {
+		rec, found, err := s.readRecord(key)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "node %s: get batch failed: %v", s.nodeID, err)
+		}
+		records = append(records, toGetResponse(rec, found, time.Now()))
+	}
+	return &pb.NodeGetBatchResponse{Records: records}, nil
+}
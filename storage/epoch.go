@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"badies/router"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// epochKey stores the highest ring epoch in the history database, which
+// unlike the data has room for keys of the node's own
+var epochKey = []byte("e")
+
+// loadEpoch restores the epoch seen before the node restarted
+func (s *Server) loadEpoch() error {
+	data, err := s.History.db.Get(epochKey, nil)
+	switch {
+	case err == nil && len(data) == 8:
+		s.epoch.Store(binary.BigEndian.Uint64(data))
+	case err != nil && !errors.Is(err, leveldb.ErrNotFound):
+		return fmt.Errorf("failed to load the ring epoch: %v", err)
+	}
+	return nil
+}
+
+// advanceEpoch raises the epoch to epoch, writing it to disk before any call
+// is let through at it, so a restarted node still refuses older rings
+func (s *Server) advanceEpoch(epoch uint64) error {
+	s.epochMu.Lock()
+	defer s.epochMu.Unlock()
+	if epoch <= s.epoch.Load() {
+		return nil
+	}
+	if err := s.History.db.Put(epochKey, binary.BigEndian.AppendUint64(nil, epoch), syncWrite); err != nil {
+		return status.Errorf(codes.Internal, "node %s: storing ring epoch %d failed: %v", s.nodeID, epoch, err)
+	}
+	s.epoch.Store(epoch)
+	return nil
+}
+
+// checkEpoch refuses a call from a coordinator whose ring is older than one
+// this node has already seen, so that a coordinator restored from a stale
+// topology cannot write keys to nodes that no longer own them. Calls
+// without an epoch, such as those between nodes, are let through.
+func (s *Server) checkEpoch(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(router.EpochHeader)
+	if len(values) == 0 {
+		return nil
+	}
+	epoch, err := strconv.ParseUint(values[0], 10, 64)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "node %s: invalid ring epoch %q", s.nodeID, values[0])
+	}
+	seen := s.epoch.Load()
+	if epoch < seen {
+		return status.Errorf(codes.FailedPrecondition,
+			"node %s: ring epoch %d is stale, the cluster is at epoch %d", s.nodeID, epoch, seen)
+	}
+	if epoch == seen {
+		return nil
+	}
+	return s.advanceEpoch(epoch)
+}
+
+// UnaryEpochInterceptor checks the ring epoch of unary calls
+func (s *Server) UnaryEpochInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := s.checkEpoch(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// StreamEpochInterceptor checks the ring epoch of streaming calls
+func (s *Server) StreamEpochInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.checkEpoch(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	pb "badies/proto/badiespb"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// DefaultHealthCheckInterval is how often a node checks its databases
+const DefaultHealthCheckInterval = 5 * time.Second
+
+// healthProbeKey is read and written to check that a database works. The
+// write puts and deletes it in one batch, so it is never visible.
+var healthProbeKey = []byte("\x00health-probe")
+
+// CheckHealth reads from and writes to every database of the node and
+// returns the first failure
+func (s *Server) CheckHealth() error {
+	dbs := []struct {
+		name string
+		db   *leveldb.DB
+	}{
+		{"data", s.db},
+		{"hints", s.Hints.db},
+		{"history", s.History.db},
+		{"transactions", s.txns.db},
+	}
+	for _, d := range dbs {
+		if _, err := d.db.Get(healthProbeKey, nil); err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+			return fmt.Errorf("%s database read failed: %v", d.name, err)
+		}
+		batch := new(leveldb.Batch)
+		batch.Put(healthProbeKey, nil)
+		batch.Delete(healthProbeKey)
+		if err := d.db.Write(batch, nil); err != nil {
+			return fmt.Errorf("%s database write failed: %v", d.name, err)
+		}
+	}
+	return nil
+}
+
+// ReportHealth serves the result of CheckHealth through the grpc.health.v1
+// service every interval, and reports the node as not serving once ctx is
+// cancelled
+func (s *Server) ReportHealth(ctx context.Context, hs *health.Server, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	healthy := true
+	for {
+		err := s.CheckHealth()
+		switch {
+		case err != nil && healthy:
+			log.Printf("Node %s is unhealthy: %v", s.nodeID, err)
+		case err == nil && !healthy:
+			log.Printf("Node %s is healthy again", s.nodeID)
+		}
+		healthy = err == nil
+		servingStatus := healthpb.HealthCheckResponse_SERVING
+		if !healthy {
+			servingStatus = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+		hs.SetServingStatus("", servingStatus)
+		hs.SetServingStatus(pb.StorageNode_ServiceDesc.ServiceName, servingStatus)
+
+		select {
+		case <-ctx.Done():
+			hs.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,204 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+const (
+	// DefaultMaxHints bounds the number of hints a node holds
+	DefaultMaxHints = 100000
+	// DefaultHintTTL is how long a hint is kept before it is dropped
+	DefaultHintTTL = 3 * time.Hour
+)
+
+// ErrHintStoreFull is returned when the hint store reached its limit
+var ErrHintStoreFull = errors.New("hint store is full")
+
+// Hint is a write this node holds for an unreachable replica
+type Hint struct {
+	Target    string
+	Key       []byte
+	Record    Record
+	ExpiresAt time.Time
+}
+
+// HintStore keeps hints in their own LevelDB next to the node's data. Keys
+// are target + 0x00 + key, so there is at most one hint per key and target
+// and the newest write wins.
+type HintStore struct {
+	// MaxHints bounds the number of stored hints
+	MaxHints int
+	// TTL is how long a hint is kept before it expires
+	TTL time.Duration
+
+	db    *leveldb.DB
+	mu    sync.Mutex
+	count int
+}
+
+// OpenHintStore opens or creates the hint database at path
+func OpenHintStore(path string) (*HintStore, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open hint store at %s: %v", path, err)
+	}
+	hs := &HintStore{MaxHints: DefaultMaxHints, TTL: DefaultHintTTL, db: db}
+
+	iter := db.NewIterator(nil, nil)
+	for iter.Next() {
+		hs.count++
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load hint store at %s: %v", path, err)
+	}
+	return hs, nil
+}
+
+// Close closes the hint database
+func (hs *HintStore) Close() error {
+	return hs.db.Close()
+}
+
+// Count returns the number of stored hints
+func (hs *HintStore) Count() int {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	return hs.count
+}
+
+func hintKey(target string, key []byte) []byte {
+	return append(append([]byte(target), 0), key...)
+}
+
+func splitHintKey(hk []byte) (string, []byte, error) {
+	i := bytes.IndexByte(hk, 0)
+	if i < 0 {
+		return "", nil, fmt.Errorf("malformed hint key %q", hk)
+	}
+	return string(hk[:i]), append([]byte(nil), hk[i+1:]...), nil
+}
+
+// encodeHint stores the expiry in front of the encoded record
+func encodeHint(expiresAt time.Time, rec Record) []byte {
+	buf := make([]byte, 8, 8+recordHeaderSize+len(rec.Value))
+	binary.BigEndian.PutUint64(buf, uint64(expiresAt.UnixNano()))
+	return append(buf, rec.encode()...)
+}
+
+func decodeHint(data []byte) (time.Time, Record, error) {
+	if len(data) < 8 {
+		return time.Time{}, Record{}, fmt.Errorf("hint too short: %d bytes", len(data))
+	}
+	expiresAt := time.Unix(0, int64(binary.BigEndian.Uint64(data[:8])))
+	rec, err := decodeRecord(data[8:])
+	return expiresAt, rec, err
+}
+
+// Store keeps rec for target. An existing hint for the same key is only
+// replaced by a newer version.
+func (hs *HintStore) Store(target string, key []byte, rec Record) error {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	hk := hintKey(target, key)
+	existing, err := hs.db.Get(hk, nil)
+	switch {
+	case err == nil:
+		if _, old, err := decodeHint(existing); err == nil && old.Version >= rec.Version {
+			return nil
+		}
+	case errors.Is(err, leveldb.ErrNotFound):
+		if hs.count >= hs.MaxHints {
+			return ErrHintStoreFull
+		}
+		hs.count++
+	default:
+		return err
+	}
+	return hs.db.Put(hk, encodeHint(time.Now().Add(hs.TTL), rec), nil)
+}
+
+// Each calls fn for every live hint. Expired hints are dropped on the way.
+func (hs *HintStore) Each(fn func(Hint) error) error {
+	now := time.Now()
+	var expired [][]byte
+
+	iter := hs.db.NewIterator(nil, nil)
+	for iter.Next() {
+		expiresAt, rec, err := decodeHint(iter.Value())
+		if err != nil {
+			iter.Release()
+			return err
+		}
+		if now.After(expiresAt) {
+			expired = append(expired, append([]byte(nil), iter.Key()...))
+			continue
+		}
+		target, key, err := splitHintKey(iter.Key())
+		if err != nil {
+			iter.Release()
+			return err
+		}
+		if err := fn(Hint{Target: target, Key: key, Record: rec, ExpiresAt: expiresAt}); err != nil {
+			iter.Release()
+			return err
+		}
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	for _, hk := range expired {
+		// The hint may have been refreshed by a newer write in the meantime
+		data, err := hs.db.Get(hk, nil)
+		if errors.Is(err, leveldb.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if expiresAt, _, err := decodeHint(data); err == nil && !now.After(expiresAt) {
+			continue
+		}
+		if err := hs.db.Delete(hk, nil); err != nil {
+			return err
+		}
+		hs.count--
+	}
+	return nil
+}
+
+// Delete removes the hint for key and target if it still holds version
+func (hs *HintStore) Delete(target string, key []byte, version uint64) (bool, error) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	hk := hintKey(target, key)
+	data, err := hs.db.Get(hk, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if _, rec, err := decodeHint(data); err == nil && rec.Version != version {
+		return false, nil
+	}
+	if err := hs.db.Delete(hk, nil); err != nil {
+		return false, err
+	}
+	hs.count--
+	return true, nil
+}
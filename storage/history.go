@@ -0,0 +1,400 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	pb "badies/proto/badiespb"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultHistoryRetention is how long a replaced version stays readable
+	DefaultHistoryRetention = 24 * time.Hour
+	// historyCompactInterval is the time between compactions of the history
+	historyCompactInterval = 10 * time.Minute
+)
+
+// ErrCompacted is returned for reads at a revision whose versions were
+// already compacted away
+var ErrCompacted = errors.New("revision has been compacted")
+
+// compactedKey stores the revision below which history is gone. History
+// entries are prefixed with 'v' and revoked versions with 'x', so neither
+// collides with it or with epochKey.
+var compactedKey = []byte("c")
+
+// HistoryStore keeps the versions of keys that were replaced by newer
+// writes in their own LevelDB next to the node's data. Keys are 'v' +
+// len(key) + key + version, so the versions of a key are adjacent and
+// ordered oldest first.
+type HistoryStore struct {
+	// Retention is how long a version stays readable after it was replaced,
+	// 0 to keep every version
+	Retention time.Duration
+
+	db        *leveldb.DB
+	mu        sync.Mutex
+	compacted uint64
+}
+
+// OpenHistoryStore opens or creates the history database at path
+func OpenHistoryStore(path string) (*HistoryStore, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history store at %s: %v", path, err)
+	}
+	hs := &HistoryStore{Retention: DefaultHistoryRetention, db: db}
+	data, err := db.Get(compactedKey, nil)
+	switch {
+	case err == nil && len(data) == 8:
+		hs.compacted = binary.BigEndian.Uint64(data)
+	case err != nil && !errors.Is(err, leveldb.ErrNotFound):
+		db.Close()
+		return nil, fmt.Errorf("failed to load history store at %s: %v", path, err)
+	}
+	return hs, nil
+}
+
+// Close closes the history database
+func (hs *HistoryStore) Close() error {
+	return hs.db.Close()
+}
+
+// Compacted returns the oldest revision that can still be read
+func (hs *HistoryStore) Compacted() uint64 {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	return hs.compacted
+}
+
+func historyPrefix(key []byte) []byte {
+	buf := make([]byte, 5, 5+len(key)+8)
+	buf[0] = 'v'
+	binary.BigEndian.PutUint32(buf[1:], uint32(len(key)))
+	return append(buf, key...)
+}
+
+func historyKey(key []byte, version uint64) []byte {
+	return binary.BigEndian.AppendUint64(historyPrefix(key), version)
+}
+
+// splitHistoryKey returns the key and version of a history entry
+func splitHistoryKey(hk []byte) ([]byte, uint64, error) {
+	if len(hk) < 13 || hk[0] != 'v' {
+		return nil, 0, fmt.Errorf("malformed history key %q", hk)
+	}
+	n := int(binary.BigEndian.Uint32(hk[1:5]))
+	if len(hk) != 5+n+8 {
+		return nil, 0, fmt.Errorf("malformed history key %q", hk)
+	}
+	return hk[5 : 5+n], binary.BigEndian.Uint64(hk[5+n:]), nil
+}
+
+// revokedKey is the key marking a reverted version: 'x' + len(key) + key +
+// version, laid out like a history entry
+func revokedKey(key []byte, version uint64) []byte {
+	rk := historyKey(key, version)
+	rk[0] = 'x'
+	return rk
+}
+
+// Revoke records that version of key was reverted, so that it is never
+// written again, not even by repair from a replica that still has it
+func (hs *HistoryStore) Revoke(key []byte, version uint64) error {
+	return hs.db.Put(revokedKey(key, version), nil, nil)
+}
+
+// Revoked reports whether version of key was reverted
+func (hs *HistoryStore) Revoked(key []byte, version uint64) (bool, error) {
+	_, err := hs.db.Get(revokedKey(key, version), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// Save keeps rec, a version of key that is about to be replaced
+func (hs *HistoryStore) Save(key []byte, rec Record) error {
+	return hs.db.Put(historyKey(key, rec.Version), rec.encode(), nil)
+}
+
+// SaveBatch keeps the versions in recs, indexed like keys, in one write
+func (hs *HistoryStore) SaveBatch(keys [][]byte, recs []Record) error {
+	batch := new(leveldb.Batch)
+	for i, rec := range recs {
+		batch.Put(historyKey(keys[i], rec.Version), rec.encode())
+	}
+	return hs.db.Write(batch, nil)
+}
+
+// At returns the newest kept version of key that is not newer than revision
+func (hs *HistoryStore) At(key []byte, revision uint64) (Record, bool, error) {
+	iter := hs.db.NewIterator(util.BytesPrefix(historyPrefix(key)), nil)
+	defer iter.Release()
+
+	var ok bool
+	if revision == ^uint64(0) || !iter.Seek(historyKey(key, revision+1)) {
+		ok = iter.Last()
+	} else {
+		ok = iter.Prev()
+	}
+	if !ok {
+		return Record{}, false, iter.Error()
+	}
+	rec, err := decodeRecord(iter.Value())
+	if err != nil {
+		return Record{}, false, fmt.Errorf("corrupt history entry for key %q: %v", key, err)
+	}
+	return rec, true, nil
+}
+
+// List returns up to limit kept versions of key, newest first. A limit of 0
+// returns all of them.
+func (hs *HistoryStore) List(key []byte, limit int) ([]Record, error) {
+	iter := hs.db.NewIterator(util.BytesPrefix(historyPrefix(key)), nil)
+	defer iter.Release()
+
+	var recs []Record
+	for ok := iter.Last(); ok && (limit == 0 || len(recs) < limit); ok = iter.Prev() {
+		rec, err := decodeRecord(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("corrupt history entry for key %q: %v", key, err)
+		}
+		recs = append(recs, rec)
+	}
+	return recs, iter.Error()
+}
+
+// Drop removes every kept version of key
+func (hs *HistoryStore) Drop(key []byte) error {
+	batch := new(leveldb.Batch)
+	iter := hs.db.NewIterator(util.BytesPrefix(historyPrefix(key)), nil)
+	for iter.Next() {
+		batch.Delete(append([]byte(nil), iter.Key()...))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return hs.db.Write(batch, nil)
+}
+
+// setCompacted records that revisions below before can no longer be read
+func (hs *HistoryStore) setCompacted(before uint64) error {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+	if before <= hs.compacted {
+		return nil
+	}
+	if err := hs.db.Put(compactedKey, binary.BigEndian.AppendUint64(nil, before), nil); err != nil {
+		return err
+	}
+	hs.compacted = before
+	return nil
+}
+
+// readAt returns the version of key that was current at revision. Reads
+// below the compacted revision fail with ErrCompacted.
+func (s *Server) readAt(key []byte, revision uint64) (Record, bool, error) {
+	if revision < s.History.Compacted() {
+		return Record{}, false, ErrCompacted
+	}
+	mu := s.keyLock(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	current, found, err := s.readRecord(key)
+	if err != nil || (found && current.Version <= revision) {
+		return current, found, err
+	}
+	return s.History.At(key, revision)
+}
+
+// CompactHistory drops the versions that were replaced before the revision
+// before, so that every read at before or later can still be answered. It
+// returns the number of versions dropped.
+func (s *Server) CompactHistory(before uint64) (int, error) {
+	if err := s.History.setCompacted(before); err != nil {
+		return 0, err
+	}
+
+	type entry struct {
+		hk      []byte
+		key     []byte
+		version uint64
+		expires int64
+	}
+	var stale [][]byte
+	var last *entry // newest entry of the previous key
+	flush := func() error {
+		if last == nil {
+			return nil
+		}
+		// The newest kept version was replaced by the stored record. If the
+		// key is gone it was swept, so the version ended when it expired.
+		current, found, err := s.readRecord(last.key)
+		if err != nil {
+			return err
+		}
+		replacedAt := last.version
+		switch {
+		case found:
+			replacedAt = current.Version
+		case last.expires != 0:
+			replacedAt = uint64(last.expires)
+		}
+		if replacedAt < before {
+			stale = append(stale, last.hk)
+		}
+		return nil
+	}
+
+	iter := s.History.db.NewIterator(util.BytesPrefix([]byte("v")), nil)
+	for iter.Next() {
+		key, version, err := splitHistoryKey(iter.Key())
+		if err != nil {
+			iter.Release()
+			return 0, err
+		}
+		rec, err := decodeRecord(iter.Value())
+		if err != nil {
+			iter.Release()
+			return 0, fmt.Errorf("corrupt history entry for key %q: %v", key, err)
+		}
+		e := &entry{hk: append([]byte(nil), iter.Key()...), key: append([]byte(nil), key...), version: version, expires: rec.ExpiresAt}
+		if last != nil && string(last.key) == string(e.key) {
+			// The previous version was replaced by this one
+			if version < before {
+				stale = append(stale, last.hk)
+			}
+		} else if err := flush(); err != nil {
+			iter.Release()
+			return 0, err
+		}
+		last = e
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+	if err := flush(); err != nil {
+		return 0, err
+	}
+
+	// Revocations only have to outlive the repairs of their version
+	revoked := s.History.db.NewIterator(util.BytesPrefix([]byte("x")), nil)
+	for revoked.Next() {
+		rk := revoked.Key()
+		if len(rk) >= 8 && binary.BigEndian.Uint64(rk[len(rk)-8:]) < before {
+			stale = append(stale, append([]byte(nil), rk...))
+		}
+	}
+	revoked.Release()
+	if err := revoked.Error(); err != nil {
+		return 0, err
+	}
+
+	batch := new(leveldb.Batch)
+	for _, hk := range stale {
+		batch.Delete(hk)
+	}
+	if err := s.History.db.Write(batch, nil); err != nil {
+		return 0, err
+	}
+	return len(stale), nil
+}
+
+// RunHistoryCompaction compacts the history every historyCompactInterval,
+// keeping replaced versions for History.Retention, until ctx is cancelled
+func (s *Server) RunHistoryCompaction(ctx context.Context) {
+	if s.History.Retention <= 0 {
+		return
+	}
+	ticker := time.NewTicker(historyCompactInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			before := uint64(time.Now().Add(-s.History.Retention).UnixNano())
+			n, err := s.CompactHistory(before)
+			if err != nil {
+				log.Printf("Node %s: history compaction failed: %v", s.nodeID, err)
+			} else if n > 0 {
+				log.Printf("Node %s compacted %d old versions", s.nodeID, n)
+			}
+		}
+	}
+}
+
+// Versions lists the current record of a key followed by its kept versions,
+// newest first
+func (s *Server) Versions(ctx context.Context, req *pb.NodeVersionsRequest) (*pb.NodeVersionsResponse, error) {
+	key := req.GetKey()
+	limit := int(req.GetLimit())
+	current, found, err := s.readRecord(key)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: history failed: %v", s.nodeID, err)
+	}
+	resp := &pb.NodeVersionsResponse{CompactedRevision: s.History.Compacted()}
+	if found {
+		resp.Records = append(resp.Records, toNodeRecord(key, current))
+		if limit > 0 {
+			limit--
+			if limit == 0 {
+				return resp, nil
+			}
+		}
+	}
+	kept, err := s.History.List(key, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: history failed: %v", s.nodeID, err)
+	}
+	for _, rec := range kept {
+		resp.Records = append(resp.Records, toNodeRecord(key, rec))
+	}
+	return resp, nil
+}
+
+// KeepVersions adds versions of a key that another node replaced to the
+// history, so that reads at older revisions survive a rebalance. Versions
+// not older than the stored record are skipped, since they were never
+// replaced here.
+func (s *Server) KeepVersions(ctx context.Context, req *pb.NodeKeepVersionsRequest) (*pb.NodeKeepVersionsResponse, error) {
+	key := req.GetKey()
+	mu := s.keyLock(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	current, found, err := s.readRecord(key)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: keeping versions failed: %v", s.nodeID, err)
+	}
+	var keys [][]byte
+	var recs []Record
+	for _, v := range req.GetVersions() {
+		if found && v.GetVersion() >= current.Version {
+			continue
+		}
+		if _, err := s.History.db.Get(historyKey(key, v.GetVersion()), nil); err == nil {
+			continue
+		}
+		keys = append(keys, key)
+		recs = append(recs, fromNodeRecord(v))
+	}
+	if err := s.History.SaveBatch(keys, recs); err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: keeping versions failed: %v", s.nodeID, err)
+	}
+	return &pb.NodeKeepVersionsResponse{Kept: int32(len(recs))}, nil
+}
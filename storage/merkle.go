@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+
+	"badies/router"
+)
+
+// merkleTree summarizes the records of a hash range. The range is split into
+// 2^depth equal buckets; a leaf is the XOR of the digests of the records in
+// its bucket, so it does not depend on iteration order, and every inner node
+// hashes its two children. The tree is returned in heap order.
+func (s *Server) merkleTree(rng router.HashRange, depth int, position router.HashFunc) ([][]byte, error) {
+	leaves := 1 << depth
+	tree := make([][sha256.Size]byte, 2*leaves-1)
+	first := leaves - 1
+
+	iter := s.db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		hash := position(iter.Key())
+		if !rng.Contains(hash) {
+			continue
+		}
+		rec, err := decodeRecord(iter.Value())
+		if err != nil {
+			return nil, err
+		}
+		digest := recordDigest(iter.Key(), rec)
+		leaf := &tree[first+rng.Bucket(hash, leaves)]
+		for i := range leaf {
+			leaf[i] ^= digest[i]
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+
+	for i := first - 1; i >= 0; i-- {
+		tree[i] = sha256.Sum256(append(tree[2*i+1][:], tree[2*i+2][:]...))
+	}
+
+	hashes := make([][]byte, len(tree))
+	for i := range tree {
+		hashes[i] = tree[i][:]
+	}
+	return hashes, nil
+}
+
+// recordDigest identifies a key at a version. Values are left out since a
+// version is only ever written with one value.
+func recordDigest(key []byte, rec Record) [sha256.Size]byte {
+	buf := make([]byte, 0, len(key)+9)
+	buf = append(buf, key...)
+	buf = binary.BigEndian.AppendUint64(buf, rec.Version)
+	if rec.Tombstone {
+		buf = append(buf, 1)
+	} else {
+		buf = append(buf, 0)
+	}
+	return sha256.Sum256(buf)
+}
@@ -0,0 +1,858 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	pb "badies/proto/badiespb"
+	"badies/router"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"go.etcd.io/raft/v3"
+	"go.etcd.io/raft/v3/raftpb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	raftTickInterval  = 100 * time.Millisecond
+	raftElectionTicks = 10
+	raftSendTimeout   = 2 * time.Second
+
+	// raftSnapshotEntries is the number of applied entries between snapshots
+	raftSnapshotEntries = 10000
+	// raftCatchUpEntries is the number of entries kept in the log behind a
+	// snapshot, so that a member that lags a little needs no snapshot
+	raftCatchUpEntries = 1000
+	// raftDedupWindow is the number of log entries a write's request ID is
+	// remembered for, so that a retry on another member is applied once
+	raftDedupWindow = 10000
+)
+
+// MaxMessageSize is the largest message a node accepts. A raft snapshot sent
+// to a member that fell behind carries every record of its partition in one
+// message, far beyond the 4 MiB gRPC allows by default.
+const MaxMessageSize = 256 << 20
+
+// ErrGroupNotFound is returned for a raft group this node does not host
+var ErrGroupNotFound = errors.New("raft group not hosted on this node")
+
+// ErrWriteConflict is returned for a conditional write that found a newer
+// version when it was applied
+var ErrWriteConflict = errors.New("write conflict")
+
+// raftID maps a node ID to the non-zero raft member ID used in every group
+func raftID(nodeID string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(nodeID))
+	if id := h.Sum64(); id != 0 {
+		return id
+	}
+	return 1
+}
+
+// RaftHost runs the raft groups this node is a member of. Each ring
+// partition is its own group; the raft log, hard state and latest snapshot of
+// all groups are kept in one LevelDB at <path>.raft and the state machine is
+// the node's data LevelDB. Every raftSnapshotEntries the log is compacted
+// behind a snapshot of the apply state. The records are not copied into it:
+// they already are in the data LevelDB, and a member that needs a snapshot
+// gets the current records of the group's range attached when it is sent.
+// Those may be newer than the snapshot, which is safe because every entry
+// after it is applied as a versioned write.
+type RaftHost struct {
+	server *Server
+	db     *leveldb.DB
+
+	mu     sync.Mutex
+	groups map[uint64]*raftGroup
+	conns  map[string]*grpc.ClientConn // peer address -> connection
+	closed bool
+}
+
+// raftGroup is one running raft instance and its apply state
+type raftGroup struct {
+	id      uint64
+	host    *RaftHost
+	node    raft.Node
+	storage *raft.MemoryStorage
+	peers   map[uint64]*pb.NodeRaftPeer // raft ID -> peer
+	stop    chan struct{}
+	done    chan struct{}
+
+	// Only touched by run
+	lastIndex     uint64 // last log index persisted
+	confState     raftpb.ConfState
+	snapshotIndex uint64 // index of the latest snapshot
+
+	mu          sync.Mutex
+	desc        *pb.NodeRaftGroup
+	applied     uint64
+	appliedCh   chan struct{}              // closed and replaced whenever applied advances
+	lastVersion uint64                     // highest version handed out by the log
+	results     map[uint64]raftResult      // request ID -> outcome of a recent write
+	recent      []*pb.NodeRaftApplied      // recent writes in log order, for expiring results
+	writes      map[uint64]chan raftResult // request ID -> apply outcome
+	reads       map[string]chan uint64     // read context -> read index
+}
+
+// openRaftHost opens the raft database and restarts every group found in it
+func openRaftHost(s *Server, path string) (*RaftHost, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open raft log at %s: %v", path, err)
+	}
+	h := &RaftHost{
+		server: s,
+		db:     db,
+		groups: make(map[uint64]*raftGroup),
+		conns:  make(map[string]*grpc.ClientConn),
+	}
+
+	iter := db.NewIterator(nil, nil)
+	var stored []*pb.NodeRaftGroup
+	for iter.Next() {
+		key := iter.Key()
+		if len(key) != 9 || key[8] != 'p' {
+			continue
+		}
+		group := &pb.NodeRaftGroup{}
+		if err := proto.Unmarshal(iter.Value(), group); err != nil {
+			iter.Release()
+			db.Close()
+			return nil, fmt.Errorf("corrupt raft group in %s: %v", path, err)
+		}
+		stored = append(stored, group)
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	for _, group := range stored {
+		if _, err := h.Start(group); err != nil {
+			h.Close()
+			return nil, err
+		}
+	}
+	return h, nil
+}
+
+// Close stops every group and closes the raft database
+func (h *RaftHost) Close() error {
+	h.mu.Lock()
+	h.closed = true
+	groups := h.groups
+	h.groups = make(map[uint64]*raftGroup)
+	h.mu.Unlock()
+
+	for _, g := range groups {
+		close(g.stop)
+		<-g.done
+	}
+	h.mu.Lock()
+	for _, conn := range h.conns {
+		conn.Close()
+	}
+	h.mu.Unlock()
+	return h.db.Close()
+}
+
+func groupKey(groupID uint64, kind byte) []byte {
+	key := make([]byte, 9)
+	binary.BigEndian.PutUint64(key, groupID)
+	key[8] = kind
+	return key
+}
+
+func entryKey(groupID uint64, index uint64) []byte {
+	return binary.BigEndian.AppendUint64(groupKey(groupID, 'e'), index)
+}
+
+// Start joins the group unless it is already running. A group that has
+// persisted state is restarted from it; otherwise it is bootstrapped with the
+// given peers.
+func (h *RaftHost) Start(desc *pb.NodeRaftGroup) (bool, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return false, errors.New("raft host is closed")
+	}
+	if g, running := h.groups[desc.GetGroupId()]; running {
+		return false, g.describe(desc)
+	}
+
+	self := raftID(h.server.nodeID)
+	g := &raftGroup{
+		id:        desc.GetGroupId(),
+		host:      h,
+		storage:   raft.NewMemoryStorage(),
+		peers:     make(map[uint64]*pb.NodeRaftPeer),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+		desc:      desc,
+		appliedCh: make(chan struct{}),
+		results:   make(map[uint64]raftResult),
+		writes:    make(map[uint64]chan raftResult),
+		reads:     make(map[string]chan uint64),
+	}
+	member := false
+	for _, peer := range desc.GetPeers() {
+		id := raftID(peer.GetNodeId())
+		g.peers[id] = peer
+		member = member || id == self
+	}
+	if !member {
+		return false, fmt.Errorf("node %s is not a member of raft group %d", h.server.nodeID, g.id)
+	}
+
+	restarted, err := g.load()
+	if err != nil {
+		return false, err
+	}
+	cfg := &raft.Config{
+		ID:              self,
+		ElectionTick:    raftElectionTicks,
+		HeartbeatTick:   1,
+		Storage:         raftStorage{g.storage, g},
+		Applied:         g.applied,
+		MaxSizePerMsg:   1 << 20,
+		MaxInflightMsgs: 256,
+		CheckQuorum:     true,
+		PreVote:         true,
+		ReadOnlyOption:  raft.ReadOnlySafe,
+		Logger:          &raft.DefaultLogger{Logger: log.New(os.Stderr, fmt.Sprintf("raft group %d: ", g.id), log.LstdFlags)},
+	}
+	if restarted {
+		g.node = raft.RestartNode(cfg)
+	} else {
+		if err := g.saveDesc(desc); err != nil {
+			return false, err
+		}
+		peers := make([]raft.Peer, 0, len(g.peers))
+		for id := range g.peers {
+			peers = append(peers, raft.Peer{ID: id})
+		}
+		g.node = raft.StartNode(cfg, peers)
+	}
+
+	h.groups[g.id] = g
+	go g.run()
+	log.Printf("Node %s joined raft group %d with %d peers", h.server.nodeID, g.id, len(g.peers))
+	return true, nil
+}
+
+func (h *RaftHost) group(groupID uint64) (*raftGroup, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	g, ok := h.groups[groupID]
+	if !ok {
+		return nil, ErrGroupNotFound
+	}
+	return g, nil
+}
+
+// describe adopts a newer description of a running group, which may add the
+// hash range of groups started before descriptions carried one
+func (g *raftGroup) describe(desc *pb.NodeRaftGroup) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if proto.Equal(g.desc, desc) {
+		return nil
+	}
+	if err := g.saveDesc(desc); err != nil {
+		return err
+	}
+	g.desc = desc
+	return nil
+}
+
+func (g *raftGroup) saveDesc(desc *pb.NodeRaftGroup) error {
+	data, err := proto.Marshal(desc)
+	if err != nil {
+		return err
+	}
+	return g.host.db.Put(groupKey(g.id, 'p'), data, nil)
+}
+
+// Step hands a message from a peer to its group
+func (h *RaftHost) Step(ctx context.Context, groupID uint64, data []byte) error {
+	g, err := h.group(groupID)
+	if err != nil {
+		return err
+	}
+	var msg raftpb.Message
+	if err := msg.Unmarshal(data); err != nil {
+		return err
+	}
+	return g.node.Step(ctx, msg)
+}
+
+// raftResult is the outcome of applying a write entry
+type raftResult struct {
+	version  uint64 // version written, or the stored version on a conflict
+	conflict bool
+}
+
+// Write proposes a write to the group and waits until this node applied it.
+// It returns the version the write was applied at. A conditional write that
+// lost to a newer version fails with ErrWriteConflict and the stored version.
+// A command that carries the request ID of a write applied before is not
+// applied again; it returns the outcome of the first one.
+func (h *RaftHost) Write(ctx context.Context, groupID uint64, cmd *pb.NodeRaftCommand) (uint64, error) {
+	g, err := h.group(groupID)
+	if err != nil {
+		return 0, err
+	}
+	if cmd.RequestId == 0 {
+		cmd.RequestId = randomID()
+	}
+	data, err := proto.Marshal(cmd)
+	if err != nil {
+		return 0, err
+	}
+
+	done := make(chan raftResult, 1)
+	g.mu.Lock()
+	g.writes[cmd.RequestId] = done
+	g.mu.Unlock()
+	defer func() {
+		g.mu.Lock()
+		delete(g.writes, cmd.RequestId)
+		g.mu.Unlock()
+	}()
+
+	if err := g.node.Propose(ctx, data); err != nil {
+		return 0, err
+	}
+	select {
+	case res := <-done:
+		if res.conflict {
+			return res.version, ErrWriteConflict
+		}
+		return res.version, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+// Read returns the record for key once this node applied everything the
+// group committed before the read started (read index), which makes the
+// read linearizable on any member.
+func (h *RaftHost) Read(ctx context.Context, groupID uint64, key []byte) (Record, bool, error) {
+	g, err := h.group(groupID)
+	if err != nil {
+		return Record{}, false, err
+	}
+	rctx := make([]byte, 8)
+	binary.BigEndian.PutUint64(rctx, randomID())
+
+	done := make(chan uint64, 1)
+	g.mu.Lock()
+	g.reads[string(rctx)] = done
+	g.mu.Unlock()
+	defer func() {
+		g.mu.Lock()
+		delete(g.reads, string(rctx))
+		g.mu.Unlock()
+	}()
+
+	if err := g.node.ReadIndex(ctx, rctx); err != nil {
+		return Record{}, false, err
+	}
+	var index uint64
+	select {
+	case index = <-done:
+	case <-ctx.Done():
+		return Record{}, false, ctx.Err()
+	}
+	if err := g.waitApplied(ctx, index); err != nil {
+		return Record{}, false, err
+	}
+	return h.server.readRecord(key)
+}
+
+func randomID() uint64 {
+	var b [8]byte
+	rand.Read(b[:])
+	return binary.BigEndian.Uint64(b[:])
+}
+
+// load restores the persisted snapshot, hard state and log. It reports
+// whether the group had been started on this node before.
+func (g *raftGroup) load() (bool, error) {
+	db := g.host.db
+	hsData, err := db.Get(groupKey(g.id, 'h'), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var hs raftpb.HardState
+	if err := hs.Unmarshal(hsData); err != nil {
+		return false, err
+	}
+
+	snapData, err := db.Get(groupKey(g.id, 's'), nil)
+	if err != nil && !errors.Is(err, leveldb.ErrNotFound) {
+		return false, err
+	}
+	if err == nil {
+		var snap raftpb.Snapshot
+		if err := snap.Unmarshal(snapData); err != nil {
+			return false, err
+		}
+		if err := g.storage.ApplySnapshot(snap); err != nil {
+			return false, err
+		}
+		if err := g.restoreState(snap); err != nil {
+			return false, err
+		}
+		g.lastIndex = snap.Metadata.Index
+	}
+
+	var entries []raftpb.Entry
+	iter := db.NewIterator(util.BytesPrefix(groupKey(g.id, 'e')), nil)
+	for iter.Next() {
+		var e raftpb.Entry
+		if err := e.Unmarshal(iter.Value()); err != nil {
+			iter.Release()
+			return false, err
+		}
+		entries = append(entries, e)
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return false, err
+	}
+
+	if err := g.storage.Append(entries); err != nil {
+		return false, err
+	}
+	if err := g.storage.SetHardState(hs); err != nil {
+		return false, err
+	}
+	if n := len(entries); n > 0 {
+		g.lastIndex = entries[n-1].Index
+	}
+	return true, nil
+}
+
+// persist writes hard state and new entries, dropping any persisted entries
+// the new ones replaced. The write is synced, since a node must not forget
+// an entry it acknowledged or a vote it cast.
+func (g *raftGroup) persist(hs raftpb.HardState, entries []raftpb.Entry) error {
+	batch := new(leveldb.Batch)
+	if !raft.IsEmptyHardState(hs) {
+		data, err := hs.Marshal()
+		if err != nil {
+			return err
+		}
+		batch.Put(groupKey(g.id, 'h'), data)
+	}
+	if n := len(entries); n > 0 {
+		for _, e := range entries {
+			data, err := e.Marshal()
+			if err != nil {
+				return err
+			}
+			batch.Put(entryKey(g.id, e.Index), data)
+		}
+		last := entries[n-1].Index
+		for i := last + 1; i <= g.lastIndex; i++ {
+			batch.Delete(entryKey(g.id, i))
+		}
+		g.lastIndex = last
+	}
+	if batch.Len() == 0 {
+		return nil
+	}
+	return g.host.db.Write(batch, syncWrite)
+}
+
+// run drives the raft state machine until the group is stopped
+func (g *raftGroup) run() {
+	defer close(g.done)
+	ticker := time.NewTicker(raftTickInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-g.stop:
+			g.node.Stop()
+			return
+		case <-ticker.C:
+			g.node.Tick()
+		case rd := <-g.node.Ready():
+			if !raft.IsEmptySnap(rd.Snapshot) {
+				if err := g.installSnapshot(rd.Snapshot); err != nil {
+					log.Fatalf("raft group %d: installing snapshot failed: %v", g.id, err)
+				}
+			}
+			if err := g.persist(rd.HardState, rd.Entries); err != nil {
+				log.Fatalf("raft group %d: persisting log failed: %v", g.id, err)
+			}
+			g.storage.Append(rd.Entries)
+			if !raft.IsEmptyHardState(rd.HardState) {
+				g.storage.SetHardState(rd.HardState)
+			}
+			g.send(rd.Messages)
+			for _, rs := range rd.ReadStates {
+				g.mu.Lock()
+				if ch, ok := g.reads[string(rs.RequestCtx)]; ok {
+					ch <- rs.Index
+				}
+				g.mu.Unlock()
+			}
+			for _, entry := range rd.CommittedEntries {
+				g.applyEntry(entry)
+			}
+			if err := g.maybeSnapshot(); err != nil {
+				log.Printf("raft group %d: snapshot failed: %v", g.id, err)
+			}
+			g.node.Advance()
+		}
+	}
+}
+
+// applyEntry applies one committed entry to the node's data. Versions are
+// derived from the log itself: an entry gets at least the version it was
+// proposed with and always more than every entry before it, so all members
+// and every replay after a restart arrive at the same versions. A
+// conditional write that fails its check still consumes its version.
+func (g *raftGroup) applyEntry(entry raftpb.Entry) {
+	switch entry.Type {
+	case raftpb.EntryConfChange:
+		var cc raftpb.ConfChange
+		if err := cc.Unmarshal(entry.Data); err == nil {
+			g.confState = *g.node.ApplyConfChange(cc)
+		}
+	case raftpb.EntryNormal:
+		if len(entry.Data) == 0 {
+			break // empty entry appended by a new leader
+		}
+		cmd := &pb.NodeRaftCommand{}
+		if err := proto.Unmarshal(entry.Data, cmd); err != nil {
+			log.Printf("raft group %d: skipping corrupt entry %d: %v", g.id, entry.Index, err)
+			break
+		}
+		g.mu.Lock()
+		res, duplicate := g.results[cmd.GetRequestId()]
+		g.mu.Unlock()
+		if !duplicate {
+			res = g.applyCommand(entry.Index, cmd)
+		}
+		g.mu.Lock()
+		if ch, ok := g.writes[cmd.GetRequestId()]; ok {
+			select {
+			case ch <- res:
+			default: // a duplicate already answered the waiter
+			}
+		}
+		g.mu.Unlock()
+	}
+
+	g.mu.Lock()
+	g.applied = entry.Index
+	close(g.appliedCh)
+	g.appliedCh = make(chan struct{})
+	g.mu.Unlock()
+}
+
+// applyCommand applies a write that was not applied before and remembers
+// its outcome for raftDedupWindow entries
+func (g *raftGroup) applyCommand(index uint64, cmd *pb.NodeRaftCommand) raftResult {
+	g.mu.Lock()
+	version := max(cmd.GetVersion(), g.lastVersion+1)
+	if cmd.GetExact() {
+		version = cmd.GetVersion()
+	}
+	g.lastVersion = max(g.lastVersion, version)
+	g.mu.Unlock()
+
+	maxVersion := uint64(math.MaxUint64)
+	if cmd.GetConditional() {
+		maxVersion = cmd.GetExpectedVersion()
+	}
+	rec := Record{Version: version, Tombstone: cmd.GetTombstone(), Value: cmd.GetValue(), ExpiresAt: cmd.GetExpiresAt()}
+	_, conflict, err := g.host.server.applyIf(cmd.GetKey(), rec, maxVersion)
+	if err != nil {
+		log.Fatalf("raft group %d: applying entry %d failed: %v", g.id, index, err)
+	}
+	res := raftResult{version: version}
+	if conflict != 0 {
+		res = raftResult{version: conflict, conflict: true}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for len(g.recent) > 0 && g.recent[0].GetIndex()+raftDedupWindow <= index {
+		delete(g.results, g.recent[0].GetRequestId())
+		g.recent = g.recent[1:]
+	}
+	if id := cmd.GetRequestId(); id != 0 {
+		g.results[id] = res
+		g.recent = append(g.recent, &pb.NodeRaftApplied{RequestId: id, Index: index, Version: res.version, Conflict: res.conflict})
+	}
+	return res
+}
+
+// raftStorage is the log storage of a group. The snapshots it keeps only
+// hold the apply state; when raft sends one to a member that fell behind, the
+// records of the group's range are attached.
+type raftStorage struct {
+	*raft.MemoryStorage
+	group *raftGroup
+}
+
+func (s raftStorage) Snapshot() (raftpb.Snapshot, error) {
+	snap, err := s.MemoryStorage.Snapshot()
+	if err != nil || raft.IsEmptySnap(snap) {
+		return snap, err
+	}
+	data, err := s.group.withRecords(snap.Data)
+	if err != nil {
+		log.Printf("raft group %d: preparing snapshot failed: %v", s.group.id, err)
+		return raftpb.Snapshot{}, raft.ErrSnapshotTemporarilyUnavailable
+	}
+	snap.Data = data
+	return snap, nil
+}
+
+// withRecords adds the current records of the group's range to snapshot data
+func (g *raftGroup) withRecords(data []byte) ([]byte, error) {
+	state := &pb.NodeRaftSnapshot{}
+	if err := proto.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	g.mu.Lock()
+	desc := g.desc
+	g.mu.Unlock()
+	if desc.GetRange() == nil {
+		return nil, errors.New("the hash range of the group is not known yet")
+	}
+	position, err := router.LookupHash(desc.GetHash())
+	if err != nil {
+		return nil, err
+	}
+	rng := router.HashRange{Start: desc.GetRange().GetStart(), End: desc.GetRange().GetEnd()}
+
+	iter := g.host.server.db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		if !rng.Contains(position(iter.Key())) {
+			continue
+		}
+		rec, err := decodeRecord(iter.Value())
+		if err != nil {
+			return nil, fmt.Errorf("corrupt record for key %q: %v", iter.Key(), err)
+		}
+		state.Records = append(state.Records, toNodeRecord(iter.Key(), rec))
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return proto.Marshal(state)
+}
+
+// maybeSnapshot takes a snapshot once raftSnapshotEntries were applied since
+// the last one and compacts the log behind it, keeping raftCatchUpEntries
+func (g *raftGroup) maybeSnapshot() error {
+	g.mu.Lock()
+	applied := g.applied
+	state := &pb.NodeRaftSnapshot{LastVersion: g.lastVersion, Applied: slices.Clone(g.recent)}
+	g.mu.Unlock()
+	if applied < g.snapshotIndex+raftSnapshotEntries {
+		return nil
+	}
+
+	data, err := proto.Marshal(state)
+	if err != nil {
+		return err
+	}
+	snap, err := g.storage.CreateSnapshot(applied, &g.confState, data)
+	if err != nil {
+		return err
+	}
+	var compact uint64
+	if applied > raftCatchUpEntries {
+		compact = applied - raftCatchUpEntries
+	}
+	if err := g.saveSnapshot(snap, compact); err != nil {
+		return err
+	}
+	if compact > 0 {
+		if err := g.storage.Compact(compact); err != nil && !errors.Is(err, raft.ErrCompacted) {
+			return err
+		}
+	}
+	g.snapshotIndex = applied
+	log.Printf("raft group %d: snapshot at index %d, log compacted up to %d", g.id, applied, compact)
+	return nil
+}
+
+// installSnapshot replaces the state of the group with a snapshot the leader
+// sent because this member fell behind the compacted log
+func (g *raftGroup) installSnapshot(snap raftpb.Snapshot) error {
+	state := &pb.NodeRaftSnapshot{}
+	if err := proto.Unmarshal(snap.Data, state); err != nil {
+		return err
+	}
+	records := len(state.GetRecords())
+	for _, rec := range state.GetRecords() {
+		if _, _, err := g.host.server.applyIf(rec.GetKey(), fromNodeRecord(rec), math.MaxUint64); err != nil {
+			return err
+		}
+	}
+	// The records are in the data LevelDB now, so only the apply state is kept
+	state.Records = nil
+	data, err := proto.Marshal(state)
+	if err != nil {
+		return err
+	}
+	snap.Data = data
+
+	// The snapshot replaces the whole log
+	if err := g.saveSnapshot(snap, math.MaxUint64); err != nil {
+		return err
+	}
+	g.lastIndex = snap.Metadata.Index
+	if err := g.storage.ApplySnapshot(snap); err != nil {
+		return err
+	}
+	if err := g.restoreState(snap); err != nil {
+		return err
+	}
+	log.Printf("raft group %d: installed snapshot at index %d with %d records", g.id, snap.Metadata.Index, records)
+	return nil
+}
+
+// saveSnapshot durably persists snap and drops the persisted entries up to
+// through
+func (g *raftGroup) saveSnapshot(snap raftpb.Snapshot, through uint64) error {
+	data, err := snap.Marshal()
+	if err != nil {
+		return err
+	}
+	batch := new(leveldb.Batch)
+	batch.Put(groupKey(g.id, 's'), data)
+	limit := groupKey(g.id, 'f') // just past every entry key
+	if through < math.MaxUint64 {
+		limit = entryKey(g.id, through+1)
+	}
+	iter := g.host.db.NewIterator(&util.Range{Start: entryKey(g.id, 0), Limit: limit}, nil)
+	for iter.Next() {
+		batch.Delete(append([]byte(nil), iter.Key()...))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	return g.host.db.Write(batch, syncWrite)
+}
+
+// restoreState takes over the apply state of a snapshot
+func (g *raftGroup) restoreState(snap raftpb.Snapshot) error {
+	state := &pb.NodeRaftSnapshot{}
+	if err := proto.Unmarshal(snap.Data, state); err != nil {
+		return err
+	}
+	g.confState = snap.Metadata.ConfState
+	g.snapshotIndex = snap.Metadata.Index
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.lastVersion = state.GetLastVersion()
+	g.recent = state.GetApplied()
+	g.results = make(map[uint64]raftResult, len(g.recent))
+	for _, w := range g.recent {
+		g.results[w.GetRequestId()] = raftResult{version: w.GetVersion(), conflict: w.GetConflict()}
+	}
+	g.applied = snap.Metadata.Index
+	close(g.appliedCh)
+	g.appliedCh = make(chan struct{})
+	return nil
+}
+
+// waitApplied blocks until the group applied index
+func (g *raftGroup) waitApplied(ctx context.Context, index uint64) error {
+	for {
+		g.mu.Lock()
+		applied, ch := g.applied, g.appliedCh
+		g.mu.Unlock()
+		if applied >= index {
+			return nil
+		}
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// send delivers outgoing messages to the peers' RaftMessage endpoints
+func (g *raftGroup) send(msgs []raftpb.Message) {
+	for _, msg := range msgs {
+		peer, ok := g.peers[msg.To]
+		if !ok {
+			continue
+		}
+		data, err := msg.Marshal()
+		if err != nil {
+			continue
+		}
+		go func(to uint64, addr string, snapshot bool) {
+			client, err := g.host.peerClient(addr)
+			if err == nil {
+				ctx, cancel := context.WithTimeout(context.Background(), raftSendTimeout)
+				_, err = client.RaftMessage(ctx, &pb.NodeRaftEnvelope{GroupId: g.id, Message: data})
+				cancel()
+			}
+			if err != nil {
+				g.node.ReportUnreachable(to)
+			}
+			if snapshot {
+				status := raft.SnapshotFinish
+				if err != nil {
+					status = raft.SnapshotFailure
+				}
+				g.node.ReportSnapshot(to, status)
+			}
+		}(msg.To, peer.GetAddr(), msg.Type == raftpb.MsgSnap)
+	}
+}
+
+// peerClient returns a cached StorageNode client for a peer address
+func (h *RaftHost) peerClient(addr string) (pb.StorageNodeClient, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, errors.New("raft host is closed")
+	}
+	conn, ok := h.conns[addr]
+	if !ok {
+		var err error
+		conn, err = grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, err
+		}
+		h.conns[addr] = conn
+	}
+	return pb.NewStorageNodeClient(conn), nil
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// Record is what a node stores for every key: the value together with the
+// version it was written at. Deleted keys keep a tombstone record so that
+// replicas can tell a delete apart from a write they missed.
+type Record struct {
+	Version   uint64
+	Tombstone bool
+	Value     []byte
+	// ExpiresAt is the unix time in nanoseconds after which the key is
+	// hidden, 0 if it never expires
+	ExpiresAt int64
+}
+
+// Expired reports whether the record's TTL ran out at now
+func (r Record) Expired(now time.Time) bool {
+	return r.ExpiresAt != 0 && r.ExpiresAt <= now.UnixNano()
+}
+
+const (
+	flagTombstone byte = 1 << 0
+	flagExpiry    byte = 1 << 1 // an expiry time follows the version
+
+	// recordFormat marks the flags byte of every encoded record. Nodes
+	// before versioned records stored the raw value, which the v1 API
+	// only accepted as a UTF-8 string, and no UTF-8 string starts with a
+	// byte of the form 10xxxxxx.
+	recordFormat byte = 0x80
+	formatMask   byte = 0xc0
+
+	recordHeaderSize = 1 + 8 // flags + version
+	expirySize       = 8
+)
+
+// legacyVersion is the version of values stored before records had one. It
+// is older than any version a coordinator hands out, but unlike 0 it does
+// not read as a key that was never written, so repair still copies them.
+const legacyVersion = 1
+
+// encode serializes the record as [flags][version][expiry][value], where
+// the expiry is only present if the record has one
+func (r Record) encode() []byte {
+	size := recordHeaderSize + len(r.Value)
+	if r.ExpiresAt != 0 {
+		size += expirySize
+	}
+	buf := make([]byte, recordHeaderSize, size)
+	buf[0] = recordFormat
+	if r.Tombstone {
+		buf[0] |= flagTombstone
+	}
+	binary.BigEndian.PutUint64(buf[1:9], r.Version)
+	if r.ExpiresAt != 0 {
+		buf[0] |= flagExpiry
+		buf = binary.BigEndian.AppendUint64(buf, uint64(r.ExpiresAt))
+	}
+	return append(buf, r.Value...)
+}
+
+// decodeRecord parses a record produced by encode. Anything else is a raw
+// value written before records existed, and is read at legacyVersion.
+func decodeRecord(data []byte) (Record, error) {
+	if len(data) == 0 || data[0]&formatMask != recordFormat {
+		return Record{Version: legacyVersion, Value: append([]byte(nil), data...)}, nil
+	}
+	if len(data) < recordHeaderSize {
+		return Record{}, fmt.Errorf("record too short: %d bytes", len(data))
+	}
+	flags := data[0]
+	r := Record{
+		Tombstone: flags&flagTombstone != 0,
+		Version:   binary.BigEndian.Uint64(data[1:9]),
+	}
+	data = data[recordHeaderSize:]
+	if flags&flagExpiry != 0 {
+		if len(data) < expirySize {
+			return Record{}, fmt.Errorf("record too short for its expiry: %d bytes", len(data)+recordHeaderSize)
+		}
+		r.ExpiresAt = int64(binary.BigEndian.Uint64(data[:expirySize]))
+		data = data[expirySize:]
+	}
+	if len(data) > 0 {
+		r.Value = append([]byte(nil), data...)
+	}
+	return r, nil
+}
@@ -0,0 +1,615 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"log"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	pb "badies/proto/badiespb"
+	"badies/router"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"go.etcd.io/raft/v3"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements the StorageNode service on top of a single local LevelDB
+type Server struct {
+	pb.UnimplementedStorageNodeServer
+	nodeID string
+	db     *leveldb.DB
+	locks  [256]sync.Mutex // striped per-key locks for versioned writes
+
+	// Hints holds writes for other replicas that were unreachable. It lives
+	// in its own LevelDB at <path>.hints.
+	Hints *HintStore
+	// Raft runs the strongly consistent replication groups this node is a
+	// member of. Their logs live in <path>.raft.
+	Raft *RaftHost
+	// History keeps replaced versions of keys for reads at older revisions.
+	// It lives in <path>.history.
+	History *HistoryStore
+
+	txns    *txnStore     // prepared transactions, in <path>.txn
+	epoch   atomic.Uint64 // highest ring epoch a coordinator sent
+	epochMu sync.Mutex    // orders the writes of a new epoch
+}
+
+// NewServer opens the LevelDB at path and returns a StorageNode server for it
+func NewServer(nodeID string, path string, options *opt.Options) (*Server, error) {
+	db, err := leveldb.OpenFile(path, options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open DB for node %s at path %s: %v", nodeID, path, err)
+	}
+	hints, err := OpenHintStore(path + ".hints")
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("node %s: %v", nodeID, err)
+	}
+	history, err := OpenHistoryStore(path + ".history")
+	if err != nil {
+		hints.Close()
+		db.Close()
+		return nil, fmt.Errorf("node %s: %v", nodeID, err)
+	}
+	txns, err := openTxnStore(path + ".txn")
+	if err != nil {
+		history.Close()
+		hints.Close()
+		db.Close()
+		return nil, fmt.Errorf("node %s: %v", nodeID, err)
+	}
+	s := &Server{nodeID: nodeID, db: db, Hints: hints, History: history, txns: txns}
+	if err := s.loadEpoch(); err != nil {
+		txns.Close()
+		history.Close()
+		hints.Close()
+		db.Close()
+		return nil, fmt.Errorf("node %s: %v", nodeID, err)
+	}
+	s.Raft, err = openRaftHost(s, path+".raft")
+	if err != nil {
+		txns.Close()
+		history.Close()
+		hints.Close()
+		db.Close()
+		return nil, fmt.Errorf("node %s: %v", nodeID, err)
+	}
+	log.Printf("Node %s opened database at %s", nodeID, path)
+	return s, nil
+}
+
+// Close closes the underlying databases
+func (s *Server) Close() error {
+	var errs []error
+	if err := s.Raft.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close raft log for node %s: %v", s.nodeID, err))
+	}
+	if err := s.txns.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close transaction store for node %s: %v", s.nodeID, err))
+	}
+	if err := s.History.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close history store for node %s: %v", s.nodeID, err))
+	}
+	if err := s.Hints.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close hint store for node %s: %v", s.nodeID, err))
+	}
+	if err := s.db.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close database for node %s: %v", s.nodeID, err))
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	log.Printf("Node %s closed its database", s.nodeID)
+	return nil
+}
+
+// keyLock returns the mutex guarding read-modify-write cycles on key
+func (s *Server) keyLock(key []byte) *sync.Mutex {
+	return &s.locks[s.lockIndex(key)]
+}
+
+func (s *Server) lockIndex(key []byte) int {
+	return int(crc32.ChecksumIEEE(key) % uint32(len(s.locks)))
+}
+
+// readRecord loads the record stored for key. A missing key is reported as
+// found == false without an error.
+func (s *Server) readRecord(key []byte) (Record, bool, error) {
+	data, err := s.db.Get(key, nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return Record{}, false, nil
+	}
+	if err != nil {
+		return Record{}, false, err
+	}
+	rec, err := decodeRecord(data)
+	if err != nil {
+		return Record{}, false, fmt.Errorf("corrupt record for key %q: %v", key, err)
+	}
+	return rec, true, nil
+}
+
+// apply writes rec for key unless the node already stores the same or a
+// newer version. It reports whether the record was written.
+func (s *Server) apply(key []byte, rec Record) (bool, error) {
+	applied, _, err := s.applyIf(key, rec, math.MaxUint64)
+	return applied, err
+}
+
+// applyIf is apply for conditional writes: it only writes rec if the stored
+// version (0 for a missing key) is not newer than maxVersion, unless it is
+// rec's own version. On a conflict it returns the stored version. The
+// replaced record is kept in the history first.
+func (s *Server) applyIf(key []byte, rec Record, maxVersion uint64) (applied bool, conflict uint64, err error) {
+	mu := s.keyLock(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	current, found, err := s.readRecord(key)
+	if err != nil {
+		return false, 0, err
+	}
+	if revoked, err := s.History.Revoked(key, rec.Version); err != nil || revoked {
+		return false, 0, err
+	}
+	// The write itself may already have arrived through read repair
+	if found && current.Version > maxVersion && current.Version != rec.Version {
+		return false, current.Version, nil
+	}
+	if found && current.Version >= rec.Version {
+		return false, 0, nil
+	}
+	if found {
+		if err := s.History.Save(key, current); err != nil {
+			return false, 0, err
+		}
+	}
+	if err := s.db.Put(key, rec.encode(), nil); err != nil {
+		return false, 0, err
+	}
+	return true, 0, nil
+}
+
+// Put stores a key-value pair in the local database if it is newer than the
+// stored version. Conditional puts are rejected with conflict set if the
+// stored version is newer than the expected one.
+func (s *Server) Put(ctx context.Context, req *pb.NodePutRequest) (*pb.NodePutResponse, error) {
+	maxVersion := uint64(math.MaxUint64)
+	if req.GetConditional() {
+		maxVersion = req.GetExpectedVersion()
+	}
+	rec := Record{Version: req.GetVersion(), Value: req.GetValue(), ExpiresAt: req.GetExpiresAt()}
+	applied, conflict, err := s.applyIf(req.GetKey(), rec, maxVersion)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: put failed: %v", s.nodeID, err)
+	}
+	if conflict != 0 {
+		return &pb.NodePutResponse{Success: false, Conflict: true, CurrentVersion: conflict}, nil
+	}
+	return &pb.NodePutResponse{Success: true, Applied: applied}, nil
+}
+
+// Get reads a record from the local database. Tombstones are returned as not
+// found together with the version they were deleted at. With a revision set
+// it returns the version that was current at that revision, with its expiry
+// judged at that time.
+func (s *Server) Get(ctx context.Context, req *pb.NodeGetRequest) (*pb.NodeGetResponse, error) {
+	if revision := req.GetRevision(); revision > 0 {
+		rec, found, err := s.readAt(req.GetKey(), revision)
+		if errors.Is(err, ErrCompacted) {
+			return nil, status.Errorf(codes.OutOfRange, "node %s: revision %d has been compacted, the oldest is %d",
+				s.nodeID, revision, s.History.Compacted())
+		}
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "node %s: get failed: %v", s.nodeID, err)
+		}
+		return toGetResponse(rec, found, time.Unix(0, int64(min(revision, uint64(time.Now().UnixNano()))))), nil
+	}
+	rec, found, err := s.readRecord(req.GetKey())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: get failed: %v", s.nodeID, err)
+	}
+	return toGetResponse(rec, found, time.Now()), nil
+}
+
+// toGetResponse converts a stored record into a Get answer, with expiry
+// judged at now. Expired records are not found but keep their value, so read
+// repair can still copy them.
+func toGetResponse(rec Record, found bool, now time.Time) *pb.NodeGetResponse {
+	if !found {
+		return &pb.NodeGetResponse{Found: false}
+	}
+	if rec.Tombstone {
+		return &pb.NodeGetResponse{Found: false, Version: rec.Version, Tombstone: true}
+	}
+	return &pb.NodeGetResponse{
+		Value:     rec.Value,
+		Found:     !rec.Expired(now),
+		Version:   rec.Version,
+		ExpiresAt: rec.ExpiresAt,
+	}
+}
+
+// Delete replaces a key with a tombstone at the given version
+func (s *Server) Delete(ctx context.Context, req *pb.NodeDeleteRequest) (*pb.NodeDeleteResponse, error) {
+	applied, err := s.apply(req.GetKey(), Record{Version: req.GetVersion(), Tombstone: true})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: delete failed: %v", s.nodeID, err)
+	}
+	return &pb.NodeDeleteResponse{Success: true, Applied: applied}, nil
+}
+
+// StreamRange streams every record whose key hashes into one of the
+// requested ring ranges, together with the key's kept versions if asked
+func (s *Server) StreamRange(req *pb.NodeRangeRequest, stream pb.StorageNode_StreamRangeServer) error {
+	position, err := router.LookupHash(req.GetHash())
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "node %s: %v", s.nodeID, err)
+	}
+	ranges := make([]router.HashRange, 0, len(req.GetRanges()))
+	for _, r := range req.GetRanges() {
+		ranges = append(ranges, router.HashRange{Start: r.GetStart(), End: r.GetEnd()})
+	}
+
+	iter := s.db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		if !inRanges(ranges, position(iter.Key())) {
+			continue
+		}
+		rec, err := decodeRecord(iter.Value())
+		if err != nil {
+			return status.Errorf(codes.DataLoss, "node %s: corrupt record for key %q: %v", s.nodeID, iter.Key(), err)
+		}
+		out := toNodeRecord(iter.Key(), rec)
+		if req.GetHistory() {
+			kept, err := s.History.List(iter.Key(), 0)
+			if err != nil {
+				return status.Errorf(codes.Internal, "node %s: history of key %q failed: %v", s.nodeID, iter.Key(), err)
+			}
+			for _, old := range kept {
+				out.History = append(out.History, toNodeRecord(iter.Key(), old))
+			}
+		}
+		if err := stream.Send(out); err != nil {
+			return err
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return status.Errorf(codes.Internal, "node %s: iteration failed: %v", s.nodeID, err)
+	}
+	return nil
+}
+
+// Scan streams the records of a key range in key order. Tombstones are sent
+// too, so the coordinator can tell a deleted key from a replica that missed
+// the delete.
+func (s *Server) Scan(req *pb.NodeScanRequest, stream pb.StorageNode_ScanServer) error {
+	rng := scanRange(req.GetStartKey(), req.GetEndKey(), req.GetPrefix())
+	if rng.Limit != nil && bytes.Compare(rng.Start, rng.Limit) >= 0 {
+		return nil
+	}
+
+	iter := s.db.NewIterator(rng, nil)
+	defer iter.Release()
+	first, next := iter.First, iter.Next
+	if req.GetReverse() {
+		first, next = iter.Last, iter.Prev
+	}
+	for ok := first(); ok; ok = next() {
+		rec, err := decodeRecord(iter.Value())
+		if err != nil {
+			return status.Errorf(codes.DataLoss, "node %s: corrupt record for key %q: %v", s.nodeID, iter.Key(), err)
+		}
+		if err := stream.Send(toNodeRecord(iter.Key(), rec)); err != nil {
+			return err
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return status.Errorf(codes.Internal, "node %s: iteration failed: %v", s.nodeID, err)
+	}
+	return nil
+}
+
+// scanRange intersects [start, end) with the keys beginning with prefix.
+// Empty bounds are open.
+func scanRange(start, end, prefix []byte) *util.Range {
+	rng := &util.Range{Start: start}
+	if len(end) > 0 {
+		rng.Limit = end
+	}
+	if len(prefix) > 0 {
+		pr := util.BytesPrefix(prefix)
+		if bytes.Compare(pr.Start, rng.Start) > 0 {
+			rng.Start = pr.Start
+		}
+		if pr.Limit != nil && (rng.Limit == nil || bytes.Compare(pr.Limit, rng.Limit) < 0) {
+			rng.Limit = pr.Limit
+		}
+	}
+	return rng
+}
+
+// Purge physically removes a key unless a newer version was written since
+// the caller read it
+func (s *Server) Purge(ctx context.Context, req *pb.NodePurgeRequest) (*pb.NodePurgeResponse, error) {
+	mu := s.keyLock(req.GetKey())
+	mu.Lock()
+	defer mu.Unlock()
+
+	rec, found, err := s.readRecord(req.GetKey())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: purge failed: %v", s.nodeID, err)
+	}
+	if !found || rec.Version > req.GetVersion() {
+		return &pb.NodePurgeResponse{Purged: false}, nil
+	}
+	if err := s.History.Drop(req.GetKey()); err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: purge failed: %v", s.nodeID, err)
+	}
+	if err := s.db.Delete(req.GetKey(), nil); err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: purge failed: %v", s.nodeID, err)
+	}
+	return &pb.NodePurgeResponse{Purged: true}, nil
+}
+
+// Revert undoes a conditional write that lost to a concurrent write on other
+// replicas. The version of that write is revoked, so that repair from a
+// replica that still has it cannot bring it back, and if key is still at that
+// version the record it replaced is taken back from the history. A replica
+// that does not keep that record, such as one that lagged behind, drops the
+// key instead and lets repair refill it.
+func (s *Server) Revert(ctx context.Context, req *pb.NodeRevertRequest) (*pb.NodeRevertResponse, error) {
+	key := req.GetKey()
+	mu := s.keyLock(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	if err := s.History.Revoke(key, req.GetVersion()); err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: revert failed: %v", s.nodeID, err)
+	}
+	current, found, err := s.readRecord(key)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: revert failed: %v", s.nodeID, err)
+	}
+	if !found || current.Version != req.GetVersion() {
+		return &pb.NodeRevertResponse{Reverted: false}, nil
+	}
+	previous, kept, err := s.History.At(key, req.GetPreviousVersion())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: revert failed: %v", s.nodeID, err)
+	}
+	if !kept || previous.Version != req.GetPreviousVersion() {
+		if err := s.db.Delete(key, nil); err != nil {
+			return nil, status.Errorf(codes.Internal, "node %s: revert failed: %v", s.nodeID, err)
+		}
+		return &pb.NodeRevertResponse{Reverted: true}, nil
+	}
+	if err := s.db.Put(key, previous.encode(), nil); err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: revert failed: %v", s.nodeID, err)
+	}
+	if err := s.History.db.Delete(historyKey(key, previous.Version), nil); err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: revert failed: %v", s.nodeID, err)
+	}
+	return &pb.NodeRevertResponse{Reverted: true}, nil
+}
+
+// Sweep physically removes every record that expired before the requested
+// time. Each record is checked again under its key lock, so a write that
+// replaced it in the meantime survives.
+func (s *Server) Sweep(ctx context.Context, req *pb.NodeSweepRequest) (*pb.NodeSweepResponse, error) {
+	cutoff := time.Unix(0, req.GetExpiredBefore())
+	var expired [][]byte
+	iter := s.db.NewIterator(nil, nil)
+	for iter.Next() {
+		rec, err := decodeRecord(iter.Value())
+		if err == nil && rec.Expired(cutoff) {
+			expired = append(expired, append([]byte(nil), iter.Key()...))
+		}
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: iteration failed: %v", s.nodeID, err)
+	}
+
+	var swept int64
+	for _, key := range expired {
+		removed, err := s.sweepKey(key, cutoff)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "node %s: sweeping key %q failed: %v", s.nodeID, key, err)
+		}
+		if removed {
+			swept++
+		}
+	}
+	if swept > 0 {
+		log.Printf("Node %s swept %d expired keys", s.nodeID, swept)
+	}
+	return &pb.NodeSweepResponse{Swept: swept}, nil
+}
+
+// sweepKey deletes key if its record is still expired at cutoff. The record
+// moves to the history, where reads before its expiry still find it.
+func (s *Server) sweepKey(key []byte, cutoff time.Time) (bool, error) {
+	mu := s.keyLock(key)
+	mu.Lock()
+	defer mu.Unlock()
+
+	rec, found, err := s.readRecord(key)
+	if err != nil || !found || !rec.Expired(cutoff) {
+		return false, err
+	}
+	if err := s.History.Save(key, rec); err != nil {
+		return false, err
+	}
+	return true, s.db.Delete(key, nil)
+}
+
+func inRanges(ranges []router.HashRange, hash uint32) bool {
+	for _, r := range ranges {
+		if r.Contains(hash) {
+			return true
+		}
+	}
+	return false
+}
+
+// toNodeRecord converts a stored record into its wire form. The key is
+// copied since iterator keys are only valid until the next step.
+func toNodeRecord(key []byte, rec Record) *pb.NodeRecord {
+	return &pb.NodeRecord{
+		Key:       append([]byte(nil), key...),
+		Value:     rec.Value,
+		Version:   rec.Version,
+		Tombstone: rec.Tombstone,
+		ExpiresAt: rec.ExpiresAt,
+	}
+}
+
+// StoreHint keeps a write for a replica the coordinator could not reach
+func (s *Server) StoreHint(ctx context.Context, req *pb.NodeHint) (*pb.NodeStoreHintResponse, error) {
+	rec := req.GetRecord()
+	if req.GetTarget() == "" || rec == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "hint needs a target and a record")
+	}
+	err := s.Hints.Store(req.GetTarget(), rec.GetKey(), fromNodeRecord(rec))
+	if errors.Is(err, ErrHintStoreFull) {
+		return nil, status.Errorf(codes.ResourceExhausted, "node %s: %v", s.nodeID, err)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: storing hint failed: %v", s.nodeID, err)
+	}
+	return &pb.NodeStoreHintResponse{Stored: true}, nil
+}
+
+// StreamHints streams every hint that has not expired yet
+func (s *Server) StreamHints(req *pb.NodeStreamHintsRequest, stream pb.StorageNode_StreamHintsServer) error {
+	err := s.Hints.Each(func(h Hint) error {
+		return stream.Send(&pb.NodeHint{
+			Target:    h.Target,
+			Record:    toNodeRecord(h.Key, h.Record),
+			ExpiresAt: h.ExpiresAt.UnixNano(),
+		})
+	})
+	if err != nil {
+		return status.Errorf(codes.Internal, "node %s: streaming hints failed: %v", s.nodeID, err)
+	}
+	return nil
+}
+
+// DeleteHint drops a hint once it was delivered to its target
+func (s *Server) DeleteHint(ctx context.Context, req *pb.NodeDeleteHintRequest) (*pb.NodeDeleteHintResponse, error) {
+	deleted, err := s.Hints.Delete(req.GetTarget(), req.GetKey(), req.GetVersion())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: deleting hint failed: %v", s.nodeID, err)
+	}
+	return &pb.NodeDeleteHintResponse{Deleted: deleted}, nil
+}
+
+// fromNodeRecord converts a wire record into its stored form
+func fromNodeRecord(rec *pb.NodeRecord) Record {
+	return Record{
+		Version:   rec.GetVersion(),
+		Tombstone: rec.GetTombstone(),
+		Value:     rec.GetValue(),
+		ExpiresAt: rec.GetExpiresAt(),
+	}
+}
+
+// MerkleTree builds a Merkle tree over the records of a hash range
+func (s *Server) MerkleTree(ctx context.Context, req *pb.NodeMerkleRequest) (*pb.NodeMerkleResponse, error) {
+	depth := int(req.GetDepth())
+	if depth > router.MaxMerkleDepth {
+		return nil, status.Errorf(codes.InvalidArgument, "merkle depth %d exceeds %d", depth, router.MaxMerkleDepth)
+	}
+	position, err := router.LookupHash(req.GetHash())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "node %s: %v", s.nodeID, err)
+	}
+	rng := router.HashRange{Start: req.GetRange().GetStart(), End: req.GetRange().GetEnd()}
+	hashes, err := s.merkleTree(rng, depth, position)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: building merkle tree failed: %v", s.nodeID, err)
+	}
+	return &pb.NodeMerkleResponse{Hashes: hashes}, nil
+}
+
+// RaftStart joins this node to a raft group. Starting a group that already
+// runs is a no-op.
+func (s *Server) RaftStart(ctx context.Context, req *pb.NodeRaftGroup) (*pb.NodeRaftStartResponse, error) {
+	started, err := s.Raft.Start(req)
+	if err != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "node %s: starting raft group %d failed: %v", s.nodeID, req.GetGroupId(), err)
+	}
+	return &pb.NodeRaftStartResponse{Started: started}, nil
+}
+
+// RaftMessage delivers a message from another member of a raft group
+func (s *Server) RaftMessage(ctx context.Context, req *pb.NodeRaftEnvelope) (*pb.NodeRaftMessageResponse, error) {
+	if err := s.Raft.Step(ctx, req.GetGroupId(), req.GetMessage()); err != nil {
+		return nil, raftError(s.nodeID, req.GetGroupId(), err)
+	}
+	return &pb.NodeRaftMessageResponse{}, nil
+}
+
+// RaftWrite commits a write through a raft group and returns the version it
+// was applied at, which the caller fixes when it replays a logged write
+func (s *Server) RaftWrite(ctx context.Context, req *pb.NodeRaftWriteRequest) (*pb.NodeRaftWriteResponse, error) {
+	version := uint64(time.Now().UnixNano())
+	if req.GetVersion() != 0 {
+		version = req.GetVersion()
+	}
+	version, err := s.Raft.Write(ctx, req.GetGroupId(), &pb.NodeRaftCommand{
+		RequestId:       req.GetRequestId(),
+		Key:             req.GetKey(),
+		Value:           req.GetValue(),
+		Version:         version,
+		Tombstone:       req.GetTombstone(),
+		Conditional:     req.GetConditional(),
+		ExpectedVersion: req.GetExpectedVersion(),
+		ExpiresAt:       req.GetExpiresAt(),
+		Exact:           req.GetVersion() != 0,
+	})
+	if errors.Is(err, ErrWriteConflict) {
+		return &pb.NodeRaftWriteResponse{Version: version, Conflict: true}, nil
+	}
+	if err != nil {
+		return nil, raftError(s.nodeID, req.GetGroupId(), err)
+	}
+	return &pb.NodeRaftWriteResponse{Version: version}, nil
+}
+
+// RaftRead performs a linearizable read through a raft group
+func (s *Server) RaftRead(ctx context.Context, req *pb.NodeRaftReadRequest) (*pb.NodeGetResponse, error) {
+	rec, found, err := s.Raft.Read(ctx, req.GetGroupId(), req.GetKey())
+	if err != nil {
+		return nil, raftError(s.nodeID, req.GetGroupId(), err)
+	}
+	return toGetResponse(rec, found, time.Now()), nil
+}
+
+// raftError maps raft failures to status codes the coordinator can retry on.
+// A group without a leader is reported as aborted rather than unavailable,
+// so that an election does not count against the node's health.
+func raftError(nodeID string, groupID uint64, err error) error {
+	switch {
+	case errors.Is(err, ErrGroupNotFound):
+		return status.Errorf(codes.NotFound, "node %s: raft group %d: %v", nodeID, groupID, err)
+	case errors.Is(err, raft.ErrProposalDropped):
+		return status.Errorf(codes.Aborted, "node %s: raft group %d has no leader", nodeID, groupID)
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return status.FromContextError(err).Err()
+	}
+	return status.Errorf(codes.Internal, "node %s: raft group %d: %v", nodeID, groupID, err)
+}
@@ -0,0 +1,173 @@
+package storage
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"sync"
+
+	pb "badies/proto/badiespb"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+var syncWrite = &opt.WriteOptions{Sync: true}
+
+// txnStore holds the transactions this node prepared but was not yet told
+// to commit or abort. They are kept in their own LevelDB keyed by
+// transaction ID, so a prepared transaction survives a restart, and every
+// key they touch is reserved until the decision arrives.
+type txnStore struct {
+	db       *leveldb.DB
+	mu       sync.Mutex
+	staged   map[uint64]*pb.NodeTxnPrepareRequest
+	reserved map[string]uint64 // key -> transaction holding it
+}
+
+func openTxnStore(path string) (*txnStore, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open transaction store at %s: %v", path, err)
+	}
+	ts := &txnStore{db: db, staged: make(map[uint64]*pb.NodeTxnPrepareRequest), reserved: make(map[string]uint64)}
+	iter := db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		req := &pb.NodeTxnPrepareRequest{}
+		if err := proto.Unmarshal(iter.Value(), req); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("corrupt prepared transaction in %s: %v", path, err)
+		}
+		ts.stage(req)
+	}
+	if err := iter.Error(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to load transaction store at %s: %v", path, err)
+	}
+	return ts, nil
+}
+
+func (ts *txnStore) Close() error {
+	return ts.db.Close()
+}
+
+func txnKey(id uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, id)
+}
+
+// txnKeys returns every key a prepare touches
+func txnKeys(req *pb.NodeTxnPrepareRequest) [][]byte {
+	keys := make([][]byte, 0, len(req.GetRecords())+len(req.GetChecks()))
+	for _, rec := range req.GetRecords() {
+		keys = append(keys, rec.GetKey())
+	}
+	for _, check := range req.GetChecks() {
+		keys = append(keys, check.GetKey())
+	}
+	return keys
+}
+
+// stage records req in memory and reserves its keys; ts.mu must be held
+// or ts not yet shared
+func (ts *txnStore) stage(req *pb.NodeTxnPrepareRequest) {
+	ts.staged[req.GetTxnId()] = req
+	for _, key := range txnKeys(req) {
+		ts.reserved[string(key)] = req.GetTxnId()
+	}
+}
+
+// get returns the prepared transaction id, or nil if there is none
+func (ts *txnStore) get(id uint64) *pb.NodeTxnPrepareRequest {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return ts.staged[id]
+}
+
+// drop forgets transaction id and releases its keys
+func (ts *txnStore) drop(id uint64) error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	req, ok := ts.staged[id]
+	if !ok {
+		return nil
+	}
+	if err := ts.db.Delete(txnKey(id), syncWrite); err != nil {
+		return err
+	}
+	delete(ts.staged, id)
+	for _, key := range txnKeys(req) {
+		if ts.reserved[string(key)] == id {
+			delete(ts.reserved, string(key))
+		}
+	}
+	return nil
+}
+
+// TxnPrepare stages a transaction's writes for this node. The node refuses
+// if another transaction holds one of the keys or a key was written after
+// the version the coordinator read. Once prepared, the transaction is
+// durable and the node can no longer refuse to commit it.
+func (s *Server) TxnPrepare(ctx context.Context, req *pb.NodeTxnPrepareRequest) (*pb.NodeTxnPrepareResponse, error) {
+	id := req.GetTxnId()
+	unlock := s.lockKeys(txnKeys(req))
+	defer unlock()
+	s.txns.mu.Lock()
+	defer s.txns.mu.Unlock()
+
+	if _, ok := s.txns.staged[id]; ok {
+		return &pb.NodeTxnPrepareResponse{Prepared: true}, nil
+	}
+	for _, key := range txnKeys(req) {
+		if owner, ok := s.txns.reserved[string(key)]; ok && owner != id {
+			return &pb.NodeTxnPrepareResponse{Reason: fmt.Sprintf("key %q is held by transaction %d", key, owner)}, nil
+		}
+	}
+	for _, check := range req.GetChecks() {
+		current, found, err := s.readRecord(check.GetKey())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "node %s: prepare failed: %v", s.nodeID, err)
+		}
+		if found && current.Version > check.GetVersion() {
+			return &pb.NodeTxnPrepareResponse{Reason: fmt.Sprintf("key %q was written at version %d after it was read at %d",
+				check.GetKey(), current.Version, check.GetVersion())}, nil
+		}
+	}
+
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: encoding transaction failed: %v", s.nodeID, err)
+	}
+	if err := s.txns.db.Put(txnKey(id), data, syncWrite); err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: prepare failed: %v", s.nodeID, err)
+	}
+	s.txns.stage(req)
+	return &pb.NodeTxnPrepareResponse{Prepared: true}, nil
+}
+
+// TxnCommit applies a prepared transaction's writes and releases its keys.
+// Committing an unknown transaction succeeds, since it was already done.
+func (s *Server) TxnCommit(ctx context.Context, req *pb.NodeTxnDecision) (*pb.NodeTxnDecisionResponse, error) {
+	prepared := s.txns.get(req.GetTxnId())
+	if prepared == nil {
+		return &pb.NodeTxnDecisionResponse{}, nil
+	}
+	if _, err := s.applyBatch(prepared.GetRecords()); err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: commit failed: %v", s.nodeID, err)
+	}
+	if err := s.txns.drop(req.GetTxnId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: commit failed: %v", s.nodeID, err)
+	}
+	return &pb.NodeTxnDecisionResponse{}, nil
+}
+
+// TxnAbort drops a prepared transaction without applying it
+func (s *Server) TxnAbort(ctx context.Context, req *pb.NodeTxnDecision) (*pb.NodeTxnDecisionResponse, error) {
+	if err := s.txns.drop(req.GetTxnId()); err != nil {
+		return nil, status.Errorf(codes.Internal, "node %s: abort failed: %v", s.nodeID, err)
+	}
+	return &pb.NodeTxnDecisionResponse{}, nil
+}
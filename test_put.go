@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"log"
-	"path/filepath"
-	"strings"
+	"time"
 
+	pb "badies/proto/badiespb"
 	"badies/router"
 )
 
@@ -12,25 +14,29 @@ func main() {
 	// Create NodeManager
 	nodeManager := router.NewNodeManager()
 
-	// Define node IDs and paths
+	// Define node IDs and the addresses of their storage node processes
 	nodeIDs := []string{"node1", "node2", "node3", "node4", "node5"}
-	for _, nodeID := range nodeIDs {
-		dbPath := filepath.Join("dbs", nodeID)
-		err := nodeManager.AddNode(nodeID, dbPath)
+	for i, nodeID := range nodeIDs {
+		addr := fmt.Sprintf("localhost:%d", 5001+i)
+		err := nodeManager.AddNode(nodeID, addr)
 		if err != nil {
 			log.Fatalf("Failed to add node %s: %v", nodeID, err)
 		}
 	}
 
 	// Create a hash ring and add nodes
-	ring := router.NewHashRing(3) // 3 replicas per key
+	ring := router.NewHashRing(3, 3) // 3 vnodes per node, 3 replicas per key
 	for _, nodeID := range nodeIDs {
 		ring.AddNode(nodeID)
 	}
 
-	// Key-value to insert
+	// Key-value to insert, versioned like the server does
 	key := "hello"
 	value := []byte("world")
+	version := uint64(time.Now().UnixNano())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
 
 	// Get target nodes from hashring
 	targetNodes := ring.GetNodes(key)
@@ -38,24 +44,22 @@ func main() {
 
 	// Store key-value pair to each node
 	for _, nodeID := range targetNodes {
-		realNodeID := strings.Split(nodeID, "#")[0] // strip replica info
-
-		db, err := nodeManager.GetDB(realNodeID)
+		client, err := nodeManager.GetClient(nodeID)
 		if err != nil {
-			log.Printf("Failed to get DB for node %s: %v", realNodeID, err)
+			log.Printf("Failed to get client for node %s: %v", nodeID, err)
 			continue
 		}
 
-		err = db.Put([]byte(key), value, nil)
+		_, err = client.Put(ctx, &pb.NodePutRequest{Key: []byte(key), Value: value, Version: version})
 		if err != nil {
-			log.Printf("Error writing key '%s' to node %s: %v", key, realNodeID, err)
+			log.Printf("Error writing key '%s' to node %s: %v", key, nodeID, err)
 		} else {
-			log.Printf("Successfully wrote key '%s' to node %s", key, realNodeID)
+			log.Printf("Successfully wrote key '%s' to node %s", key, nodeID)
 		}
 	}
 
-	// Close all DBs
+	// Close all node connections
 	if err := nodeManager.Close(); err != nil {
-		log.Fatalf("Failed to close databases: %v", err)
+		log.Fatalf("Failed to close node connections: %v", err)
 	}
 }